@@ -0,0 +1,119 @@
+package audit
+
+import (
+	"errors"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// Operation names that are passed to the audit log callback.
+const (
+	OpSet    = "set"
+	OpDelete = "delete"
+)
+
+// LogFunc is called for every successful mutation of the wrapped store.
+// For OpSet, value contains the value marshalled with the codec that was passed to NewStore,
+// for OpDelete it's nil.
+// The callback is called synchronously, so it should return quickly.
+// It can for example write the record to another (append-only) gokv.Store.
+type LogFunc func(op, key string, value []byte, ts time.Time)
+
+// Store is a gokv.Store implementation that forwards all calls to another store
+// and reports each Set and Delete to an audit log callback.
+type Store struct {
+	inner    gokv.Store
+	auditLog LogFunc
+	codec    encoding.Codec
+}
+
+// Set stores the given value for the given key in the inner store.
+// If that's successful, the audit log callback is called with the marshalled value.
+// If the inner store implements gokv.RawStore, the value is only marshalled once
+// and the exact bytes that are passed to the callback are stored.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	// Marshal before writing, so a value that can't be logged isn't stored either.
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	if rawStore, ok := s.inner.(gokv.RawStore); ok {
+		err = rawStore.SetRaw(k, data)
+	} else {
+		err = s.inner.Set(k, v)
+	}
+	if err != nil {
+		return err
+	}
+	s.auditLog(OpSet, k, data, time.Now())
+	return nil
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// Reads are not reported to the audit log callback.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	return s.inner.Get(k, v)
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// If that's successful, the audit log callback is called.
+// Deleting a non-existing key-value pair does NOT lead to an error,
+// but it's still reported to the audit log callback.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	if err := s.inner.Delete(k); err != nil {
+		return err
+	}
+	s.auditLog(OpDelete, k, nil, time.Now())
+	return nil
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// NewStore creates a new audit store that wraps the given store.
+// auditLog is called after every successful Set and Delete.
+// codec must be the codec that the inner store uses, so that the values that are passed to auditLog
+// are marshalled the same way as the stored ones. If it's nil, encoding.JSON is used,
+// which is the default of all store implementations in this repository.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, auditLog LogFunc, codec encoding.Codec) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if inner == nil {
+		return result, errors.New("The inner store must not be nil")
+	}
+	if auditLog == nil {
+		return result, errors.New("The audit log callback must not be nil")
+	}
+
+	// Set default values
+	if codec == nil {
+		codec = encoding.JSON
+	}
+
+	result.inner = inner
+	result.auditLog = auditLog
+	result.codec = codec
+
+	return result, nil
+}
@@ -0,0 +1,183 @@
+package audit_test
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/audit"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/test"
+)
+
+type record struct {
+	op    string
+	key   string
+	value []byte
+	ts    time.Time
+}
+
+type recorder struct {
+	lock    sync.Mutex
+	records []record
+}
+
+func (r *recorder) log(op, key string, value []byte, ts time.Time) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.records = append(r.records, record{op, key, value, ts})
+}
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+func TestStore(t *testing.T) {
+	store := createStore(t, new(recorder))
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := createStore(t, new(recorder))
+	test.TestTypes(store, t)
+}
+
+// TestAuditLog tests that Set and Delete are reported to the callback, but Get isn't.
+func TestAuditLog(t *testing.T) {
+	r := new(recorder)
+	store := createStore(t, r)
+	before := time.Now()
+
+	err := store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Delete("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(r.records) != 2 {
+		t.Fatalf("Expected 2 audit records, but got %v", len(r.records))
+	}
+	setRecord := r.records[0]
+	if setRecord.op != audit.OpSet || setRecord.key != "foo" {
+		t.Errorf("Expected op %q and key %q, but was %q and %q", audit.OpSet, "foo", setRecord.op, setRecord.key)
+	}
+	if string(setRecord.value) != `{"Bar":"baz"}` {
+		t.Errorf("Unexpected value in audit record: %s", setRecord.value)
+	}
+	deleteRecord := r.records[1]
+	if deleteRecord.op != audit.OpDelete || deleteRecord.key != "foo" {
+		t.Errorf("Expected op %q and key %q, but was %q and %q", audit.OpDelete, "foo", deleteRecord.op, deleteRecord.key)
+	}
+	if deleteRecord.value != nil {
+		t.Errorf("Expected no value in delete audit record, but was: %s", deleteRecord.value)
+	}
+	for _, rec := range r.records {
+		if rec.ts.Before(before) {
+			t.Errorf("Audit record timestamp %v is before the operation", rec.ts)
+		}
+	}
+}
+
+// TestErrors tests that invalid calls aren't reported to the callback.
+func TestErrors(t *testing.T) {
+	r := new(recorder)
+	store := createStore(t, r)
+
+	err := store.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Set("foo", nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	if len(r.records) != 0 {
+		t.Errorf("Expected no audit records, but got %v", len(r.records))
+	}
+}
+
+// TestCodec tests that the audit log callback gets the value marshalled with the inner store's codec,
+// and that these are the stored bytes if the inner store implements gokv.RawStore.
+func TestCodec(t *testing.T) {
+	inner := gomap.NewStore(gomap.Options{Codec: encoding.Gob})
+	r := new(recorder)
+	store, err := audit.NewStore(inner, r.log, encoding.Gob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := test.Foo{Bar: "baz"}
+	err = store.Set("foo", expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, found, err := inner.GetRaw("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if len(r.records) != 1 || !bytes.Equal(r.records[0].value, stored) {
+		t.Errorf("Expected the audit record to contain the stored bytes %x, but the records were: %v", stored, r.records)
+	}
+	actual := test.Foo{}
+	err = encoding.Gob.Unmarshal(r.records[0].value, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != expected {
+		t.Errorf("Expected %+v, but was %+v", expected, actual)
+	}
+
+	// Without gokv.RawStore the value is passed to the inner store as it is
+	store, err = audit.NewStore(nonRawStore{inner}, r.log, encoding.Gob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.TestStore(store, t)
+}
+
+// TestNewStore tests that invalid parameters lead to errors.
+func TestNewStore(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	_, err := audit.NewStore(nil, new(recorder).log, nil)
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+	_, err = audit.NewStore(inner, nil, nil)
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+}
+
+// nonRawStore hides that the embedded store implements gokv.RawStore.
+type nonRawStore struct {
+	gokv.Store
+}
+
+func createStore(t *testing.T, r *recorder) gokv.Store {
+	inner := gomap.NewStore(gomap.Options{Codec: encoding.JSON})
+	store, err := audit.NewStore(inner, r.log, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return store
+}
@@ -0,0 +1,4 @@
+/*
+Package audit contains a `gokv.Store` wrapper that reports every mutation to an audit log callback.
+*/
+package audit
@@ -3,14 +3,20 @@ package badgerdb
 import (
 	"github.com/dgraph-io/badger"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
 // Store is a gokv.Store implementation for BadgerDB.
 type Store struct {
-	db    *badger.DB
-	codec encoding.Codec
+	db *badger.DB
+	// True if the DB was passed via the options, so it's not closed by the store.
+	sharedDB  bool
+	keyPrefix string
+	codec     encoding.Codec
+	// Runs the value log GC when started via StartMaintenance.
+	maintenance *util.Maintenance
 }
 
 // Set stores the given value for the given key.
@@ -28,7 +34,7 @@ func (s Store) Set(k string, v any) error {
 	}
 
 	err = s.db.Update(func(txn *badger.Txn) error {
-		return txn.Set([]byte(k), data)
+		return txn.Set([]byte(s.keyPrefix+k), data)
 	})
 	if err != nil {
 		return err
@@ -49,7 +55,7 @@ func (s Store) Get(k string, v any) (found bool, err error) {
 
 	var data []byte
 	err = s.db.View(func(txn *badger.Txn) error {
-		item, err := txn.Get([]byte(k))
+		item, err := txn.Get([]byte(s.keyPrefix + k))
 		if err != nil {
 			return err
 		}
@@ -82,13 +88,62 @@ func (s Store) Delete(k string) error {
 	}
 
 	return s.db.Update(func(txn *badger.Txn) error {
-		return txn.Delete([]byte(k))
+		return txn.Delete([]byte(s.keyPrefix + k))
 	})
 }
 
+// ForEach calls fn for each key that starts with the given prefix.
+// The keys are collected in a read-only transaction before fn is called,
+// so the transaction isn't kept open while fn is running.
+// If fn returns an error, the iteration stops and the error is returned.
+func (s Store) ForEach(prefix string, fn func(k string) error) error {
+	var keys []string
+	err := s.db.View(func(txn *badger.Txn) error {
+		iterOptions := badger.DefaultIteratorOptions
+		// Only the keys are required
+		iterOptions.PrefetchValues = false
+		iter := txn.NewIterator(iterOptions)
+		defer iter.Close()
+		prefixBytes := []byte(s.keyPrefix + prefix)
+		for iter.Seek(prefixBytes); iter.ValidForPrefix(prefixBytes); iter.Next() {
+			// Converting to a string copies the key, which is only valid until the next call of Next()
+			keys = append(keys, string(iter.Item().Key()[len(s.keyPrefix):]))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if err := fn(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Kind returns gokv.KindBadgerDB.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindBadgerDB
+}
+
+// Sync syncs the value log and the write-ahead log to disk.
+// This is only needed when the DB was opened with SyncWrites set to false,
+// for example via Options.DB, because otherwise each write is synced anyway.
+func (s Store) Sync() error {
+	return s.db.Sync()
+}
+
 // Close closes the store.
 // It must be called to make sure that all pending updates make their way to disk.
+// If the DB was passed via the options, it's not closed, because other stores might still use it.
+// A maintenance that was started via StartMaintenance is stopped.
 func (s Store) Close() error {
+	s.maintenance.Stop()
+	if s.sharedDB {
+		return nil
+	}
 	return s.db.Close()
 }
 
@@ -97,16 +152,27 @@ type Options struct {
 	// Directory for storing the DB files.
 	// Optional ("BadgerDB" by default).
 	Dir string
+	// Prefix that's added to all keys in the DB, which makes the store a logical namespace,
+	// for example when multiple stores share one DB.
+	// Optional ("" by default).
+	KeyPrefix string
+	// An already opened DB, for example to share one DB between multiple stores with different KeyPrefix values,
+	// instead of opening many DBs.
+	// The store doesn't close the DB in this case, that's up to the caller.
+	// Dir is ignored if DB is set.
+	// Optional (nil by default).
+	DB *badger.DB
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// Dir: "BadgerDB", Codec: encoding.JSON
+// Dir: "BadgerDB", KeyPrefix: "", DB: nil, Codec: encoding.JSON
 var DefaultOptions = Options{
 	Dir:   "BadgerDB",
 	Codec: encoding.JSON,
+	// No need to set KeyPrefix or DB because their zero values are fine.
 }
 
 // NewStore creates a new BadgerDB store.
@@ -127,14 +193,21 @@ func NewStore(options Options) (Store, error) {
 
 	// Open the Badger database located in the options.Dir directory.
 	// It will be created if it doesn't exist.
-	opts := badger.DefaultOptions(options.Dir)
-	db, err := badger.Open(opts)
-	if err != nil {
-		return result, err
+	db := options.DB
+	if db == nil {
+		opts := badger.DefaultOptions(options.Dir)
+		var err error
+		db, err = badger.Open(opts)
+		if err != nil {
+			return result, err
+		}
 	}
 
 	result.db = db
+	result.sharedDB = options.DB != nil
+	result.keyPrefix = options.KeyPrefix
 	result.codec = options.Codec
+	result.maintenance = util.NewMaintenance()
 
 	return result, nil
 }
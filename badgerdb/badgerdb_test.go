@@ -5,6 +5,9 @@ import (
 	"log"
 	"os"
 	"testing"
+	"time"
+
+	"github.com/dgraph-io/badger"
 
 	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/badgerdb"
@@ -47,6 +50,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestEmptyValues(store, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		store, path := createStore(t, test.BytesCodec{})
+		defer cleanUp(store, path)
+		test.TestEmptyValues(store, t)
+	})
+}
+
 // TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
 // The store works with a single file, so everything should be locked properly.
 // The locking is implemented in the BadgerDB package, but test it nonetheless.
@@ -59,6 +79,105 @@ func TestStoreConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, store)
 }
 
+// TestIterator tests if listing the keys works properly.
+func TestIterator(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+	test.TestIterator(store, t)
+}
+
+// TestTTL tests if key-value pairs expire after their TTL.
+func TestTTL(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+	test.TestTTLStore(store, t)
+}
+
+// TestSync tests if syncing works with a DB that was opened with asynchronous writes.
+func TestSync(t *testing.T) {
+	path := generateRandomTempDBpath(t)
+	defer os.RemoveAll(path)
+	db, err := badger.Open(badger.DefaultOptions(path).WithSyncWrites(false))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store := createSharedStore(t, db, "")
+	test.TestSyncer(store, t)
+}
+
+// TestSharedDB tests that two stores with different key prefixes can share one DB,
+// that they're isolated from each other and that they work concurrently.
+func TestSharedDB(t *testing.T) {
+	path := generateRandomTempDBpath(t)
+	defer os.RemoveAll(path)
+	db, err := badger.Open(badger.DefaultOptions(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storeA := createSharedStore(t, db, "a:")
+	storeB := createSharedStore(t, db, "b:")
+
+	err = storeA.Set("foo", test.Foo{Bar: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storeB.Set("foo", test.Foo{Bar: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for expected, store := range map[string]gokv.Store{"a": storeA, "b": storeB} {
+		actual := test.Foo{}
+		found, err := store.Get("foo", &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || actual.Bar != expected {
+			t.Errorf("Expected %q to be found, but found was %v and the value %q", expected, found, actual.Bar)
+		}
+		keys, err := gokv.Keys(store, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0] != "foo" {
+			t.Errorf("Expected only the store's own key, but got %v", keys)
+		}
+	}
+	err = storeA.Delete("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := storeB.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("Deleting the key in one store deleted it in the other store")
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Run("a", func(t *testing.T) {
+			t.Parallel()
+			test.TestConcurrentInteractions(t, 500, storeA)
+		})
+		t.Run("b", func(t *testing.T) {
+			t.Parallel()
+			test.TestConcurrentInteractions(t, 500, storeB)
+		})
+	})
+
+	// Closing a store doesn't close the shared DB
+	err = storeA.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storeB.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -135,6 +254,53 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestMaintenance tests that the maintenance runs after the interval and stops when the store is closed.
+func TestMaintenance(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer os.RemoveAll(path)
+	err := store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.StartMaintenance(0)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.StartMaintenance(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.StartMaintenance(10 * time.Millisecond)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	for i := 0; i < 100 && store.MaintenanceStats().Runs == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats := store.MaintenanceStats()
+	if stats.Runs == 0 {
+		t.Fatal("Expected at least one maintenance run")
+	}
+	if stats.LastErr != nil {
+		t.Error(stats.LastErr)
+	}
+	if stats.LastRun.IsZero() {
+		t.Error("Expected the time of the last run to be set")
+	}
+
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs := store.MaintenanceStats().Runs
+	time.Sleep(50 * time.Millisecond)
+	if store.MaintenanceStats().Runs != runs {
+		t.Error("Expected no maintenance runs after closing the store")
+	}
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	store, path := createStore(t, encoding.JSON)
@@ -182,6 +348,18 @@ func createStore(t *testing.T, codec encoding.Codec) (badgerdb.Store, string) {
 	return store, randPath
 }
 
+func createSharedStore(t *testing.T, db *badger.DB, keyPrefix string) badgerdb.Store {
+	options := badgerdb.Options{
+		KeyPrefix: keyPrefix,
+		DB:        db,
+	}
+	store, err := badgerdb.NewStore(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
 func generateRandomTempDBpath(t *testing.T) string {
 	path, err := ioutil.TempDir(os.TempDir(), "BadgerDB")
 	if err != nil {
@@ -21,3 +21,9 @@ require (
 	golang.org/x/net v0.23.0 // indirect
 	golang.org/x/sys v0.18.0 // indirect
 )
+
+replace (
+	github.com/philippgille/gokv => ../
+	github.com/philippgille/gokv/test => ../test
+	github.com/philippgille/gokv/util => ../util
+)
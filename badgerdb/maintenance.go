@@ -0,0 +1,41 @@
+package badgerdb
+
+import (
+	"time"
+
+	"github.com/dgraph-io/badger"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// Ratio of discardable data in a value log file above which the file is rewritten,
+// as recommended by the BadgerDB documentation.
+const gcDiscardRatio = 0.5
+
+// StartMaintenance starts a goroutine that runs the value log garbage collection of BadgerDB
+// after each interval, until Close is called.
+// BadgerDB doesn't reclaim the disk space of deleted or overwritten values on its own,
+// so this is recommended for long-running processes.
+// Each run rewrites value log files until there's no file left with enough discardable data.
+// If another store with the same DB is running the garbage collection at the same time, the run is skipped.
+// Use MaintenanceStats for information about the runs.
+// It returns an error if the interval isn't positive or if the maintenance is already running.
+func (s Store) StartMaintenance(interval time.Duration) error {
+	return s.maintenance.Start(interval, s.runValueLogGC)
+}
+
+// MaintenanceStats returns statistics about the maintenance runs so far.
+func (s Store) MaintenanceStats() util.MaintenanceStats {
+	return s.maintenance.Stats()
+}
+
+func (s Store) runValueLogGC() error {
+	for {
+		err := s.db.RunValueLogGC(gcDiscardRatio)
+		if err == badger.ErrNoRewrite || err == badger.ErrRejected {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
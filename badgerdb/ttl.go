@@ -0,0 +1,31 @@
+package badgerdb
+
+import (
+	"time"
+
+	"github.com/dgraph-io/badger"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// SetWithTTL stores the given value for the given key, like Set,
+// but with BadgerDB's native expiry.
+// After the TTL, Get returns (false, nil) for the key.
+// The key must not be "", the value must not be nil and the TTL must be positive.
+func (s Store) SetWithTTL(k string, v any, ttl time.Duration) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(txn *badger.Txn) error {
+		return txn.SetEntry(badger.NewEntry([]byte(s.keyPrefix+k), data).WithTTL(ttl))
+	})
+}
@@ -0,0 +1,24 @@
+package gokv
+
+// BatchStore is an optional interface for stores that can work with multiple key-value pairs at once,
+// which is much faster than calling Set, Get or Delete in a loop when each call is a round trip to a server.
+// Check for it with a type assertion, or use util.WrapBatchStore,
+// which loops over the regular methods for stores that don't implement it.
+//
+// The operations are not atomic. When an error occurs, some of the key-value pairs might have been processed already.
+type BatchStore interface {
+	Store
+	// SetMany stores the given values for the given keys.
+	// The keys must not be "" and the values must not be nil.
+	SetMany(values map[string]any) error
+	// GetMany retrieves the stored values for the given keys.
+	// values must be a slice with the same length as keys, for example a []any or []*Foo,
+	// with each element being a non-nil pointer that the value of the key at the same index is unmarshalled into.
+	// found contains whether a value was found for the key at the same index.
+	// The keys must not be "".
+	GetMany(keys []string, values any) (found []bool, err error)
+	// DeleteMany deletes the stored values for the given keys.
+	// Deleting non-existing key-value pairs does NOT lead to an error.
+	// The keys must not be "".
+	DeleteMany(keys []string) error
+}
@@ -1,15 +1,22 @@
 package bbolt
 
 import (
+	"bytes"
+	"errors"
+
 	bolt "go.etcd.io/bbolt"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
 // Store is a gokv.Store implementation for bbolt (formerly known as Bolt / Bolt DB).
 type Store struct {
-	db         *bolt.DB
+	db *bolt.DB
+	// True if the DB was passed via the options, so it's not closed by the store.
+	sharedDB   bool
+	readOnly   bool
 	bucketName string
 	codec      encoding.Codec
 }
@@ -17,10 +24,14 @@ type Store struct {
 // Set stores the given value for the given key.
 // Values are automatically marshalled to JSON or gob (depending on the configuration).
 // The key must not be "" and the value must not be nil.
+// In read-only mode it returns gokv.ErrReadOnly.
 func (s Store) Set(k string, v any) error {
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return err
 	}
+	if s.readOnly {
+		return gokv.ErrReadOnly
+	}
 
 	// First turn the passed object into something that bbolt can handle
 	data, err := s.codec.Marshal(v)
@@ -79,10 +90,14 @@ func (s Store) Get(k string, v any) (found bool, err error) {
 // Delete deletes the stored value for the given key.
 // Deleting a non-existing key-value pair does NOT lead to an error.
 // The key must not be "".
+// In read-only mode it returns gokv.ErrReadOnly.
 func (s Store) Delete(k string) error {
 	if err := util.CheckKey(k); err != nil {
 		return err
 	}
+	if s.readOnly {
+		return gokv.ErrReadOnly
+	}
 
 	return s.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(s.bucketName))
@@ -90,9 +105,53 @@ func (s Store) Delete(k string) error {
 	})
 }
 
+// ForEach calls fn for each key that starts with the given prefix.
+// The keys are collected in a read-only transaction before fn is called,
+// so fn can use the store without deadlocking with the open transaction.
+// If fn returns an error, the iteration stops and the error is returned.
+func (s Store) ForEach(prefix string, fn func(k string) error) error {
+	var keys []string
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket([]byte(s.bucketName)).Cursor()
+		prefixBytes := []byte(prefix)
+		// The keys are sorted, so all matching keys follow the first one.
+		for k, _ := c.Seek(prefixBytes); k != nil && bytes.HasPrefix(k, prefixBytes); k, _ = c.Next() {
+			// Converting to a string copies the key, which is only valid during the transaction
+			keys = append(keys, string(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		if err := fn(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Kind returns gokv.KindBbolt.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindBbolt
+}
+
+// Sync syncs the DB file to disk (fdatasync).
+// Each write transaction is synced when it's committed anyway,
+// so this is only needed when the DB was opened with NoSync, for example via Options.DB.
+func (s Store) Sync() error {
+	return s.db.Sync()
+}
+
 // Close closes the store.
 // It must be called to make sure that all open transactions finish and to release all DB resources.
+// If the DB was passed via the options, it's not closed, because other stores might still use it.
 func (s Store) Close() error {
+	if s.sharedDB {
+		return nil
+	}
 	return s.db.Close()
 }
 
@@ -104,17 +163,32 @@ type Options struct {
 	// Path of the DB file.
 	// Optional ("bbolt.db" by default).
 	Path string
+	// An already opened DB, for example to share one DB file between multiple stores with different BucketName values,
+	// which isn't possible by opening the file multiple times, because bbolt locks it.
+	// The store doesn't close the DB in this case, that's up to the caller.
+	// Path is ignored if DB is set.
+	// Optional (nil by default).
+	DB *bolt.DB
+	// Opens the DB file read-only, which only takes a shared lock on the file,
+	// so that multiple read-only processes can use it at the same time.
+	// Opening blocks while another process has the file opened for writing though.
+	// The bucket isn't created in this mode, so it must exist already.
+	// Set and Delete return gokv.ErrReadOnly.
+	// If DB is set, the store is read-only if either this is true or the DB was opened read-only.
+	// Optional (false by default).
+	ReadOnly bool
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// BucketName: "default", Path: "bbolt.db", Codec: encoding.JSON
+// BucketName: "default", Path: "bbolt.db", DB: nil, ReadOnly: false, Codec: encoding.JSON
 var DefaultOptions = Options{
 	BucketName: "default",
 	Path:       "bbolt.db",
 	Codec:      encoding.JSON,
+	// No need to set DB and ReadOnly because their zero values are fine.
 }
 
 // NewStore creates a new bbolt store.
@@ -137,25 +211,51 @@ func NewStore(options Options) (Store, error) {
 	}
 
 	// Open DB
-	db, err := bolt.Open(options.Path, 0600, nil)
-	if err != nil {
-		return result, err
-	}
-
-	// Create a bucket if it doesn't exist yet.
-	// In bbolt key/value pairs are stored to and read from buckets.
-	err = db.Update(func(tx *bolt.Tx) error {
-		_, err := tx.CreateBucketIfNotExists([]byte(options.BucketName))
+	db := options.DB
+	if db == nil {
+		var err error
+		var boltOptions *bolt.Options
+		if options.ReadOnly {
+			boltOptions = &bolt.Options{ReadOnly: true}
+		}
+		db, err = bolt.Open(options.Path, 0600, boltOptions)
 		if err != nil {
-			return err
+			return result, err
 		}
-		return nil
-	})
+	}
+	readOnly := options.ReadOnly || db.IsReadOnly()
+
+	var err error
+	if readOnly {
+		// The bucket can't be created, so it must exist already.
+		err = db.View(func(tx *bolt.Tx) error {
+			if tx.Bucket([]byte(options.BucketName)) == nil {
+				return errors.New("The bucket " + options.BucketName + " doesn't exist, which is required in read-only mode")
+			}
+			return nil
+		})
+	} else {
+		// Create a bucket if it doesn't exist yet.
+		// In bbolt key/value pairs are stored to and read from buckets.
+		err = db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists([]byte(options.BucketName))
+			if err != nil {
+				return err
+			}
+			return nil
+		})
+	}
 	if err != nil {
+		// Don't leave the file locked
+		if options.DB == nil {
+			_ = db.Close()
+		}
 		return result, err
 	}
 
 	result.db = db
+	result.sharedDB = options.DB != nil
+	result.readOnly = readOnly
 	result.bucketName = options.BucketName
 	result.codec = options.Codec
 
@@ -1,11 +1,14 @@
 package bbolt_test
 
 import (
+	"errors"
 	"io/ioutil"
 	"log"
 	"os"
 	"testing"
 
+	bolt "go.etcd.io/bbolt"
+
 	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/bbolt"
 	"github.com/philippgille/gokv/encoding"
@@ -47,6 +50,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestEmptyValues(store, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		store, path := createStore(t, test.BytesCodec{})
+		defer cleanUp(store, path)
+		test.TestEmptyValues(store, t)
+	})
+}
+
 // TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
 // The store works with a single file, so everything should be locked properly.
 // The locking is implemented in the bbolt package, but test it nonetheless.
@@ -59,6 +79,147 @@ func TestStoreConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, store)
 }
 
+// TestIterator tests if listing the keys works properly.
+func TestIterator(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+	test.TestIterator(store, t)
+}
+
+// TestSync tests if syncing works with a DB that was opened without syncing each commit.
+func TestSync(t *testing.T) {
+	path := generateRandomTempDbPath(t)
+	defer os.RemoveAll(path)
+	db, err := bolt.Open(path, 0600, &bolt.Options{NoSync: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	store := createSharedStore(t, db, "default")
+	test.TestSyncer(store, t)
+}
+
+// TestSharedDB tests that two stores with different bucket names can share one DB,
+// that they're isolated from each other and that they work concurrently.
+func TestSharedDB(t *testing.T) {
+	path := generateRandomTempDbPath(t)
+	defer os.RemoveAll(path)
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storeA := createSharedStore(t, db, "a")
+	storeB := createSharedStore(t, db, "b")
+
+	err = storeA.Set("foo", test.Foo{Bar: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storeB.Set("foo", test.Foo{Bar: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for expected, store := range map[string]gokv.Store{"a": storeA, "b": storeB} {
+		actual := test.Foo{}
+		found, err := store.Get("foo", &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || actual.Bar != expected {
+			t.Errorf("Expected %q to be found, but found was %v and the value %q", expected, found, actual.Bar)
+		}
+		keys, err := gokv.Keys(store, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0] != "foo" {
+			t.Errorf("Expected only the store's own key, but got %v", keys)
+		}
+	}
+	err = storeA.Delete("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := storeB.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("Deleting the key in one store deleted it in the other store")
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Run("a", func(t *testing.T) {
+			t.Parallel()
+			test.TestConcurrentInteractions(t, 500, storeA)
+		})
+		t.Run("b", func(t *testing.T) {
+			t.Parallel()
+			test.TestConcurrentInteractions(t, 500, storeB)
+		})
+	})
+
+	// Closing a store doesn't close the shared DB
+	err = storeA.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storeB.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// TestReadOnly tests that a read-only store can read the existing values, but not write.
+func TestReadOnly(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer os.RemoveAll(path)
+	err := store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Release the exclusive lock
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	options := bbolt.Options{
+		Path:     path,
+		ReadOnly: true,
+	}
+	readOnlyStore, err := bbolt.NewStore(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer readOnlyStore.Close()
+
+	actual := test.Foo{}
+	found, err := readOnlyStore.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+	err = readOnlyStore.Set("foo", test.Foo{Bar: "qux"})
+	if !errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected gokv.ErrReadOnly, but was %v", err)
+	}
+	err = readOnlyStore.Delete("foo")
+	if !errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected gokv.ErrReadOnly, but was %v", err)
+	}
+
+	// The bucket isn't created in read-only mode
+	options.BucketName = "other"
+	_, err = bbolt.NewStore(options)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -181,6 +342,18 @@ func createStore(t *testing.T, codec encoding.Codec) (bbolt.Store, string) {
 	return store, path
 }
 
+func createSharedStore(t *testing.T, db *bolt.DB, bucketName string) bbolt.Store {
+	options := bbolt.Options{
+		BucketName: bucketName,
+		DB:         db,
+	}
+	store, err := bbolt.NewStore(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
 func generateRandomTempDbPath(t *testing.T) string {
 	path, err := ioutil.TempDir(os.TempDir(), "bbolt")
 	if err != nil {
@@ -14,3 +14,8 @@ require (
 	github.com/go-test/deep v1.1.0 // indirect
 	golang.org/x/sys v0.4.0 // indirect
 )
+
+replace (
+	github.com/philippgille/gokv => ../
+	github.com/philippgille/gokv/test => ../test
+)
@@ -2,10 +2,12 @@ package bigcache
 
 import (
 	"context"
+	"math"
 	"time"
 
 	"github.com/allegro/bigcache/v3"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -72,6 +74,11 @@ func (s Store) Delete(k string) error {
 	return nil
 }
 
+// Kind returns gokv.KindBigCache.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindBigCache
+}
+
 // Close closes the store.
 // When called, the cache is left for removal by the garbage collector.
 func (s Store) Close() error {
@@ -79,27 +86,46 @@ func (s Store) Close() error {
 }
 
 // Options are the options for the BigCache store.
+//
+// Unlike with the persistent stores, entries can be evicted by BigCache,
+// either because they're older than the Eviction time ("LifeWindow" in BigCache)
+// or because the HardMaxCacheSize is reached, in which case the oldest entries are overwritten.
+// The age of an entry is the time since it was last Set, reading it doesn't renew it.
 type Options struct {
 	// The maximum size of the cache in MiB.
 	// 0 means no limit.
 	// Optional (0 by default, meaning no limit).
 	HardMaxCacheSize int
-	// Time after which an entry can be evicted.
+	// Time after which an entry can be evicted ("LifeWindow" in BigCache).
+	// BigCache has a precision of one second.
 	// 0 means no eviction.
 	// When this is set to 0 and HardMaxCacheSize is set to a non-zero value
 	// and the maximum capacity of the cache is reached
 	// the oldest entries will be evicted nonetheless when new ones are stored.
 	// Optional (0 by default, meaning no eviction).
 	Eviction time.Duration
+	// Interval in which entries that are older than the Eviction time are removed.
+	// Entries that are older than that are also removed when they're the oldest entry of a shard
+	// while a new entry is stored, but Get still returns them until then.
+	// Only used when Eviction is > 0.
+	// Optional (1s by default).
+	CleanWindow time.Duration
+	// Number of shards, which must be a power of two.
+	// More shards reduce the lock contention with concurrent access,
+	// but each shard takes some memory, even if it's empty.
+	// Optional (1024 by default).
+	Shards int
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// HardMaxCacheSize: 0 (no limit), Eviction: 0 (no limit), Codec: encoding.JSON
+// HardMaxCacheSize: 0 (no limit), Eviction: 0 (no limit), CleanWindow: 1s, Shards: 1024, Codec: encoding.JSON
 var DefaultOptions = Options{
-	Codec: encoding.JSON,
+	CleanWindow: time.Second,
+	Shards:      1024,
+	Codec:       encoding.JSON,
 	// No need to set Eviction or HardMaxCacheSize because their zero values are fine.
 }
 
@@ -110,11 +136,25 @@ func NewStore(options Options) (Store, error) {
 	result := Store{}
 
 	// Set default options
+	if options.CleanWindow == 0 {
+		options.CleanWindow = DefaultOptions.CleanWindow
+	}
+	if options.Shards == 0 {
+		options.Shards = DefaultOptions.Shards
+	}
 	if options.Codec == nil {
 		options.Codec = DefaultOptions.Codec
 	}
 
 	config := bigcache.DefaultConfig(options.Eviction)
+	config.CleanWindow = options.CleanWindow
+	if options.Eviction == 0 {
+		// For BigCache a LifeWindow of 0 means that entries are evicted after one second,
+		// so the longest possible one is used instead, and there's nothing to clean up.
+		config.LifeWindow = math.MaxInt64
+		config.CleanWindow = 0
+	}
+	config.Shards = options.Shards
 	config.HardMaxCacheSize = options.HardMaxCacheSize
 	cache, err := bigcache.New(context.Background(), config)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"math/rand"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/philippgille/gokv/bigcache"
 	"github.com/philippgille/gokv/encoding"
@@ -44,6 +45,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, encoding.JSON)
+		defer store.Close()
+		test.TestEmptyValues(store, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		store := createStore(t, test.BytesCodec{})
+		defer store.Close()
+		test.TestEmptyValues(store, t)
+	})
+}
+
 // TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
 func TestStoreConcurrent(t *testing.T) {
 	store := createStore(t, encoding.JSON)
@@ -139,6 +157,69 @@ func TestClose(t *testing.T) {
 	}
 }
 
+// TestNoEviction tests that entries aren't evicted when no eviction time is set.
+// It takes a few seconds, because BigCache has a precision of one second.
+func TestNoEviction(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	defer store.Close()
+
+	err := store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Let the clean up run and the entry become older than one second
+	time.Sleep(2500 * time.Millisecond)
+	// Storing another entry checks if the oldest entry is expired
+	err = store.Set("baz", "qux")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := store.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("The value was evicted, but no eviction time was set")
+	}
+}
+
+// TestEviction tests that entries are removed after the eviction time.
+// It takes a few seconds, because BigCache has a precision of one second.
+func TestEviction(t *testing.T) {
+	options := bigcache.Options{
+		Eviction:    time.Second,
+		CleanWindow: 500 * time.Millisecond,
+		Shards:      16,
+	}
+	store, err := bigcache.NewStore(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+
+	err = store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(3 * time.Second)
+
+	found, err := store.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found after the eviction time, but no value was expected")
+	}
+
+	// The number of shards must be a power of two
+	options.Shards = 3
+	_, err = bigcache.NewStore(options)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
 // TestEvictionOnMaxSize tests if entries are evicted when the max size is reached when NO eviction time is set.
 func TestEvictionOnMaxSize(t *testing.T) {
 	// Test with small max size (1 MiB) and eviction of 0
@@ -0,0 +1,229 @@
+package blobstorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+var defaultTimeout = 2 * time.Second
+
+// Client is a gokv.Store implementation for Azure Blob Storage.
+type Client struct {
+	c             *azblob.Client
+	containerName string
+	keyPrefix     string
+	timeOut       time.Duration
+	codec         encoding.Codec
+}
+
+// Set stores the given value for the given key.
+// Values are automatically marshalled to JSON or gob (depending on the configuration).
+// The key must not be "" and the value must not be nil.
+func (c Client) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	// First turn the passed object into something that Blob Storage can handle.
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	_, err = c.c.UploadBuffer(tctx, c.containerName, c.keyPrefix+k, data, nil)
+	return err
+}
+
+// Get retrieves the stored value for the given key.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (c Client) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	res, err := c.c.DownloadStream(tctx, c.containerName, c.keyPrefix+k, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return true, err
+	}
+
+	return true, c.codec.Unmarshal(data, v)
+}
+
+// ModTime returns the time when the blob of the given key was last modified.
+// It only retrieves the blob's properties, not the value.
+// If no value is found it returns (time.Time{}, false, nil).
+// The key must not be "".
+func (c Client) ModTime(k string) (modTime time.Time, found bool, err error) {
+	if err := util.CheckKey(k); err != nil {
+		return time.Time{}, false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	blobClient := c.c.ServiceClient().NewContainerClient(c.containerName).NewBlobClient(c.keyPrefix + k)
+	res, err := blobClient.GetProperties(tctx, nil)
+	if err != nil {
+		if bloberror.HasCode(err, bloberror.BlobNotFound) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+	if res.LastModified == nil {
+		return time.Time{}, true, errors.New("Blob Storage didn't report a modification time")
+	}
+
+	return *res.LastModified, true, nil
+}
+
+// Delete deletes the stored value for the given key.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (c Client) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	_, err := c.c.DeleteBlob(tctx, c.containerName, c.keyPrefix+k, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Kind returns gokv.KindBlobStorage.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindBlobStorage
+}
+
+// Close closes the client.
+// In the Blob Storage implementation this doesn't have any effect.
+func (c Client) Close() error {
+	return nil
+}
+
+// Options are the options for the Blob Storage client.
+type Options struct {
+	// Connection string.
+	// Can be either a normal connection string like this:
+	// "DefaultEndpointsProtocol=https;AccountName=foo;AccountKey=abc123==;EndpointSuffix=core.windows.net".
+	// Or a "Shared Access Signature". In this case the connection string must contain a "BlobEndpoint".
+	// For the Azurite emulator it's the well-known development storage connection string,
+	// see https://github.com/Azure/Azurite/blob/v3.17.1/README.md#connection-strings.
+	// Either ConnectionString or AccountName and AccountKey must be set.
+	ConnectionString string
+	// Name of the storage account.
+	// Only used when ConnectionString is empty.
+	AccountName string
+	// Access key of the storage account.
+	// Only used when ConnectionString is empty.
+	AccountKey string
+	// Name of the container.
+	// The container is automatically created if it doesn't exist yet.
+	// Optional ("gokv" by default).
+	ContainerName string
+	// Prefix for the blob names, for example "gokv/".
+	// This allows multiple stores to share one container.
+	// Optional ("" by default).
+	KeyPrefix string
+	// The timeout for operations.
+	// Optional (2 * time.Second by default).
+	Timeout *time.Duration
+	// Encoding format.
+	// Optional (encoding.JSON by default).
+	Codec encoding.Codec
+}
+
+// DefaultOptions is an Options object with default values.
+// ContainerName: "gokv", KeyPrefix: "", Timeout: 2 * time.Second, Codec: encoding.JSON
+var DefaultOptions = Options{
+	ContainerName: "gokv",
+	Timeout:       &defaultTimeout,
+	Codec:         encoding.JSON,
+	// No need to set KeyPrefix because its Go zero value is fine.
+}
+
+// NewClient creates a new Blob Storage client.
+//
+// Compared to Table Storage, Blob Storage is better suited for larger values,
+// because the size of Table Storage entity properties is limited to 64 KiB.
+func NewClient(options Options) (Client, error) {
+	result := Client{}
+
+	// Precondition check
+	if options.ConnectionString == "" && (options.AccountName == "" || options.AccountKey == "") {
+		return result, errors.New("Either the ConnectionString or the AccountName and AccountKey of the passed options must be set")
+	}
+
+	// Set default values
+	if options.ContainerName == "" {
+		options.ContainerName = DefaultOptions.ContainerName
+	}
+	if options.Timeout == nil {
+		options.Timeout = DefaultOptions.Timeout
+	}
+	if options.Codec == nil {
+		options.Codec = DefaultOptions.Codec
+	}
+
+	var blobClient *azblob.Client
+	var err error
+	if options.ConnectionString != "" {
+		blobClient, err = azblob.NewClientFromConnectionString(options.ConnectionString, nil)
+	} else {
+		var cred *azblob.SharedKeyCredential
+		cred, err = azblob.NewSharedKeyCredential(options.AccountName, options.AccountKey)
+		if err != nil {
+			return result, err
+		}
+		serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", options.AccountName)
+		blobClient, err = azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// Create the container if it doesn't exist yet.
+	// Also serves as connection test.
+	tctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = blobClient.CreateContainer(tctx, options.ContainerName, nil)
+	if err != nil && !bloberror.HasCode(err, bloberror.ContainerAlreadyExists) {
+		return result, err
+	}
+
+	result.c = blobClient
+	result.containerName = options.ContainerName
+	result.keyPrefix = options.KeyPrefix
+	result.timeOut = *options.Timeout
+	result.codec = options.Codec
+
+	return result, nil
+}
@@ -0,0 +1,294 @@
+package blobstorage_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+
+	"github.com/philippgille/gokv/blobstorage"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/test"
+)
+
+// For the Azurite emulator this can be the standard storage emulator connection string,
+// see https://github.com/Azure/Azurite/blob/v3.17.1/README.md#connection-strings.
+// Alternatively test with a provided conn string for example for the real Azure.
+var connectionStringEnvVar = "BLOB_STORAGE_CONNECTION_STRING"
+
+// TestConnection only tests the connection to Blob Storage, allowing to work on connection options with `go test -run TestConnection .` for example.
+func TestConnection(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+}
+
+// TestClient tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+//
+// Note: This test is only executed if the initial connection to Blob Storage works.
+func TestClient(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON, "")
+		test.TestStore(client, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		client := createClient(t, encoding.Gob, "")
+		test.TestStore(client, t)
+	})
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+//
+// Note: This test is only executed if the initial connection to Blob Storage works.
+func TestTypes(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON, "")
+		test.TestTypes(client, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		client := createClient(t, encoding.Gob, "")
+		test.TestTypes(client, t)
+	})
+}
+
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON, "")
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{}, "")
+		test.TestEmptyValues(client, t)
+	})
+}
+
+// TestClientConcurrent launches a bunch of goroutines that concurrently work with the Blob Storage client.
+//
+// Note: This test is only executed if the initial connection to Blob Storage works.
+func TestClientConcurrent(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	client := createClient(t, encoding.JSON, "")
+
+	goroutineCount := 100
+
+	test.TestConcurrentInteractions(t, goroutineCount, client)
+}
+
+// TestModTime tests if the modification time of the blobs is reported.
+//
+// Note: This test is only executed if the initial connection to Blob Storage works.
+func TestModTime(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	client := createClient(t, encoding.JSON, "")
+	test.TestModTimer(client, t)
+}
+
+// TestKeyPrefix tests that two clients with different key prefixes don't see each other's values.
+//
+// Note: This test is only executed if the initial connection to Blob Storage works.
+func TestKeyPrefix(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	clientA := createClient(t, encoding.JSON, "a/")
+	clientB := createClient(t, encoding.JSON, "b/")
+
+	err := clientA.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := clientB.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+	found, err = clientA.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+}
+
+// TestErrors tests some error cases.
+//
+// Note: This test is only executed if the initial connection to Blob Storage works.
+func TestErrors(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	// Test empty key
+	client := createClient(t, encoding.JSON, "")
+	err := client.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = client.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = client.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestNil tests the behaviour when passing nil or pointers to nil values to some methods.
+//
+// Note: This test is only executed if the initial connection to Blob Storage works.
+func TestNil(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	// Test setting nil
+
+	t.Run("set nil with JSON marshalling", func(t *testing.T) {
+		client := createClient(t, encoding.JSON, "")
+		err := client.Set("foo", nil)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("set nil with Gob marshalling", func(t *testing.T) {
+		client := createClient(t, encoding.Gob, "")
+		err := client.Set("foo", nil)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	// Test passing nil or pointer to nil value for retrieval
+
+	createTest := func(codec encoding.Codec) func(t *testing.T) {
+		return func(t *testing.T) {
+			client := createClient(t, codec, "")
+
+			// Prep
+			err := client.Set("foo", test.Foo{Bar: "baz"})
+			if err != nil {
+				t.Error(err)
+			}
+
+			_, err = client.Get("foo", nil) // actually nil
+			if err == nil {
+				t.Error("An error was expected")
+			}
+
+			var i any // actually nil
+			_, err = client.Get("foo", i)
+			if err == nil {
+				t.Error("An error was expected")
+			}
+
+			var valPtr *test.Foo // nil value
+			_, err = client.Get("foo", valPtr)
+			if err == nil {
+				t.Error("An error was expected")
+			}
+		}
+	}
+	t.Run("get with nil / nil value parameter", createTest(encoding.JSON))
+	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
+}
+
+// TestClose tests if the close method returns any errors.
+//
+// Note: This test is only executed if the initial connection to Blob Storage works.
+func TestClose(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Blob Storage could be established. Probably not running in a proper test environment.")
+	}
+
+	client := createClient(t, encoding.JSON, "")
+	err := client.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// TestMissingCredentials tests that creating a client without any credentials leads to an error.
+func TestMissingCredentials(t *testing.T) {
+	_, err := blobstorage.NewClient(blobstorage.Options{AccountName: "foo"})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// checkConnection returns true if a connection could be made, false otherwise.
+func checkConnection() bool {
+	connString, found := os.LookupEnv(connectionStringEnvVar)
+	if !found {
+		fmt.Println("No connection string found in the environment variable")
+		return false
+	}
+	blobClient, err := azblob.NewClientFromConnectionString(connString, nil)
+	if err != nil {
+		fmt.Printf("Error creating blob client from connection string: %v\n", err)
+		return false
+	}
+	tctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = blobClient.NewListContainersPager(nil).NextPage(tctx)
+	if err != nil {
+		fmt.Printf("Error listing containers: %v\n", err)
+		return false
+	}
+	return true
+}
+
+func createClient(t *testing.T, codec encoding.Codec, keyPrefix string) blobstorage.Client {
+	connString, found := os.LookupEnv(connectionStringEnvVar)
+	if !found {
+		t.Fatal(errors.New("No connection string found in the environment variable"))
+	}
+	options := blobstorage.Options{
+		ConnectionString: connString,
+		KeyPrefix:        keyPrefix,
+		Codec:            codec,
+	}
+	client, err := blobstorage.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
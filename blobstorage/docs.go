@@ -0,0 +1,4 @@
+/*
+Package blobstorage contains an implementation of the `gokv.Store` interface for Azure Blob Storage.
+*/
+package blobstorage
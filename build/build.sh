@@ -10,7 +10,7 @@ echo "building gokv"
 (cd "$SCRIPT_DIR"/.. && go build -v) || (cd "$WORKING_DIR" && echo " failed" && exit 1)
 
 # Helper packages
-array=( encoding sql test util )
+array=( encoding encoding/cbor encoding/compressed encoding/msgpack sql test util )
 for MODULE_NAME in "${array[@]}"; do
     echo "building $MODULE_NAME"
     (cd "$SCRIPT_DIR"/../"$MODULE_NAME" && go build -v) || (cd "$WORKING_DIR" && echo " failed" && exit 1)
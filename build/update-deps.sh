@@ -15,7 +15,7 @@ SCRIPT_DIR="$( cd "$( dirname "${BASH_SOURCE[0]}" )" >/dev/null 2>&1 && pwd )"
 export GO111MODULE=on
 
 # Helper packages
-array=( encoding sql test util )
+array=( encoding encoding/cbor encoding/compressed encoding/msgpack sql test util )
 for MODULE_NAME in "${array[@]}"; do
     echo "updating $MODULE_NAME"
     (cd "$SCRIPT_DIR"/../"$MODULE_NAME" && go get $(go list -f '{{if not (or .Main .Indirect)}}{{.Path}}{{end}}' -m all) && go mod tidy) || (cd "$WORKING_DIR" && echo " failed" && exit 1)
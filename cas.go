@@ -0,0 +1,127 @@
+package gokv
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"time"
+)
+
+// ErrCompareAndSwapNotSupported is returned by CompareAndSwap when the store doesn't implement the CASStore interface.
+var ErrCompareAndSwapNotSupported = errors.New("The store doesn't support compare-and-swap")
+
+// ErrUpdateConflict is returned by UpdateFunc when the value was changed concurrently in each attempt.
+var ErrUpdateConflict = errors.New("The value was changed concurrently too often, so the update was given up")
+
+// Number of attempts after which UpdateFunc gives up.
+const maxUpdateAttempts = 100
+
+// The backoff between the attempts of UpdateFunc grows by the step with each attempt, up to the maximum,
+// and is randomized, so that concurrent updates don't keep conflicting with each other.
+const (
+	updateBackoffStep = time.Millisecond
+	maxUpdateBackoff  = 50 * time.Millisecond
+)
+
+// CASStore is an optional interface for stores that can atomically replace a value
+// only if it still holds an expected value, for example for leader election
+// or for updates that must not overwrite a concurrent update.
+// Stores that can't do this atomically don't implement it, so there's no silent race.
+// Check for it with a type assertion or use the CompareAndSwap function.
+type CASStore interface {
+	// CompareAndSwap stores the new value for the given key if the stored value is equal to the old value.
+	// The values are compared in their marshalled form, so the old value must be
+	// of the same type as the stored one and its marshalling must be deterministic
+	// (which isn't the case for maps with gob, for example).
+	// If the key doesn't exist or holds a different value, it returns (false, nil).
+	// The key must not be "" and the values must not be nil.
+	CompareAndSwap(k string, old, new any) (swapped bool, err error)
+}
+
+// CompareAndSwap stores the new value for the given key via the store if it implements the CASStore interface
+// and the stored value is equal to the old value.
+// Otherwise ErrCompareAndSwapNotSupported is returned.
+func CompareAndSwap(store Store, k string, old, new any) (swapped bool, err error) {
+	casStore, ok := store.(CASStore)
+	if !ok {
+		return false, ErrCompareAndSwapNotSupported
+	}
+	return casStore.CompareAndSwap(k, old, new)
+}
+
+// UpdateFunc updates the stored value for the given key with the update function,
+// via compare-and-swap if the store implements the CASStore interface.
+// Otherwise ErrCompareAndSwapNotSupported is returned.
+// The stored value is retrieved into a new value of the type of initial and passed to update,
+// or initial is passed if the key doesn't exist yet.
+// The value that update returns is stored if the stored value wasn't changed in the meantime
+// and then returned. Otherwise update is called again with the changed value,
+// so it should be free of side effects. After 100 attempts ErrUpdateConflict is returned.
+// If update returns an error, nothing is stored and the error is returned.
+// When the key doesn't exist yet, the new value is stored via SetIfAbsent,
+// so concurrent updates of a non-existing key don't overwrite each other either.
+// If the store doesn't implement the ConditionalSetter interface, ErrSetIfAbsentNotSupported is returned in that case.
+// Values are compared in their marshalled form, see CASStore.
+// The key must not be "" and initial must not be nil.
+func UpdateFunc(store Store, k string, initial any, update func(current any) (any, error)) (result any, err error) {
+	casStore, ok := store.(CASStore)
+	if !ok {
+		return nil, ErrCompareAndSwapNotSupported
+	}
+	if initial == nil {
+		return nil, errors.New("The initial value must not be nil")
+	}
+
+	valueType := reflect.TypeOf(initial)
+	for attempt := 0; attempt < maxUpdateAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(updateBackoff(attempt))
+		}
+
+		current := reflect.New(valueType)
+		found, err := store.Get(k, current.Interface())
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			setter, ok := store.(ConditionalSetter)
+			if !ok {
+				return nil, ErrSetIfAbsentNotSupported
+			}
+			result, err = update(initial)
+			if err != nil {
+				return nil, err
+			}
+			set, err := setter.SetIfAbsent(k, result)
+			if err != nil {
+				return nil, err
+			}
+			if set {
+				return result, nil
+			}
+			continue
+		}
+
+		result, err = update(current.Elem().Interface())
+		if err != nil {
+			return nil, err
+		}
+		swapped, err := casStore.CompareAndSwap(k, current.Elem().Interface(), result)
+		if err != nil {
+			return nil, err
+		}
+		if swapped {
+			return result, nil
+		}
+	}
+	return nil, ErrUpdateConflict
+}
+
+// updateBackoff returns a random duration up to the backoff for the given attempt.
+func updateBackoff(attempt int) time.Duration {
+	backoff := time.Duration(attempt) * updateBackoffStep
+	if backoff > maxUpdateBackoff {
+		backoff = maxUpdateBackoff
+	}
+	return time.Duration(rand.Int63n(int64(backoff))) + 1
+}
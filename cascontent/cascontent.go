@@ -0,0 +1,125 @@
+package cascontent
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// ErrInvalidHash is returned by GetByHash when the hash isn't a hash that Put returned.
+var ErrInvalidHash = errors.New("The hash must be a lowercase hex encoded SHA-256 hash")
+
+// Store is a content-addressable store that stores values under the hash of their marshalled form
+// in another store, so that equal values are only stored once.
+type Store struct {
+	inner gokv.Store
+	codec encoding.Codec
+}
+
+// Put marshals the given value, stores it under the SHA-256 hash of the marshalled value
+// and returns the hash (hex encoded).
+// If a value with the same hash is already stored, it's not stored again.
+// The codec must marshal deterministically for the deduplication to work,
+// which for example isn't the case for maps with gob.
+// The value must not be nil.
+func (s Store) Put(v any) (hash string, err error) {
+	if err := util.CheckVal(v); err != nil {
+		return "", err
+	}
+
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	hash = hex.EncodeToString(sum[:])
+
+	// Storing the same content concurrently isn't a problem, because the content is equal,
+	// so the existence check doesn't need to be atomic.
+	found, err := s.inner.Get(hash, new([]byte))
+	if err != nil {
+		return "", err
+	}
+	if found {
+		return hash, nil
+	}
+	if err := s.inner.Set(hash, data); err != nil {
+		return "", err
+	}
+	return hash, nil
+}
+
+// GetByHash retrieves the value that's stored for the given hash.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If the stored data doesn't match the hash anymore, an error is returned.
+// If no value is found it returns (false, nil).
+// The hash must have been returned by Put and the pointer must not be nil.
+func (s Store) GetByHash(hash string, v any) (found bool, err error) {
+	if err := util.CheckVal(v); err != nil {
+		return false, err
+	}
+	if !isValidHash(hash) {
+		return false, ErrInvalidHash
+	}
+
+	var data []byte
+	found, err = s.inner.Get(hash, &data)
+	if err != nil || !found {
+		return found, err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != hash {
+		return true, errors.New("The stored data doesn't match its hash. It was modified in the inner store")
+	}
+
+	return true, s.codec.Unmarshal(data, v)
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// isValidHash returns true if the hash has the format that Put returns, which is lowercase hex.
+func isValidHash(hash string) bool {
+	if len(hash) != 2*sha256.Size {
+		return false
+	}
+	for _, c := range hash {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// NewStore creates a new content-addressable store that stores the values marshalled with the given codec
+// in the given store.
+// The hashes are calculated from the data that the given codec creates,
+// so the same codec must be used for reading the values.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, codec encoding.Codec) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if inner == nil {
+		return result, errors.New("The inner store must not be nil")
+	}
+
+	// Set default values
+	if codec == nil {
+		codec = encoding.JSON
+	}
+
+	result.inner = inner
+	result.codec = codec
+
+	return result, nil
+}
@@ -0,0 +1,172 @@
+package cascontent_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/cascontent"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if putting values and getting them by their hash works properly.
+func TestStore(t *testing.T) {
+	for name, codec := range map[string]encoding.Codec{"JSON": encoding.JSON, "gob": encoding.Gob} {
+		t.Run(name, func(t *testing.T) {
+			store := createStore(t, gomap.NewStore(gomap.DefaultOptions), codec)
+			expected := test.Foo{Bar: "baz"}
+			hash, err := store.Put(expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(hash) != 64 {
+				t.Errorf("Expected a hex encoded SHA-256 hash, but was %q", hash)
+			}
+
+			actual := test.Foo{}
+			found, err := store.GetByHash(hash, &actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !found {
+				t.Error("No value was found, but should have been")
+			}
+			if actual != expected {
+				t.Errorf("Expected %v, but was %v", expected, actual)
+			}
+
+			// Missing value
+			found, err = store.GetByHash("0000000000000000000000000000000000000000000000000000000000000000", &actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if found {
+				t.Error("A value was found, but no value was expected")
+			}
+		})
+	}
+}
+
+// TestDeduplication tests that putting the same value twice yields the same hash and only stores it once,
+// while a different value yields a different hash.
+func TestDeduplication(t *testing.T) {
+	inner := &countingStore{Store: gomap.NewStore(gomap.DefaultOptions)}
+	store := createStore(t, inner, encoding.JSON)
+
+	hash1, err := store.Put(test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash2, err := store.Put(test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash1 != hash2 {
+		t.Errorf("Expected the same hash for the same value, but got %q and %q", hash1, hash2)
+	}
+	if inner.setCount() != 1 {
+		t.Errorf("Expected the value to be stored once, but it was stored %v times", inner.setCount())
+	}
+
+	hash3, err := store.Put(test.Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash3 == hash1 {
+		t.Error("Expected a different hash for a different value")
+	}
+	if inner.setCount() != 2 {
+		t.Errorf("Expected 2 stored values, but %v were stored", inner.setCount())
+	}
+}
+
+// TestIntegrity tests that data that was modified in the inner store is detected.
+func TestIntegrity(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	store := createStore(t, inner, encoding.JSON)
+	hash, err := store.Put(test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = inner.Set(hash, []byte(`{"Bar":"qux"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.GetByHash(hash, new(test.Foo))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	_, err := cascontent.NewStore(nil, encoding.JSON)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON)
+	_, err = store.Put(nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	for _, hash := range []string{"", "foo", "ABCDEF0000000000000000000000000000000000000000000000000000000000"} {
+		_, err = store.GetByHash(hash, new(test.Foo))
+		if !errors.Is(err, cascontent.ErrInvalidHash) {
+			t.Errorf("Expected ErrInvalidHash for %q, but was %v", hash, err)
+		}
+	}
+	_, err = store.GetByHash("0000000000000000000000000000000000000000000000000000000000000000", nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store, err := cascontent.NewStore(gomap.NewStore(gomap.DefaultOptions), encoding.JSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// countingStore is a gokv.Store that counts the calls to Set.
+type countingStore struct {
+	gokv.Store
+	lock sync.Mutex
+	sets int
+}
+
+func (s *countingStore) Set(k string, v any) error {
+	s.lock.Lock()
+	s.sets++
+	s.lock.Unlock()
+	return s.Store.Set(k, v)
+}
+
+func (s *countingStore) setCount() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.sets
+}
+
+func createStore(t *testing.T, inner gokv.Store, codec encoding.Codec) cascontent.Store {
+	store, err := cascontent.NewStore(inner, codec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return store
+}
@@ -0,0 +1,6 @@
+/*
+Package cascontent contains a content-addressable store wrapper,
+which stores values under the SHA-256 hash of their marshalled form.
+Storing the same value multiple times only stores it once.
+*/
+package cascontent
@@ -9,6 +9,7 @@ import (
 	// ("warning: a blank import should be only in a main or test package, or have a comment justifying it").
 	_ "github.com/lib/pq"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/sql"
 )
@@ -20,6 +21,11 @@ type Client struct {
 	*sql.Client
 }
 
+// Kind returns gokv.KindCockroachDB.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindCockroachDB
+}
+
 // Options are the options for the CockroachDB client.
 type Options struct {
 	// Connection URL.
@@ -120,12 +126,17 @@ func NewClient(options Options) (Client, error) {
 	if err != nil {
 		return result, err
 	}
+	keysStmt, err := db.Prepare("SELECT k FROM " + options.TableName + " WHERE k LIKE $1 ESCAPE '!'")
+	if err != nil {
+		return result, err
+	}
 
 	c := sql.Client{
 		C:          db,
 		UpsertStmt: upsertStmt,
 		GetStmt:    getStmt,
 		DeleteStmt: deleteStmt,
+		KeysStmt:   keysStmt,
 		Codec:      options.Codec,
 	}
 
@@ -45,6 +45,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the CockroachDB client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -131,6 +148,13 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestPing tests if the server is reachable via Ping.
+func TestPing(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestPinger(client, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -140,6 +164,13 @@ func TestClose(t *testing.T) {
 	}
 }
 
+// TestIterator tests if listing the keys works properly.
+func TestIterator(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestIterator(client, t)
+}
+
 // checkConnection returns true if a connection could be made, false otherwise.
 func checkConnection() bool {
 	db, err := sql.Open("postgres", "postgres://root@localhost:26257/?sslmode=disable")
@@ -0,0 +1,105 @@
+package codecfallback
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// Store is a gokv.Store implementation that writes values with a primary codec
+// and reads them with the primary codec, falling back to a secondary codec if that fails.
+// It works with the raw bytes of another store, so the codec that's configured in the other store isn't used.
+type Store struct {
+	inner     gokv.RawStore
+	primary   encoding.Codec
+	secondary encoding.Codec
+}
+
+// Set marshals the given value with the primary codec and stores it for the given key in the inner store.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	data, err := s.primary.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.inner.SetRaw(k, data)
+}
+
+// Get retrieves the stored value for the given key from the inner store
+// and unmarshals it with the primary codec, or with the secondary codec if the primary codec fails.
+// The stored bytes are only retrieved once.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	data, found, err := s.inner.GetRaw(k)
+	if err != nil || !found {
+		return found, err
+	}
+	primaryErr := s.primary.Unmarshal(data, v)
+	if primaryErr == nil {
+		return true, nil
+	}
+	if err := s.secondary.Unmarshal(data, v); err != nil {
+		return true, fmt.Errorf("The value couldn't be decoded with the primary codec (%w) or with the secondary codec (%v)", primaryErr, err)
+	}
+	return true, nil
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	return s.inner.Delete(k)
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// NewStore creates a new codecfallback store that wraps the given store.
+// The inner store must implement gokv.RawStore.
+// The primary codec must fail to unmarshal values in the format of the secondary codec,
+// which for example is the case for encoding.JSON and encoding.Gob in both directions.
+// Otherwise values in the old format could be unmarshalled wrongly.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, primary, secondary encoding.Codec) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if inner == nil {
+		return result, errors.New("The inner store must not be nil")
+	}
+	rawStore, ok := inner.(gokv.RawStore)
+	if !ok {
+		return result, errors.New("The inner store must implement gokv.RawStore")
+	}
+	if primary == nil || secondary == nil {
+		return result, errors.New("The primary and secondary codecs must not be nil")
+	}
+
+	result.inner = rawStore
+	result.primary = primary
+	result.secondary = secondary
+
+	return result, nil
+}
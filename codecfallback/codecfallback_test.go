@@ -0,0 +1,159 @@
+package codecfallback_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/codecfallback"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestStore(t *testing.T) {
+	// Test with JSON as primary codec
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, encoding.Gob)
+		test.TestStore(store, t)
+	})
+
+	// Test with gob as primary codec
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.Gob, encoding.JSON)
+		test.TestStore(store, t)
+	})
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	// Test with JSON as primary codec
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, encoding.Gob)
+		test.TestTypes(store, t)
+	})
+
+	// Test with gob as primary codec
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.Gob, encoding.JSON)
+		test.TestTypes(store, t)
+	})
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, encoding.Gob)
+
+	goroutineCount := 1000
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+}
+
+// TestFallback tests that values written with the secondary codec can be read through the wrapper
+// and that the wrapper writes with the primary codec.
+func TestFallback(t *testing.T) {
+	inner := gomap.NewStore(gomap.Options{Codec: encoding.Gob})
+	err := inner.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := createStore(t, inner, encoding.JSON, encoding.Gob)
+	actual := test.Foo{}
+	found, err := store.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+
+	err = store.Set("foo", test.Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _, err := inner.GetRaw("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte(`{"Bar":"qux"}`)) {
+		t.Errorf("Expected the value to be written with the primary codec, but was %q", data)
+	}
+	found, err = store.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "qux" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "qux"}, found, actual)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	// Test empty key
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	store := createStore(t, inner, encoding.JSON, encoding.Gob)
+	err := store.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Test value that neither codec can decode
+	err = inner.SetRaw("foo", []byte("invalid"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Get("foo", new(test.Foo))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Test invalid parameters
+	_, err = codecfallback.NewStore(nil, encoding.JSON, encoding.Gob)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = codecfallback.NewStore(nonRawStore{inner}, encoding.JSON, encoding.Gob)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = codecfallback.NewStore(inner, encoding.JSON, nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, encoding.Gob)
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// nonRawStore hides the gokv.RawStore methods of the embedded store.
+type nonRawStore struct {
+	gokv.Store
+}
+
+func createStore(t *testing.T, inner gokv.Store, primary, secondary encoding.Codec) codecfallback.Store {
+	store, err := codecfallback.NewStore(inner, primary, secondary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+	return store
+}
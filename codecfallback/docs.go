@@ -0,0 +1,6 @@
+/*
+Package codecfallback contains a `gokv.Store` wrapper for migrating from one codec to another.
+Values are written with the new (primary) codec, and values that can't be decoded with it
+are decoded with the old (secondary) codec.
+*/
+package codecfallback
@@ -0,0 +1,20 @@
+module github.com/philippgille/gokv/codecfallback
+
+go 1.20
+
+require (
+	github.com/philippgille/gokv v0.7.0
+	github.com/philippgille/gokv/encoding v0.7.0
+	github.com/philippgille/gokv/gomap v0.7.0
+	github.com/philippgille/gokv/test v0.7.0
+	github.com/philippgille/gokv/util v0.7.0
+)
+
+require github.com/go-test/deep v1.1.0 // indirect
+
+replace (
+	github.com/philippgille/gokv => ../
+	github.com/philippgille/gokv/gomap => ../gomap
+	github.com/philippgille/gokv/test => ../test
+	github.com/philippgille/gokv/util => ../util
+)
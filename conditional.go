@@ -0,0 +1,56 @@
+package gokv
+
+import "errors"
+
+// ErrConditionalDeleteNotSupported is returned by DeleteIf when the store doesn't implement the ConditionalDeleter interface.
+var ErrConditionalDeleteNotSupported = errors.New("The store doesn't support conditional deletes")
+
+// ErrSetIfAbsentNotSupported is returned by SetIfAbsent when the store doesn't implement the ConditionalSetter interface.
+var ErrSetIfAbsentNotSupported = errors.New("The store doesn't support storing values only if the key doesn't exist")
+
+// ConditionalDeleter is an optional interface for stores that can atomically delete a key-value pair
+// only if it still holds an expected value, for example to clean up a value
+// without deleting a concurrent update of it.
+// Check for it with a type assertion or use the DeleteIf function.
+type ConditionalDeleter interface {
+	// DeleteIf deletes the stored value for the given key if it's equal to the expected value.
+	// The values are compared in their marshalled form, so the expected value must be
+	// of the same type as the stored one and its marshalling must be deterministic
+	// (which isn't the case for maps with gob, for example).
+	// If the key doesn't exist or holds a different value, it returns (false, nil).
+	// The key must not be "" and the expected value must not be nil.
+	DeleteIf(k string, expected any) (deleted bool, err error)
+}
+
+// DeleteIf deletes the stored value for the given key via the store if it implements the ConditionalDeleter interface
+// and the stored value is equal to the expected value.
+// Otherwise ErrConditionalDeleteNotSupported is returned.
+func DeleteIf(store Store, k string, expected any) (deleted bool, err error) {
+	deleter, ok := store.(ConditionalDeleter)
+	if !ok {
+		return false, ErrConditionalDeleteNotSupported
+	}
+	return deleter.DeleteIf(k, expected)
+}
+
+// ConditionalSetter is an optional interface for stores that can atomically store a value
+// only if the key doesn't exist yet, so that of several concurrent callers only one creates the value.
+// Check for it with a type assertion or use the SetIfAbsent function.
+type ConditionalSetter interface {
+	// SetIfAbsent stores the given value for the given key if the key doesn't exist yet.
+	// Expired key-value pairs are treated as non-existing.
+	// If the key already exists, the stored value isn't changed and it returns (false, nil).
+	// The key must not be "" and the value must not be nil.
+	SetIfAbsent(k string, v any) (set bool, err error)
+}
+
+// SetIfAbsent stores the given value for the given key via the store if it implements the ConditionalSetter interface
+// and the key doesn't exist yet.
+// Otherwise ErrSetIfAbsentNotSupported is returned.
+func SetIfAbsent(store Store, k string, v any) (set bool, err error) {
+	setter, ok := store.(ConditionalSetter)
+	if !ok {
+		return false, ErrSetIfAbsentNotSupported
+	}
+	return setter.SetIfAbsent(k, v)
+}
@@ -3,15 +3,17 @@ package consul
 import (
 	"github.com/hashicorp/consul/api"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
 // Client is a gokv.Store implementation for Consul.
 type Client struct {
-	c      *api.KV
-	folder string
-	codec  encoding.Codec
+	c       *api.KV
+	session *api.Session
+	folder  string
+	codec   encoding.Codec
 }
 
 // Set stores the given value for the given key.
@@ -85,6 +87,11 @@ func (c Client) Delete(k string) error {
 	return err
 }
 
+// Kind returns gokv.KindConsul.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindConsul
+}
+
 // Close closes the client.
 // In the Consul implementation this doesn't have any effect.
 func (c Client) Close() error {
@@ -99,22 +106,29 @@ type Options struct {
 	// Address of the Consul server, including port number.
 	// Optional ("127.0.0.1:8500" by default).
 	Address string
-	// Directory under which to store the key-value pairs.
+	// Directory under which to store the key-value pairs, which works as a key prefix.
 	// The Consul UI calls this "folder".
 	// Optional (none by default).
 	Folder string
+	// ACL token for the requests, which is required when the Consul server has ACLs enabled.
+	// The token needs read and write permissions for the key-value pairs in the Folder.
+	// Optional ("" by default, meaning the CONSUL_HTTP_TOKEN environment variable or the agent's default token is used).
+	Token string
+	// Datacenter to store the key-value pairs in.
+	// Optional ("" by default, meaning the datacenter of the agent that the client is connected to).
+	Datacenter string
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// Scheme: "http", Address: "127.0.0.1:8500", Folder: none, Codec: encoding.JSON
+// Scheme: "http", Address: "127.0.0.1:8500", Folder: none, Token: "", Datacenter: "", Codec: encoding.JSON
 var DefaultOptions = Options{
 	Scheme:  "http",
 	Address: "127.0.0.1:8500",
 	Codec:   encoding.JSON,
-	// No need to define Folder because its zero value is fine
+	// No need to define Folder, Token or Datacenter because their zero values are fine
 }
 
 // NewClient creates a new Consul client.
@@ -135,12 +149,18 @@ func NewClient(options Options) (Client, error) {
 	config := api.DefaultConfig()
 	config.Scheme = options.Scheme
 	config.Address = options.Address
+	// Only override the token from the environment variable if one is set explicitly
+	if options.Token != "" {
+		config.Token = options.Token
+	}
+	config.Datacenter = options.Datacenter
 	client, err := api.NewClient(config)
 	if err != nil {
 		return result, err
 	}
 
 	result.c = client.KV()
+	result.session = client.Session()
 	result.folder = options.Folder
 	result.codec = options.Codec
 
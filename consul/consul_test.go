@@ -44,6 +44,21 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the Consul client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -55,6 +70,51 @@ func TestClientConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, client)
 }
 
+// TestLock tests that two clients can't hold the same lock simultaneously
+// and that a lock automatically expires after its TTL, unless it's refreshed by gokv.WithLock.
+func TestLock(t *testing.T) {
+	clientA := createClient(t, encoding.JSON)
+	clientB := createClient(t, encoding.JSON)
+
+	err := clientA.Delete("lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 10s is the minimum TTL of Consul sessions
+	test.TestLocker(t, clientA, clientB, "lock", 10*time.Second)
+	test.TestWithLock(t, clientA, clientB, "lock", 10*time.Second)
+}
+
+// TestDatacenter tests if the key-value pairs are stored in the configured datacenter.
+// The token is ignored by Consul when ACLs are disabled, like in the test environment.
+func TestDatacenter(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to Consul could be established. Probably not running in a proper test environment.")
+	}
+
+	options := consul.Options{
+		Folder:     "test_" + strconv.FormatInt(time.Now().Unix(), 10),
+		Token:      "test",
+		Datacenter: "dc1",
+	}
+	client, err := consul.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.TestStore(client, t)
+
+	options.Datacenter = "non-existing"
+	client, err = consul.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Set("foo", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -4,6 +4,7 @@ go 1.20
 
 require (
 	github.com/hashicorp/consul/api v1.26.1
+	github.com/philippgille/gokv v0.7.0
 	github.com/philippgille/gokv/encoding v0.7.0
 	github.com/philippgille/gokv/test v0.7.0
 	github.com/philippgille/gokv/util v0.7.0
@@ -25,7 +26,12 @@ require (
 	github.com/mattn/go-isatty v0.0.17 // indirect
 	github.com/mitchellh/go-homedir v1.1.0 // indirect
 	github.com/mitchellh/mapstructure v1.5.0 // indirect
-	github.com/philippgille/gokv v0.7.0 // indirect
 	golang.org/x/exp v0.0.0-20230817173708-d852ddb80c63 // indirect
 	golang.org/x/sys v0.13.0 // indirect
 )
+
+replace (
+	github.com/philippgille/gokv => ../
+	github.com/philippgille/gokv/test => ../test
+	github.com/philippgille/gokv/util => ../util
+)
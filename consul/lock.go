@@ -0,0 +1,104 @@
+package consul
+
+import (
+	"time"
+
+	"github.com/hashicorp/consul/api"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// minLockTTL is the minimum TTL of Consul sessions.
+var minLockTTL = 10 * time.Second
+
+// AcquireLock acquires the lock with the given key via a Consul session.
+// The session has the given TTL and is configured to delete the key when it's invalidated,
+// so the lock automatically expires if it's not refreshed.
+// If the lock is currently held by someone else, gokv.ErrLockHeld is returned.
+// Consul requires a TTL of at least 10 seconds, smaller values are increased to that,
+// and it invalidates sessions only up to twice the TTL after their last renewal.
+// The key must not be "" and the TTL must be positive.
+func (c Client) AcquireLock(k string, ttl time.Duration) (gokv.Lock, error) {
+	if err := util.CheckKey(k); err != nil {
+		return nil, err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	if ttl < minLockTTL {
+		ttl = minLockTTL
+	}
+	sessionEntry := api.SessionEntry{
+		TTL:      ttl.Round(time.Second).String(),
+		Behavior: api.SessionBehaviorDelete,
+		// By default Consul blocks the acquisition of a lock for 15 seconds after the session
+		// of its previous holder was invalidated.
+		LockDelay: time.Millisecond,
+	}
+	sessionID, _, err := c.session.Create(&sessionEntry, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.folder != "" {
+		k = c.folder + "/" + k
+	}
+	kvPair := api.KVPair{
+		Key:     k,
+		Session: sessionID,
+	}
+	acquired, _, err := c.c.Acquire(&kvPair, nil)
+	if err != nil || !acquired {
+		_, _ = c.session.Destroy(sessionID, nil)
+		if err != nil {
+			return nil, err
+		}
+		return nil, gokv.ErrLockHeld
+	}
+	return lock{
+		c:         c,
+		key:       k,
+		sessionID: sessionID,
+	}, nil
+}
+
+// lock is a gokv.Lock implementation for Consul.
+type lock struct {
+	c         Client
+	key       string
+	sessionID string
+}
+
+// Release releases the lock by destroying its session, which also deletes the key.
+// If the lock expired in the meantime, gokv.ErrLockLost is returned.
+func (l lock) Release() error {
+	kvPair, _, err := l.c.c.Get(l.key, nil)
+	if err != nil {
+		return err
+	}
+	_, err = l.c.session.Destroy(l.sessionID, nil)
+	if err != nil {
+		return err
+	}
+	if kvPair == nil || kvPair.Session != l.sessionID {
+		return gokv.ErrLockLost
+	}
+	return nil
+}
+
+// Refresh renews the lock's session.
+// Consul can only renew a session with the TTL it was created with,
+// so the passed TTL is ignored.
+// If the lock expired in the meantime, gokv.ErrLockLost is returned.
+func (l lock) Refresh(ttl time.Duration) error {
+	sessionEntry, _, err := l.c.session.Renew(l.sessionID, nil)
+	if err != nil {
+		return err
+	}
+	if sessionEntry == nil {
+		return gokv.ErrLockLost
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+package gokv
+
+import "errors"
+
+// ErrIncrementUnsupported is returned by Increment when the store doesn't implement the CounterStore interface.
+var ErrIncrementUnsupported = errors.New("The store doesn't support atomic counters")
+
+// ErrNotACounter is returned by Increment when the key holds a value that isn't an integer.
+var ErrNotACounter = errors.New("The stored value is not a counter")
+
+// CounterStore is an optional interface for stores that can atomically increment an integer value,
+// for example for rate limiters or statistics, where a Get followed by a Set would lose concurrent updates.
+// Check for it with a type assertion or use the Increment function.
+//
+// The counter is stored in the backend's native integer representation, not marshalled with the store's codec.
+// When reading it with Get, the store passes its decimal representation to the codec,
+// so with encoding.JSON it can be read into an int64.
+//
+// It's implemented by the dynamodb, etcd, mongodb and redis implementations.
+type CounterStore interface {
+	// Increment adds delta to the counter that's stored for the given key and returns the new count.
+	// A non-existing counter starts at 0. delta can be negative to decrement the counter.
+	// If the key holds a value that isn't an integer, ErrNotACounter is returned.
+	// The key must not be "".
+	Increment(k string, delta int64) (int64, error)
+}
+
+// Increment adds delta to the counter that's stored for the given key via the store
+// if it implements the CounterStore interface and returns the new count.
+// Otherwise ErrIncrementUnsupported is returned.
+func Increment(store Store, k string, delta int64) (int64, error) {
+	counterStore, ok := store.(CounterStore)
+	if !ok {
+		return 0, ErrIncrementUnsupported
+	}
+	return counterStore.Increment(k, delta)
+}
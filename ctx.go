@@ -0,0 +1,25 @@
+package gokv
+
+import "context"
+
+// StoreCtx is an optional interface for stores that accept a context.Context,
+// for example for canceling slow operations or propagating deadlines from an HTTP handler.
+// Check for it with a type assertion and fall back to the Store methods when it's not implemented,
+// or use util.WrapStoreCtx, which does that for you.
+type StoreCtx interface {
+	Store
+	// SetCtx is like Set, but with a context.
+	// When the context is canceled or its deadline is exceeded, the operation is aborted
+	// and the context's error is returned.
+	// Depending on the implementation the value might have been stored anyway.
+	SetCtx(ctx context.Context, k string, v any) error
+	// GetCtx is like Get, but with a context.
+	// When the context is canceled or its deadline is exceeded, the operation is aborted
+	// and the context's error is returned.
+	GetCtx(ctx context.Context, k string, v any) (found bool, err error)
+	// DeleteCtx is like Delete, but with a context.
+	// When the context is canceled or its deadline is exceeded, the operation is aborted
+	// and the context's error is returned.
+	// Depending on the implementation the value might have been deleted anyway.
+	DeleteCtx(ctx context.Context, k string) error
+}
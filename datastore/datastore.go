@@ -8,12 +8,11 @@ import (
 	"cloud.google.com/go/datastore"
 	"google.golang.org/api/option"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
-const kind = "gokv"
-
 // entity is a struct that holds the actual value as a slice of bytes named "V"
 // (translated to lowercase "v" in Cloud Datastore).
 // Cloud Datastore requires a pointer to a struct as value.
@@ -26,15 +25,23 @@ var defaultTimeout = 2 * time.Second
 
 // Client is a gokv.Store implementation for Cloud Datastore.
 type Client struct {
-	c       *datastore.Client
-	timeOut time.Duration
-	codec   encoding.Codec
+	c          *datastore.Client
+	entityKind string
+	namespace  string
+	timeOut    time.Duration
+	codec      encoding.Codec
 }
 
 // Set stores the given value for the given key.
 // Values are automatically marshalled to JSON or gob (depending on the configuration).
 // The key must not be "" and the value must not be nil.
 func (c Client) Set(k string, v any) error {
+	return c.SetCtx(context.Background(), k, v)
+}
+
+// SetCtx is like Set, but the context is passed to the Cloud Datastore client.
+// The configured timeout still applies, in addition to the context's deadline.
+func (c Client) SetCtx(ctx context.Context, k string, v any) error {
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return err
 	}
@@ -45,16 +52,12 @@ func (c Client) Set(k string, v any) error {
 		return err
 	}
 
-	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	tctx, cancel := context.WithTimeout(ctx, c.timeOut)
 	defer cancel()
-	key := datastore.Key{
-		Kind: kind,
-		Name: k,
-	}
 	src := entity{
 		V: data,
 	}
-	_, err = c.c.Put(tctx, &key, &src)
+	_, err = c.c.Put(tctx, c.key(k), &src)
 
 	return err
 }
@@ -66,18 +69,20 @@ func (c Client) Set(k string, v any) error {
 // If no value is found it returns (false, nil).
 // The key must not be "" and the pointer must not be nil.
 func (c Client) Get(k string, v any) (found bool, err error) {
+	return c.GetCtx(context.Background(), k, v)
+}
+
+// GetCtx is like Get, but the context is passed to the Cloud Datastore client.
+// The configured timeout still applies, in addition to the context's deadline.
+func (c Client) GetCtx(ctx context.Context, k string, v any) (found bool, err error) {
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return false, err
 	}
 
-	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	tctx, cancel := context.WithTimeout(ctx, c.timeOut)
 	defer cancel()
-	key := datastore.Key{
-		Kind: kind,
-		Name: k,
-	}
 	dst := new(entity)
-	err = c.c.Get(tctx, &key, dst)
+	err = c.c.Get(tctx, c.key(k), dst)
 	if err != nil {
 		if err == datastore.ErrNoSuchEntity {
 			return false, nil
@@ -93,17 +98,31 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 // Deleting a non-existing key-value pair does NOT lead to an error.
 // The key must not be "".
 func (c Client) Delete(k string) error {
+	return c.DeleteCtx(context.Background(), k)
+}
+
+// DeleteCtx is like Delete, but the context is passed to the Cloud Datastore client.
+// The configured timeout still applies, in addition to the context's deadline.
+func (c Client) DeleteCtx(ctx context.Context, k string) error {
 	if err := util.CheckKey(k); err != nil {
 		return err
 	}
 
-	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	tctx, cancel := context.WithTimeout(ctx, c.timeOut)
 	defer cancel()
-	key := datastore.Key{
-		Kind: kind,
-		Name: k,
-	}
-	return c.c.Delete(tctx, &key)
+	return c.c.Delete(tctx, c.key(k))
+}
+
+// key returns the Cloud Datastore key for the given key, with the configured kind and namespace.
+func (c Client) key(k string) *datastore.Key {
+	key := datastore.NameKey(c.entityKind, k, nil)
+	key.Namespace = c.namespace
+	return key
+}
+
+// Kind returns gokv.KindDatastore.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindDatastore
 }
 
 // Close closes the client.
@@ -122,6 +141,13 @@ type Options struct {
 	// GOOGLE_APPLICATION_CREDENTIALS environment variable.
 	// Optional ("" by default, leading to a lookup via environment variable).
 	CredentialsFile string
+	// Kind of the entities that the key-value pairs are stored as.
+	// Optional ("gokv" by default).
+	Kind string
+	// Namespace of the entities, for example to isolate the data of multiple tenants
+	// or to have multiple independent stores in the same project.
+	// Optional ("" by default, which is the default namespace).
+	Namespace string
 	// The timeout for operations.
 	// Optional (2 * time.Second by default).
 	Timeout *time.Duration
@@ -131,11 +157,12 @@ type Options struct {
 }
 
 // DefaultOptions is an Options object with default values.
-// CredentialsFile: "", Timeout: 2 * time.Second, Codec: encoding.JSON
+// CredentialsFile: "", Kind: "gokv", Namespace: "", Timeout: 2 * time.Second, Codec: encoding.JSON
 var DefaultOptions = Options{
+	Kind:    "gokv",
 	Timeout: &defaultTimeout,
 	Codec:   encoding.JSON,
-	// No need to set CredentialsFile because its Go zero value is fine.
+	// No need to set CredentialsFile or Namespace because their Go zero values are fine.
 }
 
 // NewClient creates a new Cloud Datastore client.
@@ -150,6 +177,9 @@ func NewClient(options Options) (Client, error) {
 	}
 
 	// Set default values
+	if options.Kind == "" {
+		options.Kind = DefaultOptions.Kind
+	}
 	if options.Timeout == nil {
 		options.Timeout = DefaultOptions.Timeout
 	}
@@ -172,6 +202,8 @@ func NewClient(options Options) (Client, error) {
 	}
 
 	result.c = dsClient
+	result.entityKind = options.Kind
+	result.namespace = options.Namespace
 	result.timeOut = *options.Timeout
 	result.codec = options.Codec
 
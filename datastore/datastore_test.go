@@ -49,6 +49,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the Cloud Datastore client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -62,6 +79,51 @@ func TestClientConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, client)
 }
 
+// TestNamespace tests that clients with different namespaces or kinds don't see each other's key-value pairs.
+func TestNamespace(t *testing.T) {
+	clientA := createClient(t, encoding.JSON)
+	defer clientA.Close()
+	clientB := createClientWithOptions(t, datastore.Options{
+		ProjectID: "gokv",
+		Namespace: "tenant-b",
+	})
+	defer clientB.Close()
+	clientC := createClientWithOptions(t, datastore.Options{
+		ProjectID: "gokv",
+		Kind:      "other",
+	})
+	defer clientC.Close()
+
+	err := clientA.Set("foo", test.Foo{Bar: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientA.Delete("foo")
+	for _, client := range []datastore.Client{clientB, clientC} {
+		found, err := client.Get("foo", new(test.Foo))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Error("A value from another namespace or kind was found")
+		}
+	}
+
+	err = clientB.Set("foo", test.Foo{Bar: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientB.Delete("foo")
+	actual := test.Foo{}
+	found, err := clientA.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "a" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "a"}, found, actual)
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -180,14 +242,18 @@ func checkConnection() bool {
 }
 
 func createClient(t *testing.T, codec encoding.Codec) datastore.Client {
-	err := os.Setenv("DATASTORE_EMULATOR_HOST", "localhost:8081")
-	if err != nil {
-		t.Fatalf("Emulator environment variable couldn't be set: %v\n", err)
-	}
 	options := datastore.Options{
 		ProjectID: "gokv",
 		Codec:     codec,
 	}
+	return createClientWithOptions(t, options)
+}
+
+func createClientWithOptions(t *testing.T, options datastore.Options) datastore.Client {
+	err := os.Setenv("DATASTORE_EMULATOR_HOST", "localhost:8081")
+	if err != nil {
+		t.Fatalf("Emulator environment variable couldn't be set: %v\n", err)
+	}
 	client, err := datastore.NewClient(options)
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,103 @@
+package defaults
+
+import (
+	"errors"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// Store is a gokv.Store implementation that forwards all calls to another store,
+// but returns a default value when Get doesn't find a value for a key that has a default.
+type Store struct {
+	inner    gokv.Store
+	defaults map[string][]byte
+	codec    encoding.Codec
+}
+
+// Set stores the given value for the given key in the inner store.
+// This overrides the default value of the key.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	return s.inner.Set(k, v)
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// If no value is found, but there's a default value for the key,
+// the default value is unmarshalled into v and found is true.
+// If neither is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	found, err = s.inner.Get(k, v)
+	if err != nil || found {
+		return found, err
+	}
+
+	data, ok := s.defaults[k]
+	if !ok {
+		return false, nil
+	}
+	return true, s.codec.Unmarshal(data, v)
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// For keys with a default value, Get returns the default value again afterwards.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	return s.inner.Delete(k)
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// Options are the options for the defaults store.
+type Options struct {
+	// Encoding format of the default values.
+	// Optional (encoding.JSON by default).
+	Codec encoding.Codec
+}
+
+// DefaultOptions is an Options object with default values.
+// Codec: encoding.JSON
+var DefaultOptions = Options{
+	Codec: encoding.JSON,
+}
+
+// NewStore creates a new defaults store that wraps the given store.
+// The default values must be marshalled with the codec from the options,
+// for example `[]byte("true")` for a boolean feature flag with encoding.JSON.
+// They're not written to the inner store.
+// The map is copied, so changing it afterwards doesn't have any effect.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, defaults map[string][]byte, options Options) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	for k, data := range defaults {
+		if err := util.CheckKey(k); err != nil {
+			return result, err
+		}
+		if data == nil {
+			return result, errors.New("The default value for key " + k + " is nil, which is not allowed")
+		}
+	}
+
+	// Set default values
+	if options.Codec == nil {
+		options.Codec = DefaultOptions.Codec
+	}
+
+	result.inner = inner
+	result.defaults = make(map[string][]byte, len(defaults))
+	for k, data := range defaults {
+		result.defaults[k] = data
+	}
+	result.codec = options.Codec
+
+	return result, nil
+}
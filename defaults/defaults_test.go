@@ -0,0 +1,116 @@
+package defaults_test
+
+import (
+	"testing"
+
+	"github.com/philippgille/gokv/defaults"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestStore(t *testing.T) {
+	store := createStore(t, nil)
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := createStore(t, nil)
+	test.TestTypes(store, t)
+}
+
+// TestDefaults tests that an unset key returns its default and a set key returns the stored override.
+func TestDefaults(t *testing.T) {
+	store := createStore(t, map[string][]byte{
+		"newUI":   []byte("false"),
+		"maxSize": []byte(`{"Bar":"baz"}`),
+	})
+
+	// Default
+	var flag bool
+	found, err := store.Get("newUI", &flag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+	if flag {
+		t.Error("Expected the default value false, but was true")
+	}
+	foo := test.Foo{}
+	found, err = store.Get("maxSize", &foo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || foo.Bar != "baz" {
+		t.Errorf("Expected the default value %q, but was %q (found: %v)", "baz", foo.Bar, found)
+	}
+
+	// Override
+	err = store.Set("newUI", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err = store.Get("newUI", &flag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || !flag {
+		t.Errorf("Expected the stored value true, but was %v (found: %v)", flag, found)
+	}
+
+	// Back to default after deleting the override
+	err = store.Delete("newUI")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err = store.Get("newUI", &flag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || flag {
+		t.Errorf("Expected the default value false, but was %v (found: %v)", flag, found)
+	}
+
+	// No default
+	found, err = store.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	_, err := defaults.NewStore(inner, map[string][]byte{"": []byte("1")}, defaults.DefaultOptions)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = defaults.NewStore(inner, map[string][]byte{"foo": nil}, defaults.DefaultOptions)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// A default that doesn't match the destination type
+	store := createStore(t, map[string][]byte{"foo": []byte(`"bar"`)})
+	_, err = store.Get("foo", new(int))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+func createStore(t *testing.T, defaultValues map[string][]byte) defaults.Store {
+	inner := gomap.NewStore(gomap.Options{Codec: encoding.JSON})
+	store, err := defaults.NewStore(inner, defaultValues, defaults.DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
@@ -0,0 +1,5 @@
+/*
+Package defaults contains a `gokv.Store` wrapper that returns default values for a set of keys when they're not stored,
+for example for feature flags or other configuration with sensible defaults.
+*/
+package defaults
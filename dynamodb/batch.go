@@ -0,0 +1,187 @@
+package dynamodb
+
+import (
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// DynamoDB limits the number of items per BatchWriteItem and BatchGetItem request.
+const (
+	maxBatchWriteItems = 25
+	maxBatchGetItems   = 100
+)
+
+// SetMany stores the given values for the given keys.
+// It uses BatchWriteItem with up to 25 items per request.
+// Items that DynamoDB didn't process (for example due to throttling) are sent again.
+// The operation is not atomic.
+// The keys must not be "" and the values must not be nil.
+func (c Client) SetMany(values map[string]any) error {
+	if err := util.CheckKeysAndValues(values); err != nil {
+		return err
+	}
+
+	requests := make([]*awsdynamodb.WriteRequest, 0, len(values))
+	for k, v := range values {
+		valAttr, err := c.valueAttr(v)
+		if err != nil {
+			return err
+		}
+		k := k
+		item := map[string]*awsdynamodb.AttributeValue{
+			keyAttrName: {S: &k},
+			valAttrName: valAttr,
+		}
+		c.addGroup(k, item)
+		requests = append(requests, &awsdynamodb.WriteRequest{
+			PutRequest: &awsdynamodb.PutRequest{
+				Item: item,
+			},
+		})
+	}
+	return c.batchWrite(requests)
+}
+
+// GetMany retrieves the stored values for the given keys.
+// It uses BatchGetItem with up to 100 keys per request.
+// Keys that DynamoDB didn't process (for example due to throttling) are requested again.
+// values must be a slice with the same length as keys, for example a []any or []*Foo,
+// with each element being a non-nil pointer that the value of the key at the same index is unmarshalled into.
+// found contains whether a value was found for the key at the same index.
+// The keys must not be "".
+func (c Client) GetMany(keys []string, values any) (found []bool, err error) {
+	pointers, err := util.BatchPointers(keys, values)
+	if err != nil {
+		return nil, err
+	}
+
+	// DynamoDB rejects requests with duplicate keys
+	uniqueKeys := unique(keys)
+	data := make(map[string][]byte, len(uniqueKeys))
+	for start := 0; start < len(uniqueKeys); start += maxBatchGetItems {
+		end := start + maxBatchGetItems
+		if end > len(uniqueKeys) {
+			end = len(uniqueKeys)
+		}
+		requestKeys := make([]map[string]*awsdynamodb.AttributeValue, 0, end-start)
+		for _, k := range uniqueKeys[start:end] {
+			k := k
+			requestKeys = append(requestKeys, map[string]*awsdynamodb.AttributeValue{
+				keyAttrName: {S: &k},
+			})
+		}
+		requestItems := map[string]*awsdynamodb.KeysAndAttributes{
+			c.tableName: {Keys: requestKeys},
+		}
+		for attempt := 0; len(requestItems) > 0; attempt++ {
+			if attempt > 0 {
+				backoff(attempt)
+			}
+			output, err := c.c.BatchGetItem(&awsdynamodb.BatchGetItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range output.Responses[c.tableName] {
+				keyAttr, valAttr := item[keyAttrName], item[valAttrName]
+				if keyAttr == nil || keyAttr.S == nil || valAttr == nil || isExpired(item) {
+					continue
+				}
+				data[*keyAttr.S] = valueData(valAttr)
+			}
+			requestItems = output.UnprocessedKeys
+		}
+	}
+
+	found = make([]bool, len(keys))
+	for i, k := range keys {
+		d, ok := data[k]
+		if !ok {
+			continue
+		}
+		found[i] = true
+		if err = c.codec.Unmarshal(d, pointers[i]); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// DeleteMany deletes the stored values for the given keys.
+// It uses BatchWriteItem with up to 25 items per request.
+// Items that DynamoDB didn't process (for example due to throttling) are sent again.
+// Deleting non-existing key-value pairs does NOT lead to an error.
+// The keys must not be "".
+func (c Client) DeleteMany(keys []string) error {
+	if err := util.CheckKeys(keys); err != nil {
+		return err
+	}
+
+	// DynamoDB rejects requests with duplicate keys
+	uniqueKeys := unique(keys)
+	requests := make([]*awsdynamodb.WriteRequest, 0, len(uniqueKeys))
+	for _, k := range uniqueKeys {
+		k := k
+		requests = append(requests, &awsdynamodb.WriteRequest{
+			DeleteRequest: &awsdynamodb.DeleteRequest{
+				Key: map[string]*awsdynamodb.AttributeValue{
+					keyAttrName: {S: &k},
+				},
+			},
+		})
+	}
+	return c.batchWrite(requests)
+}
+
+// batchWrite sends the write requests in chunks of 25
+// and resends unprocessed items until all items are processed.
+func (c Client) batchWrite(requests []*awsdynamodb.WriteRequest) error {
+	for start := 0; start < len(requests); start += maxBatchWriteItems {
+		end := start + maxBatchWriteItems
+		if end > len(requests) {
+			end = len(requests)
+		}
+		requestItems := map[string][]*awsdynamodb.WriteRequest{
+			c.tableName: requests[start:end],
+		}
+		for attempt := 0; len(requestItems) > 0; attempt++ {
+			if attempt > 0 {
+				backoff(attempt)
+			}
+			output, err := c.c.BatchWriteItem(&awsdynamodb.BatchWriteItemInput{
+				RequestItems: requestItems,
+			})
+			if err != nil {
+				return err
+			}
+			requestItems = output.UnprocessedItems
+		}
+	}
+	return nil
+}
+
+// backoff sleeps before resending unprocessed items, as recommended by AWS.
+// The duration grows exponentially with the attempt, up to 1.6 seconds.
+func backoff(attempt int) {
+	if attempt > 5 {
+		attempt = 5
+	}
+	time.Sleep(time.Duration(50<<attempt) * time.Millisecond)
+}
+
+func unique(keys []string) []string {
+	seen := make(map[string]struct{}, len(keys))
+	result := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		result = append(result, k)
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package dynamodb
+
+import (
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// DeleteIf deletes the stored value for the given key if it's equal to the expected value.
+// It uses a conditional DeleteItem, so the comparison and deletion are atomic.
+// The values are compared in their marshalled form, or as numbers for integers when Options.NumericValues is set.
+// If the key doesn't exist, is expired or holds a different value, it returns (false, nil).
+// The key must not be "" and the expected value must not be nil.
+func (c Client) DeleteIf(k string, expected any) (deleted bool, err error) {
+	if err := util.CheckKeyAndValue(k, expected); err != nil {
+		return false, err
+	}
+
+	expectedAttr, err := c.valueAttr(expected)
+	if err != nil {
+		return false, err
+	}
+
+	key := make(map[string]*awsdynamodb.AttributeValue)
+	key[keyAttrName] = &awsdynamodb.AttributeValue{
+		S: &k,
+	}
+	condition := "#v = :v AND " + notExpiredCondition
+	deleteItemInput := awsdynamodb.DeleteItemInput{
+		TableName:           &c.tableName,
+		Key:                 key,
+		ConditionExpression: &condition,
+		ExpressionAttributeNames: map[string]*string{
+			"#v": &valAttrName,
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":v": expectedAttr,
+		},
+	}
+	addNotExpiredPlaceholders(deleteItemInput.ExpressionAttributeNames, deleteItemInput.ExpressionAttributeValues)
+	_, err = c.c.DeleteItem(&deleteItemInput)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// CompareAndSwap stores the new value for the given key if the stored value is equal to the old value.
+// It uses a conditional PutItem, so the comparison and replacement are atomic.
+// The values are compared in their marshalled form, or as numbers for integers when Options.NumericValues is set.
+// If the key doesn't exist, is expired or holds a different value, it returns (false, nil).
+// The key must not be "" and the values must not be nil.
+func (c Client) CompareAndSwap(k string, old, new any) (swapped bool, err error) {
+	if err := util.CheckKeyAndValue(k, old); err != nil {
+		return false, err
+	}
+	if err := util.CheckVal(new); err != nil {
+		return false, err
+	}
+
+	oldAttr, err := c.valueAttr(old)
+	if err != nil {
+		return false, err
+	}
+	newAttr, err := c.valueAttr(new)
+	if err != nil {
+		return false, err
+	}
+
+	item := make(map[string]*awsdynamodb.AttributeValue)
+	item[keyAttrName] = &awsdynamodb.AttributeValue{
+		S: &k,
+	}
+	item[valAttrName] = newAttr
+	c.addGroup(k, item)
+	condition := "#v = :old AND " + notExpiredCondition
+	putItemInput := awsdynamodb.PutItemInput{
+		TableName:           &c.tableName,
+		Item:                item,
+		ConditionExpression: &condition,
+		ExpressionAttributeNames: map[string]*string{
+			"#v": &valAttrName,
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":old": oldAttr,
+		},
+	}
+	addNotExpiredPlaceholders(putItemInput.ExpressionAttributeNames, putItemInput.ExpressionAttributeValues)
+	_, err = c.c.PutItem(&putItemInput)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SetIfAbsent stores the given value for the given key if the key doesn't exist yet or is expired.
+// It uses a conditional PutItem, so the existence check and the write are atomic.
+// If the key already exists, it returns (false, nil).
+// The key must not be "" and the value must not be nil.
+func (c Client) SetIfAbsent(k string, v any) (set bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	valAttr, err := c.valueAttr(v)
+	if err != nil {
+		return false, err
+	}
+
+	item := make(map[string]*awsdynamodb.AttributeValue)
+	item[keyAttrName] = &awsdynamodb.AttributeValue{
+		S: &k,
+	}
+	item[valAttrName] = valAttr
+	c.addGroup(k, item)
+	condition := "attribute_not_exists(#k) OR NOT " + notExpiredCondition
+	putItemInput := awsdynamodb.PutItemInput{
+		TableName:           &c.tableName,
+		Item:                item,
+		ConditionExpression: &condition,
+		ExpressionAttributeNames: map[string]*string{
+			"#k": &keyAttrName,
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{},
+	}
+	addNotExpiredPlaceholders(putItemInput.ExpressionAttributeNames, putItemInput.ExpressionAttributeValues)
+	_, err = c.c.PutItem(&putItemInput)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,142 @@
+package dynamodb
+
+import (
+	"reflect"
+	"strconv"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// Increment adds delta to the counter that's stored for the given key and returns the new count.
+// It uses UpdateItem with an ADD expression, so the counter is stored as number attribute
+// and Get passes its decimal representation to the codec, which means it can be read into an int64 when using encoding.JSON.
+// An integer that was stored with Set can only be incremented when Options.NumericValues is set,
+// because it's marshalled with the codec otherwise.
+// A non-existing or expired counter starts at 0. delta can be negative to decrement the counter.
+// If the key holds a value that isn't a number, gokv.ErrNotACounter is returned.
+// The key must not be "".
+func (c Client) Increment(k string, delta int64) (int64, error) {
+	if err := util.CheckKey(k); err != nil {
+		return 0, err
+	}
+
+	count, err := c.updateCounter(k, delta, false)
+	if !isConditionalCheckFailed(err) {
+		return count, err
+	}
+	// Either the value isn't a number or the item is expired, but not deleted by DynamoDB yet.
+	// An expired item is treated like a non-existing one, so the counter starts at 0 again and has no expiry.
+	count, err = c.updateCounter(k, delta, true)
+	if !isConditionalCheckFailed(err) {
+		return count, err
+	}
+	// The item might have been replaced between the two updates
+	count, err = c.updateCounter(k, delta, false)
+	if isConditionalCheckFailed(err) {
+		return 0, gokv.ErrNotACounter
+	}
+	return count, err
+}
+
+// updateCounter adds delta to a counter that isn't expired, or, if reset is true,
+// sets an expired counter to delta.
+// If the respective condition isn't met, the error is a ConditionalCheckFailedException.
+func (c Client) updateCounter(k string, delta int64, reset bool) (int64, error) {
+	key := make(map[string]*awsdynamodb.AttributeValue)
+	key[keyAttrName] = &awsdynamodb.AttributeValue{
+		S: &k,
+	}
+	d := strconv.FormatInt(delta, 10)
+	returnValues := awsdynamodb.ReturnValueUpdatedNew
+	updateItemInput := awsdynamodb.UpdateItemInput{
+		TableName: &c.tableName,
+		Key:       key,
+		ExpressionAttributeNames: map[string]*string{
+			"#v": &valAttrName,
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":delta": {N: &d},
+		},
+		ReturnValues: &returnValues,
+	}
+	addNotExpiredPlaceholders(updateItemInput.ExpressionAttributeNames, updateItemInput.ExpressionAttributeValues)
+
+	group := map[string]*awsdynamodb.AttributeValue{}
+	c.addGroup(k, group)
+	groupAttr := group[groupAttrName]
+	if groupAttr != nil {
+		updateItemInput.ExpressionAttributeNames["#g"] = &groupAttrName
+		updateItemInput.ExpressionAttributeValues[":g"] = groupAttr
+	}
+
+	var updateExpression, condition string
+	if reset {
+		updateExpression = "SET #v = :delta"
+		if groupAttr != nil {
+			updateExpression += ", #g = :g"
+		}
+		updateExpression += " REMOVE #ttl"
+		condition = "#ttl <= :now"
+	} else {
+		updateExpression = "ADD #v :delta"
+		if groupAttr != nil {
+			updateExpression += " SET #g = :g"
+		}
+		// Without the type condition DynamoDB would reject the update with a generic validation error
+		numberType := awsdynamodb.ScalarAttributeTypeN
+		condition = "(attribute_not_exists(#v) OR attribute_type(#v, :n)) AND " + notExpiredCondition
+		updateItemInput.ExpressionAttributeValues[":n"] = &awsdynamodb.AttributeValue{S: &numberType}
+	}
+	updateItemInput.UpdateExpression = &updateExpression
+	updateItemInput.ConditionExpression = &condition
+
+	updateItemOutput, err := c.c.UpdateItem(&updateItemInput)
+	if err != nil {
+		return 0, err
+	}
+	attributeVal := updateItemOutput.Attributes[valAttrName]
+	if attributeVal == nil || attributeVal.N == nil {
+		return 0, gokv.ErrNotACounter
+	}
+	return strconv.ParseInt(*attributeVal.N, 10, 64)
+}
+
+// valueData returns the data of the value attribute,
+// which is the decimal representation for counters and the marshalled value otherwise.
+func valueData(attributeVal *awsdynamodb.AttributeValue) []byte {
+	if attributeVal.N != nil {
+		return []byte(*attributeVal.N)
+	}
+	return attributeVal.B
+}
+
+// valueAttr returns the attribute value for the given value,
+// which is a number attribute for integers when Options.NumericValues is set
+// and the marshalled value otherwise.
+func (c Client) valueAttr(v any) (*awsdynamodb.AttributeValue, error) {
+	if c.numericValues {
+		if n, ok := integerString(v); ok {
+			return &awsdynamodb.AttributeValue{N: &n}, nil
+		}
+	}
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &awsdynamodb.AttributeValue{B: data}, nil
+}
+
+// integerString returns the decimal representation of v if it's an integer.
+func integerString(v any) (string, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(rv.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(rv.Uint(), 10), true
+	}
+	return "", false
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -26,18 +27,27 @@ type Client struct {
 	c         *awsdynamodb.DynamoDB
 	tableName string
 	codec     encoding.Codec
+	groupFunc func(k string) string
+	// Store integers as number attributes instead of marshalling them
+	numericValues bool
 }
 
 // Set stores the given value for the given key.
 // Values are automatically marshalled to JSON or gob (depending on the configuration).
 // The key must not be "" and the value must not be nil.
 func (c Client) Set(k string, v any) error {
+	return c.SetCtx(context.Background(), k, v)
+}
+
+// SetCtx is like Set, but the context is passed to the DynamoDB SDK,
+// which also stops retrying when the context is canceled or its deadline is exceeded.
+func (c Client) SetCtx(ctx context.Context, k string, v any) error {
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return err
 	}
 
 	// First turn the passed object into something that DynamoDB can handle.
-	data, err := c.codec.Marshal(v)
+	valAttr, err := c.valueAttr(v)
 	if err != nil {
 		return err
 	}
@@ -46,14 +56,13 @@ func (c Client) Set(k string, v any) error {
 	item[keyAttrName] = &awsdynamodb.AttributeValue{
 		S: &k,
 	}
-	item[valAttrName] = &awsdynamodb.AttributeValue{
-		B: data,
-	}
+	item[valAttrName] = valAttr
+	c.addGroup(k, item)
 	putItemInput := awsdynamodb.PutItemInput{
 		TableName: &c.tableName,
 		Item:      item,
 	}
-	_, err = c.c.PutItem(&putItemInput)
+	_, err = c.c.PutItemWithContext(ctx, &putItemInput)
 	if err != nil {
 		return err
 	}
@@ -67,6 +76,12 @@ func (c Client) Set(k string, v any) error {
 // If no value is found it returns (false, nil).
 // The key must not be "" and the pointer must not be nil.
 func (c Client) Get(k string, v any) (found bool, err error) {
+	return c.GetCtx(context.Background(), k, v)
+}
+
+// GetCtx is like Get, but the context is passed to the DynamoDB SDK,
+// which also stops retrying when the context is canceled or its deadline is exceeded.
+func (c Client) GetCtx(ctx context.Context, k string, v any) (found bool, err error) {
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return false, err
 	}
@@ -79,11 +94,11 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 		TableName: &c.tableName,
 		Key:       key,
 	}
-	getItemOutput, err := c.c.GetItem(&getItemInput)
+	getItemOutput, err := c.c.GetItemWithContext(ctx, &getItemInput)
 	if err != nil {
 		return false, err
-	} else if getItemOutput.Item == nil {
-		// Return false if the key-value pair doesn't exist
+	} else if getItemOutput.Item == nil || isExpired(getItemOutput.Item) {
+		// Return false if the key-value pair doesn't exist or expired
 		return false, nil
 	}
 	attributeVal := getItemOutput.Item[valAttrName]
@@ -92,7 +107,7 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 		// TODO: Maybe return an error? Behaviour should be consistent across all implementations.
 		return false, nil
 	}
-	data := attributeVal.B
+	data := valueData(attributeVal)
 
 	return true, c.codec.Unmarshal(data, v)
 }
@@ -101,6 +116,12 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 // Deleting a non-existing key-value pair does NOT lead to an error.
 // The key must not be "".
 func (c Client) Delete(k string) error {
+	return c.DeleteCtx(context.Background(), k)
+}
+
+// DeleteCtx is like Delete, but the context is passed to the DynamoDB SDK,
+// which also stops retrying when the context is canceled or its deadline is exceeded.
+func (c Client) DeleteCtx(ctx context.Context, k string) error {
 	if err := util.CheckKey(k); err != nil {
 		return err
 	}
@@ -113,10 +134,15 @@ func (c Client) Delete(k string) error {
 		TableName: &c.tableName,
 		Key:       key,
 	}
-	_, err := c.c.DeleteItem(&deleteItemInput)
+	_, err := c.c.DeleteItemWithContext(ctx, &deleteItemInput)
 	return err
 }
 
+// Kind returns gokv.KindDynamoDB.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindDynamoDB
+}
+
 // Close closes the client.
 // In the DynamoDB implementation this doesn't have any effect.
 func (c Client) Close() error {
@@ -134,6 +160,12 @@ type Options struct {
 	// Name of the DynamoDB table.
 	// Optional ("gokv" by default).
 	TableName string
+	// Billing mode of the table, either awsdynamodb.BillingModeProvisioned ("PROVISIONED")
+	// or awsdynamodb.BillingModePayPerRequest ("PAY_PER_REQUEST") for on-demand capacity.
+	// With PAY_PER_REQUEST, ReadCapacityUnits and WriteCapacityUnits are ignored.
+	// Only used when the table doesn't exist yet and is created by gokv.
+	// Optional ("PROVISIONED" by default).
+	BillingMode string
 	// ReadCapacityUnits of the table.
 	// Only required when the table doesn't exist yet and is created by gokv.
 	// Optional (5 by default, which is the same default value as when creating a table in the web console)
@@ -173,21 +205,39 @@ type Options struct {
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
+	// Function that extracts a group from a key, for example the tenant in a multi-tenant application.
+	// When set, the group is stored in the "g" attribute of each item and the keys of a group
+	// can be listed with KeysForGroup, which queries a Global Secondary Index on that attribute.
+	// Keys for which the function returns "" don't belong to any group.
+	// The index is only created when gokv creates the table (with the same billing mode and capacity units as the table),
+	// so for an existing table an index with the name "gokv-group", "g" as partition key and "k" as sort key
+	// must be added manually.
+	// Optional (nil by default).
+	GroupFunc func(k string) string
+	// Store integer values (all int and uint types) as number attributes instead of marshalling them with the codec.
+	// Such a value can then be atomically incremented with Increment, without having to be created by Increment first.
+	// Get passes the decimal representation of number attributes to the codec,
+	// so reading them requires a codec that can unmarshal a decimal number into an integer, like encoding.JSON.
+	// Other values are still marshalled with the codec.
+	// Optional (false by default).
+	NumericValues bool
 }
 
 // DefaultOptions is an Options object with default values.
 // Region: "" (use shared config file or environment variable), TableName: "gokv",
+// BillingMode: "PROVISIONED", ReadCapacityUnits: 5, WriteCapacityUnits: 5, WaitForTableCreation: true,
 // AWSaccessKeyID: "" (use shared credentials file or environment variable),
 // AWSsecretAccessKey: "" (use shared credentials file or environment variable),
-// CustomEndpoint: "", Codec: encoding.JSON
+// CustomEndpoint: "", Codec: encoding.JSON, GroupFunc: nil, NumericValues: false
 var DefaultOptions = Options{
 	TableName:            "gokv",
+	BillingMode:          awsdynamodb.BillingModeProvisioned,
 	ReadCapacityUnits:    5,
 	WriteCapacityUnits:   5,
 	WaitForTableCreation: aws.Bool(true),
 	Codec:                encoding.JSON,
-	// No need to set Region, AWSaccessKeyID, AWSsecretAccessKey
-	// or CustomEndpoint because their Go zero values are fine.
+	// No need to set Region, AWSaccessKeyID, AWSsecretAccessKey,
+	// CustomEndpoint, GroupFunc or NumericValues because their Go zero values are fine.
 }
 
 // NewClient creates a new DynamoDB client.
@@ -203,6 +253,9 @@ func NewClient(options Options) (Client, error) {
 	if options.TableName == "" {
 		options.TableName = DefaultOptions.TableName
 	}
+	if options.BillingMode == "" {
+		options.BillingMode = DefaultOptions.BillingMode
+	}
 	if options.ReadCapacityUnits == 0 {
 		options.ReadCapacityUnits = DefaultOptions.ReadCapacityUnits
 	}
@@ -215,6 +268,12 @@ func NewClient(options Options) (Client, error) {
 	if options.Codec == nil {
 		options.Codec = DefaultOptions.Codec
 	}
+
+	// Precondition check
+	if options.BillingMode != awsdynamodb.BillingModeProvisioned && options.BillingMode != awsdynamodb.BillingModePayPerRequest {
+		return result, errors.New("The BillingMode must be either \"PROVISIONED\" or \"PAY_PER_REQUEST\"")
+	}
+
 	// Set credentials only if set in the options.
 	// If not set, the SDK uses the shared credentials file or environment variables, which is the preferred way.
 	// Return an error if only one of the values is set.
@@ -262,7 +321,7 @@ func NewClient(options Options) (Client, error) {
 		if !ok {
 			return result, err
 		} else if awsErr.Code() == awsdynamodb.ErrCodeResourceNotFoundException {
-			err = createTable(options.TableName, options.ReadCapacityUnits, options.WriteCapacityUnits, *options.WaitForTableCreation, describeTableInput, svc)
+			err = createTable(options, describeTableInput, svc)
 			if err != nil {
 				return result, err
 			}
@@ -274,15 +333,17 @@ func NewClient(options Options) (Client, error) {
 	result.c = svc
 	result.tableName = options.TableName
 	result.codec = options.Codec
+	result.groupFunc = options.GroupFunc
+	result.numericValues = options.NumericValues
 
 	return result, nil
 }
 
-func createTable(tableName string, readCapacityUnits, writeCapacityUnits int64, waitForTableCreation bool, describeTableInput awsdynamodb.DescribeTableInput, svc *awsdynamodb.DynamoDB) error {
+func createTable(options Options, describeTableInput awsdynamodb.DescribeTableInput, svc *awsdynamodb.DynamoDB) error {
 	keyAttrType := "S" // For "string"
 	keyType := "HASH"  // As opposed to "RANGE"
 	createTableInput := awsdynamodb.CreateTableInput{
-		TableName: &tableName,
+		TableName: &options.TableName,
 		AttributeDefinitions: []*awsdynamodb.AttributeDefinition{{
 			AttributeName: &keyAttrName,
 			AttributeType: &keyAttrType,
@@ -291,10 +352,17 @@ func createTable(tableName string, readCapacityUnits, writeCapacityUnits int64,
 			AttributeName: &keyAttrName,
 			KeyType:       &keyType,
 		}},
-		ProvisionedThroughput: &awsdynamodb.ProvisionedThroughput{
-			ReadCapacityUnits:  &readCapacityUnits,
-			WriteCapacityUnits: &writeCapacityUnits,
-		},
+		BillingMode: &options.BillingMode,
+	}
+	// With on-demand capacity, DynamoDB rejects a ProvisionedThroughput for the table and its indexes
+	if options.BillingMode == awsdynamodb.BillingModeProvisioned {
+		createTableInput.ProvisionedThroughput = &awsdynamodb.ProvisionedThroughput{
+			ReadCapacityUnits:  &options.ReadCapacityUnits,
+			WriteCapacityUnits: &options.WriteCapacityUnits,
+		}
+	}
+	if options.GroupFunc != nil {
+		addGroupIndex(&createTableInput)
 	}
 	_, err := svc.CreateTable(&createTableInput)
 	if err != nil {
@@ -302,7 +370,7 @@ func createTable(tableName string, readCapacityUnits, writeCapacityUnits int64,
 	}
 	// If configured (true by default), block until the table is created.
 	// Typical table creation duration is 10 seconds.
-	if waitForTableCreation {
+	if *options.WaitForTableCreation {
 		for try := 1; try < 16; try++ {
 			describeTableOutput, err := svc.DescribeTable(&describeTableInput)
 			if err != nil || *describeTableOutput.Table.TableStatus == "CREATING" {
@@ -3,7 +3,11 @@ package dynamodb_test
 import (
 	"context"
 	"log"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -13,6 +17,7 @@ import (
 	"github.com/aws/aws-sdk-go/aws/session"
 	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/dynamodb"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/test"
@@ -60,6 +65,21 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the DynamoDB client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -69,6 +89,361 @@ func TestClientConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, client)
 }
 
+// TestLock tests that two clients can't hold the same lock simultaneously
+// and that a lock automatically expires after its TTL, unless it's refreshed by gokv.WithLock.
+func TestLock(t *testing.T) {
+	clientA := createClient(t, encoding.JSON)
+	clientB := createClient(t, encoding.JSON)
+
+	err := clientA.Delete("lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.TestLocker(t, clientA, clientB, "lock", time.Second)
+	test.TestWithLock(t, clientA, clientB, "lock", time.Second)
+}
+
+// TestContext tests that the context is passed to the DynamoDB SDK.
+func TestContext(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+
+	var storeCtx gokv.StoreCtx = client
+	err := storeCtx.SetCtx(context.Background(), "foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := test.Foo{}
+	found, err := storeCtx.GetCtx(context.Background(), "foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = storeCtx.SetCtx(canceledCtx, "foo", test.Foo{Bar: "qux"})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = storeCtx.GetCtx(canceledCtx, "foo", &actual)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = storeCtx.DeleteCtx(canceledCtx, "foo")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	err = storeCtx.DeleteCtx(context.Background(), "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestDeleteIf tests that a key-value pair is only deleted when it holds the expected value.
+func TestDeleteIf(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	test.TestConditionalDeleter(client, t)
+}
+
+// TestDeleteIfExpired tests that an expired key-value pair that DynamoDB hasn't deleted yet isn't deleted by DeleteIf,
+// just like Get doesn't find it.
+func TestDeleteIfExpired(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	setExpired(t, client, "foo", "bar")
+
+	deleted, err := client.DeleteIf("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted {
+		t.Error("Expected the expired value not to be deleted")
+	}
+}
+
+// TestCompareAndSwap tests that a value is only replaced when it holds the expected value.
+func TestCompareAndSwap(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	test.TestCASStore(client, t)
+}
+
+// TestCompareAndSwapExpired tests that an expired key-value pair that DynamoDB hasn't deleted yet isn't swapped,
+// just like Get doesn't find it.
+func TestCompareAndSwapExpired(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	setExpired(t, client, "foo", "bar")
+
+	swapped, err := client.CompareAndSwap("foo", "bar", "baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapped {
+		t.Error("Expected the expired value not to be swapped")
+	}
+	found, err := client.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("Expected the value to still be expired")
+	}
+}
+
+// TestSetIfAbsent tests that a value is only stored when the key doesn't exist yet.
+func TestSetIfAbsent(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	test.TestConditionalSetter(client, t)
+}
+
+// TestUpdateFunc tests if concurrent updates via gokv.UpdateFunc don't get lost.
+func TestUpdateFunc(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	test.TestUpdateFunc(client, t)
+}
+
+// TestCounter tests if concurrent increments of a counter are atomic.
+func TestCounter(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	test.TestCounterStore(client, t)
+}
+
+// TestCounterExpired tests that an expired key-value pair that DynamoDB hasn't deleted yet
+// is treated like a non-existing counter, even if its value isn't a number.
+func TestCounterExpired(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	setExpired(t, client, "foo", "bar")
+
+	count, err := client.Increment("foo", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("Expected %v, but was %v", 3, count)
+	}
+	count, err = client.Increment("foo", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Errorf("Expected %v, but was %v", 5, count)
+	}
+	var actual int64
+	found, err := client.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual != 5 {
+		t.Errorf("Expected %v, but found was %v and the value %v", 5, found, actual)
+	}
+}
+
+// TestNumericValues tests if integers that are stored with Set can be atomically incremented
+// when they're stored as number attributes.
+func TestNumericValues(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to DynamoDB could be established. Probably not running in a proper test environment.")
+	}
+
+	options := dynamodb.Options{
+		Region:             endpoints.EuCentral1RegionID,
+		AWSaccessKeyID:     "foo",
+		AWSsecretAccessKey: "foo",
+		CustomEndpoint:     customEndpoint,
+		NumericValues:      true,
+	}
+	client, err := dynamodb.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Other values still work as usual
+	test.TestStore(client, t)
+
+	err = client.Set("numeric", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goroutineCount := 20
+	incrementsPerGoroutine := 10
+	var wg sync.WaitGroup
+	wg.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < incrementsPerGoroutine; j++ {
+				_, err := client.Increment("numeric", 1)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	expected := int64(100 + goroutineCount*incrementsPerGoroutine)
+	var actual int64
+	found, err := client.Get("numeric", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual != expected {
+		t.Errorf("Expected %v, but found was %v and the value %v", expected, found, actual)
+	}
+
+	// Numbers can also be compared
+	swapped, err := client.CompareAndSwap("numeric", expected, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !swapped {
+		t.Error("Expected the value to be swapped")
+	}
+}
+
+// TestKeysForGroup tests if the keys of different groups are listed independently via the group index.
+func TestKeysForGroup(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to DynamoDB could be established. Probably not running in a proper test environment.")
+	}
+
+	// A separate table is used, because the index is only created together with the table
+	options := dynamodb.Options{
+		Region:             endpoints.EuCentral1RegionID,
+		TableName:          "gokv-groups",
+		AWSaccessKeyID:     "foo",
+		AWSsecretAccessKey: "foo",
+		CustomEndpoint:     customEndpoint,
+		GroupFunc: func(k string) string {
+			group, _, found := strings.Cut(k, "/")
+			if !found {
+				return ""
+			}
+			return group
+		},
+	}
+	client, err := dynamodb.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values := map[string]any{
+		"tenant1/foo": "bar",
+		"tenant1/baz": "qux",
+		"tenant2/foo": "bar",
+		"nogroup":     "bar",
+	}
+	for k, v := range values {
+		err = client.Set(k, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = client.Increment("tenant2/counter", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string][]string{
+		"tenant1": {"tenant1/baz", "tenant1/foo"},
+		"tenant2": {"tenant2/counter", "tenant2/foo"},
+		"tenant3": nil,
+	}
+	for group, expectedKeys := range expected {
+		keys, err := client.KeysForGroup(group)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(keys)
+		if !reflect.DeepEqual(keys, expectedKeys) {
+			t.Errorf("Expected the keys %v for group %q, but were %v", expectedKeys, group, keys)
+		}
+	}
+
+	_, err = client.KeysForGroup("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = createClient(t, encoding.JSON).KeysForGroup("tenant1")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestBillingMode tests if a table with on-demand capacity can be created and used.
+func TestBillingMode(t *testing.T) {
+	// The billing mode is checked before connecting
+	_, err := dynamodb.NewClient(dynamodb.Options{BillingMode: "FREE"})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	if !checkConnection() {
+		t.Skip("No connection to DynamoDB could be established. Probably not running in a proper test environment.")
+	}
+
+	// A separate table is used, because the billing mode is only set when the table is created
+	options := dynamodb.Options{
+		Region:             endpoints.EuCentral1RegionID,
+		TableName:          "gokv-on-demand",
+		BillingMode:        awsdynamodb.BillingModePayPerRequest,
+		AWSaccessKeyID:     "foo",
+		AWSsecretAccessKey: "foo",
+		CustomEndpoint:     customEndpoint,
+	}
+	client, err := dynamodb.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.TestStore(client, t)
+}
+
+// TestTTL tests if key-value pairs expire after their TTL.
+func TestTTL(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	test.TestTTLStore(client, t)
+}
+
+// TestBatch tests if the gokv.BatchStore methods work properly.
+func TestBatch(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	test.TestBatchStore(client, t)
+
+	// More items than fit into a single request
+	values := make(map[string]any, 120)
+	keys := make([]string, 0, 120)
+	for i := 0; i < 120; i++ {
+		k := "batch" + strconv.Itoa(i)
+		values[k] = i
+		keys = append(keys, k)
+	}
+	err := client.SetMany(values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := make([]*int, len(keys))
+	for i := range actual {
+		actual[i] = new(int)
+	}
+	found, err := client.GetMany(keys, actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range keys {
+		if !found[i] || *actual[i] != i {
+			t.Errorf("Expected %v to be found for key %v, but found was %v and the value %v", i, keys[i], found[i], *actual[i])
+		}
+	}
+	err = client.DeleteMany(keys)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -209,6 +584,24 @@ func checkConnection() bool {
 	return true
 }
 
+// setExpired stores a key-value pair with the shortest possible TTL and waits until it's expired.
+// DynamoDB deletes expired items only after a while, and DynamoDB local not at all, so the item still exists afterwards.
+func setExpired(t *testing.T, client dynamodb.Client, k string, v any) {
+	err := client.SetWithTTL(k, v, time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The expiry has a precision of one second and is rounded up
+	time.Sleep(2 * time.Second)
+	found, err := client.Get(k, new(any))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Fatal("Expected the value to be expired")
+	}
+}
+
 func createClient(t *testing.T, codec encoding.Codec) dynamodb.Client {
 	options := dynamodb.Options{
 		Region:             endpoints.EuCentral1RegionID,
@@ -0,0 +1,101 @@
+package dynamodb
+
+import (
+	"errors"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// "g" is used as table column name for the group of a key, as determined by Options.GroupFunc.
+var groupAttrName = "g"
+
+// Name of the Global Secondary Index on the group attribute.
+var groupIndexName = "gokv-group"
+
+// KeysForGroup returns the keys of all key-value pairs in the given group,
+// as determined by Options.GroupFunc when they were stored.
+// It uses a Query on the Global Secondary Index of the group attribute instead of scanning the whole table.
+// The index is eventually consistent, so recently stored or deleted key-value pairs
+// can be missing or still be included for a short time.
+// The order of the keys is undefined.
+// Options.GroupFunc must be set and the group must not be "".
+func (c Client) KeysForGroup(group string) ([]string, error) {
+	if c.groupFunc == nil {
+		return nil, errors.New("KeysForGroup requires the GroupFunc option")
+	}
+	if group == "" {
+		return nil, errors.New("The group must not be \"\"")
+	}
+
+	keyCondition := "#g = :g"
+	queryInput := awsdynamodb.QueryInput{
+		TableName:              &c.tableName,
+		IndexName:              &groupIndexName,
+		KeyConditionExpression: &keyCondition,
+		ExpressionAttributeNames: map[string]*string{
+			"#g": &groupAttrName,
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":g": {S: &group},
+		},
+	}
+	var keys []string
+	for {
+		queryOutput, err := c.c.Query(&queryInput)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range queryOutput.Items {
+			keyAttr := item[keyAttrName]
+			if keyAttr == nil || keyAttr.S == nil || isExpired(item) {
+				continue
+			}
+			keys = append(keys, *keyAttr.S)
+		}
+		if len(queryOutput.LastEvaluatedKey) == 0 {
+			return keys, nil
+		}
+		queryInput.ExclusiveStartKey = queryOutput.LastEvaluatedKey
+	}
+}
+
+// addGroup adds the group attribute to the item if Options.GroupFunc is set.
+func (c Client) addGroup(k string, item map[string]*awsdynamodb.AttributeValue) {
+	if c.groupFunc == nil {
+		return
+	}
+	// Items without the attribute aren't included in the index
+	if group := c.groupFunc(k); group != "" {
+		item[groupAttrName] = &awsdynamodb.AttributeValue{
+			S: &group,
+		}
+	}
+}
+
+// addGroupIndex adds the group attribute and the Global Secondary Index on it to the table definition.
+// The index has the key as sort key and includes the expiry, so that KeysForGroup can filter expired items.
+func addGroupIndex(createTableInput *awsdynamodb.CreateTableInput) {
+	attrType := awsdynamodb.ScalarAttributeTypeS
+	hashKeyType := awsdynamodb.KeyTypeHash
+	rangeKeyType := awsdynamodb.KeyTypeRange
+	projectionType := awsdynamodb.ProjectionTypeInclude
+	createTableInput.AttributeDefinitions = append(createTableInput.AttributeDefinitions, &awsdynamodb.AttributeDefinition{
+		AttributeName: &groupAttrName,
+		AttributeType: &attrType,
+	})
+	createTableInput.GlobalSecondaryIndexes = []*awsdynamodb.GlobalSecondaryIndex{{
+		IndexName: &groupIndexName,
+		KeySchema: []*awsdynamodb.KeySchemaElement{{
+			AttributeName: &groupAttrName,
+			KeyType:       &hashKeyType,
+		}, {
+			AttributeName: &keyAttrName,
+			KeyType:       &rangeKeyType,
+		}},
+		Projection: &awsdynamodb.Projection{
+			ProjectionType:   &projectionType,
+			NonKeyAttributes: []*string{&ttlAttrName},
+		},
+		ProvisionedThroughput: createTableInput.ProvisionedThroughput,
+	}}
+}
@@ -0,0 +1,167 @@
+package dynamodb
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// "t" is used as table column name for the token that identifies the holder of a lock.
+var tokenAttrName = "t"
+
+// "e" is used as table column name for the expiry of a lock, in Unix milliseconds.
+var expiryAttrName = "e"
+
+// AcquireLock acquires the lock with the given key via a conditional write,
+// which only succeeds if there's no lock item yet or if the existing one expired.
+// If the lock is currently held by someone else, gokv.ErrLockHeld is returned.
+// The lock automatically expires after the TTL, unless it's refreshed before,
+// so the holder should call Refresh() periodically as heartbeat.
+// The expiry is based on the clocks of the clients, so they should be roughly in sync.
+// The key must not be "" and the TTL must be positive.
+func (c Client) AcquireLock(k string, ttl time.Duration) (gokv.Lock, error) {
+	if err := util.CheckKey(k); err != nil {
+		return nil, err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(b)
+
+	now := time.Now()
+	item := make(map[string]*awsdynamodb.AttributeValue)
+	item[keyAttrName] = &awsdynamodb.AttributeValue{
+		S: &k,
+	}
+	item[tokenAttrName] = &awsdynamodb.AttributeValue{
+		S: &token,
+	}
+	item[expiryAttrName] = unixMilliAttr(now.Add(ttl))
+	conditionExpression := "attribute_not_exists(#k) OR #e < :now"
+	putItemInput := awsdynamodb.PutItemInput{
+		TableName:           &c.tableName,
+		Item:                item,
+		ConditionExpression: &conditionExpression,
+		ExpressionAttributeNames: map[string]*string{
+			"#k": &keyAttrName,
+			"#e": &expiryAttrName,
+		},
+		ExpressionAttributeValues: map[string]*awsdynamodb.AttributeValue{
+			":now": unixMilliAttr(now),
+		},
+	}
+	_, err := c.c.PutItem(&putItemInput)
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return nil, gokv.ErrLockHeld
+		}
+		return nil, err
+	}
+	return lock{
+		c:     c,
+		key:   k,
+		token: token,
+	}, nil
+}
+
+// lock is a gokv.Lock implementation for DynamoDB.
+type lock struct {
+	c     Client
+	key   string
+	token string
+}
+
+// Release releases the lock by deleting the lock item,
+// but only if it's still held with the same token and didn't expire.
+// If the lock expired in the meantime, gokv.ErrLockLost is returned.
+func (l lock) Release() error {
+	key := make(map[string]*awsdynamodb.AttributeValue)
+	key[keyAttrName] = &awsdynamodb.AttributeValue{
+		S: &l.key,
+	}
+	conditionExpression := "#t = :token AND #e >= :now"
+	deleteItemInput := awsdynamodb.DeleteItemInput{
+		TableName:                 &l.c.tableName,
+		Key:                       key,
+		ConditionExpression:       &conditionExpression,
+		ExpressionAttributeNames:  l.attrNames(),
+		ExpressionAttributeValues: l.attrValues(time.Now()),
+	}
+	_, err := l.c.c.DeleteItem(&deleteItemInput)
+	if isConditionalCheckFailed(err) {
+		return gokv.ErrLockLost
+	}
+	return err
+}
+
+// Refresh extends the lock's expiry to the given TTL from now,
+// but only if it's still held with the same token and didn't expire.
+// If the lock expired in the meantime, gokv.ErrLockLost is returned.
+// The TTL must be positive.
+func (l lock) Refresh(ttl time.Duration) error {
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+	now := time.Now()
+	key := make(map[string]*awsdynamodb.AttributeValue)
+	key[keyAttrName] = &awsdynamodb.AttributeValue{
+		S: &l.key,
+	}
+	updateExpression := "SET #e = :expiry"
+	conditionExpression := "#t = :token AND #e >= :now"
+	attrValues := l.attrValues(now)
+	attrValues[":expiry"] = unixMilliAttr(now.Add(ttl))
+	updateItemInput := awsdynamodb.UpdateItemInput{
+		TableName:                 &l.c.tableName,
+		Key:                       key,
+		UpdateExpression:          &updateExpression,
+		ConditionExpression:       &conditionExpression,
+		ExpressionAttributeNames:  l.attrNames(),
+		ExpressionAttributeValues: attrValues,
+	}
+	_, err := l.c.c.UpdateItem(&updateItemInput)
+	if isConditionalCheckFailed(err) {
+		return gokv.ErrLockLost
+	}
+	return err
+}
+
+func (l lock) attrNames() map[string]*string {
+	return map[string]*string{
+		"#t": &tokenAttrName,
+		"#e": &expiryAttrName,
+	}
+}
+
+func (l lock) attrValues(now time.Time) map[string]*awsdynamodb.AttributeValue {
+	return map[string]*awsdynamodb.AttributeValue{
+		":token": {
+			S: &l.token,
+		},
+		":now": unixMilliAttr(now),
+	}
+}
+
+func unixMilliAttr(t time.Time) *awsdynamodb.AttributeValue {
+	n := strconv.FormatInt(t.UnixMilli(), 10)
+	return &awsdynamodb.AttributeValue{
+		N: &n,
+	}
+}
+
+func isConditionalCheckFailed(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	return ok && awsErr.Code() == awsdynamodb.ErrCodeConditionalCheckFailedException
+}
@@ -0,0 +1,84 @@
+package dynamodb
+
+import (
+	"strconv"
+	"time"
+
+	awsdynamodb "github.com/aws/aws-sdk-go/service/dynamodb"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// "ttl" is used as table column name for the expiry of a key-value pair, in Unix seconds,
+// which is the format that DynamoDB's Time to Live feature requires.
+var ttlAttrName = "ttl"
+
+// SetWithTTL stores the given value for the given key, like Set,
+// but with an expiry in the "ttl" attribute.
+// After the TTL, Get returns (false, nil) for the key.
+// DynamoDB only deletes expired items if Time to Live is enabled for the table with "ttl" as attribute name,
+// and even then it can take a while, but Get doesn't return expired items in any case.
+// The expiry has a precision of one second and is rounded up,
+// and it's based on the clocks of the clients, so they should be roughly in sync.
+// The key must not be "", the value must not be nil and the TTL must be positive.
+func (c Client) SetWithTTL(k string, v any, ttl time.Duration) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+
+	valAttr, err := c.valueAttr(v)
+	if err != nil {
+		return err
+	}
+
+	expiry := time.Now().Add(ttl)
+	expirySeconds := expiry.Unix()
+	if expiry.Nanosecond() > 0 {
+		expirySeconds++
+	}
+	n := strconv.FormatInt(expirySeconds, 10)
+	item := make(map[string]*awsdynamodb.AttributeValue)
+	item[keyAttrName] = &awsdynamodb.AttributeValue{
+		S: &k,
+	}
+	item[valAttrName] = valAttr
+	item[ttlAttrName] = &awsdynamodb.AttributeValue{
+		N: &n,
+	}
+	c.addGroup(k, item)
+	putItemInput := awsdynamodb.PutItemInput{
+		TableName: &c.tableName,
+		Item:      item,
+	}
+	_, err = c.c.PutItem(&putItemInput)
+	return err
+}
+
+// isExpired returns true if the item has an expiry that has passed.
+// DynamoDB can take a while to delete expired items, so they must be filtered when reading.
+func isExpired(item map[string]*awsdynamodb.AttributeValue) bool {
+	attr := item[ttlAttrName]
+	if attr == nil || attr.N == nil {
+		return false
+	}
+	expirySeconds, err := strconv.ParseInt(*attr.N, 10, 64)
+	if err != nil {
+		return false
+	}
+	return time.Now().Unix() >= expirySeconds
+}
+
+// notExpiredCondition is the counterpart of isExpired for condition expressions,
+// so that conditional writes don't match expired items that DynamoDB hasn't deleted yet.
+// It requires the placeholders that addNotExpiredPlaceholders adds.
+const notExpiredCondition = "(attribute_not_exists(#ttl) OR #ttl > :now)"
+
+// addNotExpiredPlaceholders adds the placeholders that notExpiredCondition requires.
+func addNotExpiredPlaceholders(names map[string]*string, values map[string]*awsdynamodb.AttributeValue) {
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	names["#ttl"] = &ttlAttrName
+	values[":now"] = &awsdynamodb.AttributeValue{N: &now}
+}
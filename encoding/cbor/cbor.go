@@ -0,0 +1,40 @@
+package cbor
+
+import (
+	"github.com/fxamacker/cbor/v2"
+)
+
+// cborEncMode encodes times as RFC 3339 strings with nanoseconds and the standard tag 0,
+// instead of the default Unix seconds, which would lose precision and the time zone.
+var cborEncMode = func() cbor.EncMode {
+	encMode, err := cbor.EncOptions{
+		Time:    cbor.TimeRFC3339Nano,
+		TimeTag: cbor.EncTagRequired,
+	}.EncMode()
+	if err != nil {
+		panic(err)
+	}
+	return encMode
+}()
+
+// Convenience variable for simpler usage in gokv store options.
+//
+//	options := redis.Options{
+//		Codec: cbor.Codec,
+//	}
+var Codec = CBORcodec{}
+
+// CBORcodec encodes/decodes Go values to/from CBOR (RFC 8949),
+// which is useful for exchanging data with services written in other languages.
+// Slices of bytes are encoded as CBOR byte strings.
+type CBORcodec struct{}
+
+// Marshal encodes a Go value to CBOR.
+func (c CBORcodec) Marshal(v any) ([]byte, error) {
+	return cborEncMode.Marshal(v)
+}
+
+// Unmarshal decodes a CBOR value into a Go value.
+func (c CBORcodec) Unmarshal(data []byte, v any) error {
+	return cbor.Unmarshal(data, v)
+}
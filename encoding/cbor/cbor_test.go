@@ -0,0 +1,103 @@
+package cbor_test
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/philippgille/gokv/encoding/cbor"
+)
+
+type foo struct {
+	Bar  string
+	Baz  int
+	Qux  []string
+	Quux map[string]float64
+	Time time.Time
+}
+
+// TestCBORRoundTrip tests that a struct is unmarshalled to the original value.
+func TestCBORRoundTrip(t *testing.T) {
+	expected := foo{
+		Bar:  "baz",
+		Baz:  -42,
+		Qux:  []string{"a", "b"},
+		Quux: map[string]float64{"pi": 3.14},
+		// The nanoseconds must survive the round trip
+		Time: time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("CET", 3600)),
+	}
+	data, err := cbor.Codec.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := foo{}
+	err = cbor.Codec.Unmarshal(data, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The location isn't necessarily the same pointer after the round trip, so the times are compared separately
+	if !actual.Time.Equal(expected.Time) {
+		t.Errorf("Expected %v, but was %v", expected.Time, actual.Time)
+	}
+	actual.Time, expected.Time = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %+v, but was %+v", expected, actual)
+	}
+}
+
+// TestCBORErrors tests that marshalling an unsupported value and unmarshalling invalid data lead to errors.
+func TestCBORErrors(t *testing.T) {
+	_, err := cbor.Codec.Marshal(make(chan int))
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+
+	actual := foo{}
+	// Truncated data
+	data, err := cbor.Codec.Marshal(foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cbor.Codec.Unmarshal(data[:len(data)-2], &actual)
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+	// Type mismatch
+	data, err = cbor.Codec.Marshal("baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = cbor.Codec.Unmarshal(data, &actual)
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+	// Non-pointer
+	err = cbor.Codec.Unmarshal(data, actual)
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+}
+
+// TestCBORByteString tests that slices of bytes are encoded as CBOR byte strings instead of arrays.
+func TestCBORByteString(t *testing.T) {
+	data, err := cbor.Codec.Marshal([]byte("foo"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Major type 2 (byte string) with length 3, followed by the bytes
+	expected := []byte{0x43, 'f', 'o', 'o'}
+	if !bytes.Equal(data, expected) {
+		t.Errorf("Expected %x, but was %x", expected, data)
+	}
+
+	var actual []byte
+	err = cbor.Codec.Unmarshal(data, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(actual) != "foo" {
+		t.Errorf("Expected %q, but was %q", "foo", actual)
+	}
+}
@@ -0,0 +1,7 @@
+module github.com/philippgille/gokv/encoding/cbor
+
+go 1.20
+
+require github.com/fxamacker/cbor/v2 v2.9.4
+
+require github.com/x448/float16 v0.8.4 // indirect
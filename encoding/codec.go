@@ -1,6 +1,9 @@
 package encoding
 
 // Codec encodes/decodes Go values to/from slices of bytes.
+//
+// Note: Switching the codec of a store that already contains data makes the previously written values unreadable,
+// because they're not converted. Either migrate the existing values or start with an empty store.
 type Codec interface {
 	// Marshal encodes a Go value to a slice of bytes.
 	Marshal(v any) ([]byte, error)
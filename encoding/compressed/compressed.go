@@ -0,0 +1,170 @@
+package compressed
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+
+	"github.com/philippgille/gokv/encoding"
+)
+
+// Compression is a compression algorithm for the CompressedCodec.
+type Compression int
+
+// Supported compression algorithms.
+const (
+	Gzip Compression = iota + 1
+	Zstd
+)
+
+// DefaultCompressionThreshold is the size in bytes below which the CompressedCodec doesn't compress values.
+// Compressing tiny values costs time and usually doesn't save any space.
+const DefaultCompressionThreshold = 256
+
+// Headers that precede the data of the CompressedCodec.
+// Neither JSON nor gob data can start with these bytes,
+// so values that were stored before compression was enabled can still be read.
+const (
+	headerUncompressed byte = 0x80
+	headerGzip         byte = 0x81
+	headerZstd         byte = 0x82
+)
+
+// The zstd encoder and decoder are safe for concurrent use via EncodeAll and DecodeAll,
+// so they're only created once.
+var (
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+	zstdErr     error
+)
+
+// CompressedCodec wraps another codec and compresses the data that the other codec marshals.
+// A one-byte header tells Unmarshal whether and how the data was compressed.
+// Data without header is passed to the other codec as it is,
+// so compression can be enabled for a store that already contains JSON or gob values.
+// Values that don't get smaller are stored uncompressed.
+// Create it with NewCodec or NewCodecWithThreshold.
+type CompressedCodec struct {
+	inner       encoding.Codec
+	compression Compression
+	threshold   int
+}
+
+// NewCodec creates a CompressedCodec that uses the given codec and compression algorithm.
+// Values that are marshalled to less than DefaultCompressionThreshold bytes aren't compressed.
+func NewCodec(inner encoding.Codec, compression Compression) CompressedCodec {
+	return NewCodecWithThreshold(inner, compression, DefaultCompressionThreshold)
+}
+
+// NewCodecWithThreshold is like NewCodec,
+// but values that are marshalled to less than threshold bytes aren't compressed.
+func NewCodecWithThreshold(inner encoding.Codec, compression Compression, threshold int) CompressedCodec {
+	return CompressedCodec{
+		inner:       inner,
+		compression: compression,
+		threshold:   threshold,
+	}
+}
+
+// Marshal encodes a Go value with the inner codec and compresses the result.
+func (c CompressedCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) >= c.threshold {
+		var compressed []byte
+		switch c.compression {
+		case Gzip:
+			compressed, err = gzipCompress(data)
+		case Zstd:
+			compressed, err = zstdCompress(data)
+		default:
+			return nil, errors.New("Unknown compression algorithm")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(compressed) < len(data) {
+			return compressed, nil
+		}
+	}
+
+	return append([]byte{headerUncompressed}, data...), nil
+}
+
+// Unmarshal decompresses the data if necessary and decodes it with the inner codec.
+func (c CompressedCodec) Unmarshal(data []byte, v any) error {
+	if len(data) == 0 {
+		return c.inner.Unmarshal(data, v)
+	}
+
+	var err error
+	switch data[0] {
+	case headerUncompressed:
+		data = data[1:]
+	case headerGzip:
+		data, err = gzipDecompress(data[1:])
+	case headerZstd:
+		data, err = zstdDecompress(data[1:])
+	default:
+		// Stored before compression was enabled
+	}
+	if err != nil {
+		return err
+	}
+
+	return c.inner.Unmarshal(data, v)
+}
+
+func gzipCompress(data []byte) ([]byte, error) {
+	buffer := bytes.NewBuffer([]byte{headerGzip})
+	writer := gzip.NewWriter(buffer)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func gzipDecompress(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+func initZstd() error {
+	zstdOnce.Do(func() {
+		zstdEncoder, zstdErr = zstd.NewWriter(nil)
+		if zstdErr != nil {
+			return
+		}
+		zstdDecoder, zstdErr = zstd.NewReader(nil)
+	})
+	return zstdErr
+}
+
+func zstdCompress(data []byte) ([]byte, error) {
+	if err := initZstd(); err != nil {
+		return nil, err
+	}
+	return zstdEncoder.EncodeAll(data, []byte{headerZstd}), nil
+}
+
+func zstdDecompress(data []byte) ([]byte, error) {
+	if err := initZstd(); err != nil {
+		return nil, err
+	}
+	return zstdDecoder.DecodeAll(data, nil)
+}
@@ -0,0 +1,136 @@
+package compressed_test
+
+import (
+	"crypto/rand"
+	"strings"
+	"testing"
+
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/encoding/compressed"
+)
+
+type foo struct {
+	Bar string
+}
+
+var compressions = map[string]compressed.Compression{
+	"gzip": compressed.Gzip,
+	"zstd": compressed.Zstd,
+}
+
+// TestCompressed tests that a highly compressible value shrinks and is unmarshalled to the original value.
+func TestCompressed(t *testing.T) {
+	for name, compression := range compressions {
+		t.Run(name, func(t *testing.T) {
+			codec := compressed.NewCodec(encoding.JSON, compression)
+			expected := foo{Bar: strings.Repeat("baz", 1000)}
+
+			data, err := codec.Marshal(expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			uncompressed, err := encoding.JSON.Marshal(expected)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(data) >= len(uncompressed)/10 {
+				t.Errorf("Expected the data to shrink to less than %v bytes, but it's %v bytes", len(uncompressed)/10, len(data))
+			}
+
+			actual := foo{}
+			err = codec.Unmarshal(data, &actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual != expected {
+				t.Errorf("Expected %q, but was %q", expected.Bar, actual.Bar)
+			}
+		})
+	}
+}
+
+// TestCompressedIncompressible tests that random values and values below the threshold
+// only grow by the one-byte header.
+func TestCompressedIncompressible(t *testing.T) {
+	random := make([]byte, 4096)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, compression := range compressions {
+		t.Run(name, func(t *testing.T) {
+			// Gob doesn't encode slices of bytes as text, like JSON does with base64
+			codec := compressed.NewCodec(encoding.Gob, compression)
+			for _, value := range [][]byte{random, []byte("tiny")} {
+				data, err := codec.Marshal(value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				uncompressed, err := encoding.Gob.Marshal(value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(data) > len(uncompressed)+1 {
+					t.Errorf("Expected at most %v bytes, but was %v", len(uncompressed)+1, len(data))
+				}
+
+				var actual []byte
+				err = codec.Unmarshal(data, &actual)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(actual) != string(value) {
+					t.Error("The unmarshalled value differs from the original one")
+				}
+			}
+		})
+	}
+}
+
+// TestCompressedThreshold tests that values below the threshold aren't compressed.
+func TestCompressedThreshold(t *testing.T) {
+	value := foo{Bar: strings.Repeat("baz", 100)}
+	uncompressed, err := encoding.JSON.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	codec := compressed.NewCodecWithThreshold(encoding.JSON, compressed.Gzip, len(uncompressed)+1)
+	data, err := codec.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != len(uncompressed)+1 {
+		t.Errorf("Expected the value not to be compressed, but its size changed from %v to %v bytes", len(uncompressed), len(data))
+	}
+
+	codec = compressed.NewCodecWithThreshold(encoding.JSON, compressed.Gzip, len(uncompressed))
+	data, err = codec.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) >= len(uncompressed) {
+		t.Errorf("Expected the value to be compressed, but its size changed from %v to %v bytes", len(uncompressed), len(data))
+	}
+}
+
+// TestCompressedBackwardCompatibility tests that values that were stored without compression can still be read.
+func TestCompressedBackwardCompatibility(t *testing.T) {
+	for name, codec := range map[string]encoding.Codec{"JSON": encoding.JSON, "gob": encoding.Gob} {
+		t.Run(name, func(t *testing.T) {
+			data, err := codec.Marshal(foo{Bar: "baz"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := foo{}
+			err = compressed.NewCodec(codec, compressed.Zstd).Unmarshal(data, &actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if actual.Bar != "baz" {
+				t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+			}
+		})
+	}
+}
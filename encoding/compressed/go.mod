@@ -0,0 +1,8 @@
+module github.com/philippgille/gokv/encoding/compressed
+
+go 1.20
+
+require (
+	github.com/klauspost/compress v1.17.4
+	github.com/philippgille/gokv/encoding v0.7.0
+)
@@ -3,5 +3,8 @@ Package encoding is a wrapper for the core functionality of packages like "encod
 
 It contains the Codec interface and multiple implementations for encoding Go values to other formats and decode from other formats to Go values.
 Formats can be JSON, gob etc.
+
+Codecs with third party dependencies are in their own modules in subdirectories (protobuf, msgpack, cbor and compressed),
+so that the stores don't have to download their dependencies.
 */
 package encoding
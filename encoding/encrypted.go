@@ -0,0 +1,79 @@
+package encoding
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"io"
+)
+
+// EncryptedCodec wraps another codec and encrypts the data that the other codec marshals with AES-GCM,
+// so that the values are encrypted at rest in any store.
+// The random nonce is prepended to the ciphertext.
+// Keys aren't encrypted. For that see the encrypt package.
+// Create it with NewEncrypted.
+type EncryptedCodec struct {
+	inner Codec
+	aead  cipher.AEAD
+}
+
+// NewEncrypted creates an EncryptedCodec that uses the given codec and key.
+// The key must be 16, 24 or 32 bytes long to select AES-128, AES-192 or AES-256,
+// for example generated with crypto/rand.
+// Losing the key means losing access to all stored values.
+func NewEncrypted(inner Codec, key []byte) (EncryptedCodec, error) {
+	result := EncryptedCodec{}
+
+	// Precondition check
+	if inner == nil {
+		return result, errors.New("The inner codec must not be nil")
+	}
+	switch len(key) {
+	case 16, 24, 32:
+	default:
+		return result, errors.New("The key must be 16, 24 or 32 bytes long")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return result, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return result, err
+	}
+
+	result.inner = inner
+	result.aead = aead
+
+	return result, nil
+}
+
+// Marshal encodes a Go value with the inner codec and encrypts the result.
+func (c EncryptedCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.inner.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return c.aead.Seal(nonce, nonce, data, nil), nil
+}
+
+// Unmarshal decrypts the data and decodes it with the inner codec.
+func (c EncryptedCodec) Unmarshal(data []byte, v any) error {
+	nonceSize := c.aead.NonceSize()
+	if len(data) < nonceSize+c.aead.Overhead() {
+		return errors.New("The data is too short to be an encrypted value")
+	}
+	plaintext, err := c.aead.Open(nil, data[:nonceSize], data[nonceSize:], nil)
+	if err != nil {
+		return errors.New("The data couldn't be decrypted. It was either encrypted with a different key or modified")
+	}
+
+	return c.inner.Unmarshal(plaintext, v)
+}
@@ -0,0 +1,99 @@
+package encoding_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/philippgille/gokv/encoding"
+)
+
+var encryptionKey = []byte("0123456789abcdef0123456789abcdef")
+
+// TestEncrypted tests that the data is encrypted and that another codec with the same key can decrypt it.
+func TestEncrypted(t *testing.T) {
+	codec, err := encoding.NewEncrypted(encoding.JSON, encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := codec.Marshal(foo{Bar: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Errorf("The data contains the plaintext value: %s", data)
+	}
+
+	// The random nonce leads to different data for the same value
+	data2, err := codec.Marshal(foo{Bar: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(data, data2) {
+		t.Error("Expected different data for each encryption")
+	}
+
+	otherCodec, err := encoding.NewEncrypted(encoding.JSON, encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := foo{}
+	err = otherCodec.Unmarshal(data, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Bar != "secret" {
+		t.Errorf("Expected %q, but was %q", "secret", actual.Bar)
+	}
+}
+
+// TestEncryptedWrongKey tests that data can't be decrypted with a different key or after it was modified.
+func TestEncryptedWrongKey(t *testing.T) {
+	codec, err := encoding.NewEncrypted(encoding.JSON, encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := codec.Marshal(foo{Bar: "secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wrongKeyCodec, err := encoding.NewEncrypted(encoding.JSON, []byte("fedcba9876543210fedcba9876543210"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = wrongKeyCodec.Unmarshal(data, &foo{})
+	if err == nil || !strings.Contains(err.Error(), "different key") {
+		t.Errorf("Expected a descriptive error, but was %v", err)
+	}
+
+	data[len(data)-1] ^= 1
+	err = codec.Unmarshal(data, &foo{})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = codec.Unmarshal([]byte("short"), &foo{})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestEncryptedKeySize tests that only AES key sizes are accepted.
+func TestEncryptedKeySize(t *testing.T) {
+	for _, size := range []int{16, 24, 32} {
+		_, err := encoding.NewEncrypted(encoding.JSON, make([]byte, size))
+		if err != nil {
+			t.Errorf("Expected a key with %v bytes to be accepted, but got: %v", size, err)
+		}
+	}
+	for _, size := range []int{0, 15, 31, 64} {
+		_, err := encoding.NewEncrypted(encoding.JSON, make([]byte, size))
+		if err == nil {
+			t.Errorf("Expected an error for a key with %v bytes", size)
+		}
+	}
+	_, err := encoding.NewEncrypted(nil, encryptionKey)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
@@ -0,0 +1,55 @@
+package encoding
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// fallbackMarker precedes the string representation of values that the inner codec couldn't marshal.
+// Neither JSON nor gob data can start with a null byte.
+var fallbackMarker = []byte("\x00gokv-fallback:")
+
+// FallbackCodec wraps another codec and stores the string representation of values
+// that the other codec can't marshal, for example a type that gob can't handle.
+// This way at least something is persisted, which is useful for best-effort logging or telemetry data,
+// but the original value is lost. Only use it when that's acceptable.
+// Create it with NewFallback.
+type FallbackCodec struct {
+	inner Codec
+}
+
+// NewFallback creates a FallbackCodec that uses the given codec
+// and falls back to the string representation (`fmt.Sprintf("%v", v)`) when the codec fails to marshal a value.
+func NewFallback(inner Codec) FallbackCodec {
+	return FallbackCodec{inner: inner}
+}
+
+// Marshal encodes a Go value with the inner codec.
+// If that fails, the value's string representation is encoded instead, with a marker.
+func (c FallbackCodec) Marshal(v any) ([]byte, error) {
+	data, err := c.inner.Marshal(v)
+	if err == nil {
+		return data, nil
+	}
+	return append(append([]byte{}, fallbackMarker...), fmt.Sprintf("%v", v)...), nil
+}
+
+// Unmarshal decodes a value with the inner codec.
+// A value that was stored as string representation can only be decoded into a *string or *any,
+// which is then set to the string representation.
+func (c FallbackCodec) Unmarshal(data []byte, v any) error {
+	s, ok := bytes.CutPrefix(data, fallbackMarker)
+	if !ok {
+		return c.inner.Unmarshal(data, v)
+	}
+	switch v := v.(type) {
+	case *string:
+		*v = string(s)
+	case *any:
+		*v = string(s)
+	default:
+		return errors.New("The value was stored as string representation, because it couldn't be marshalled, so it can only be unmarshalled into a *string or *any")
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package encoding_test
+
+import (
+	"testing"
+
+	"github.com/philippgille/gokv/encoding"
+)
+
+// TestFallback tests that values the inner codec can't marshal are stored as string representation
+// and read back as string, while other values are unaffected.
+func TestFallback(t *testing.T) {
+	codec := encoding.NewFallback(encoding.JSON)
+
+	// Regular value
+	data, err := codec.Marshal(foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := foo{}
+	err = codec.Unmarshal(data, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	// JSON doesn't support complex numbers
+	_, err = encoding.JSON.Marshal(complex(1, 2))
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	data, err = codec.Marshal(complex(1, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var s string
+	err = codec.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s != "(1+2i)" {
+		t.Errorf("Expected %q, but was %q", "(1+2i)", s)
+	}
+	var i any
+	err = codec.Unmarshal(data, &i)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if i != "(1+2i)" {
+		t.Errorf("Expected %q, but was %v", "(1+2i)", i)
+	}
+	var c complex128
+	err = codec.Unmarshal(data, &c)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// gob can't handle functions
+	codec = encoding.NewFallback(encoding.Gob)
+	data, err = codec.Marshal(func() {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = codec.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s == "" {
+		t.Error("Expected the string representation, but was empty")
+	}
+}
@@ -1,12 +1,27 @@
 package encoding
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 )
 
 // JSONcodec encodes/decodes Go values to/from JSON.
 // You can use encoding.JSON instead of creating an instance of this struct.
-type JSONcodec struct{}
+type JSONcodec struct {
+	strict bool
+}
+
+// NewStrictJSON creates a JSONcodec that returns an error when unmarshalling JSON
+// with an object key that doesn't match any non-ignored, exported field of the destination struct,
+// instead of silently ignoring it.
+// This catches drift between the stored values and the current shape of a struct early.
+func NewStrictJSON() JSONcodec {
+	return JSONcodec{
+		strict: true,
+	}
+}
 
 // Marshal encodes a Go value to JSON.
 func (c JSONcodec) Marshal(v any) ([]byte, error) {
@@ -15,5 +30,18 @@ func (c JSONcodec) Marshal(v any) ([]byte, error) {
 
 // Unmarshal decodes a JSON value into a Go value.
 func (c JSONcodec) Unmarshal(data []byte, v any) error {
-	return json.Unmarshal(data, v)
+	if !c.strict {
+		return json.Unmarshal(data, v)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return err
+	}
+	// Like json.Unmarshal, don't accept data after the JSON value
+	if _, err := decoder.Token(); err != io.EOF {
+		return errors.New("invalid character after top-level JSON value")
+	}
+	return nil
 }
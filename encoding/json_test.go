@@ -0,0 +1,61 @@
+package encoding_test
+
+import (
+	"testing"
+
+	"github.com/philippgille/gokv/encoding"
+)
+
+type foo struct {
+	Bar string
+}
+
+// TestStrictJSON tests that the strict JSON codec rejects unknown fields, while the default one ignores them.
+func TestStrictJSON(t *testing.T) {
+	data := []byte(`{"Bar":"baz","Qux":"quux"}`)
+
+	actual := foo{}
+	err := encoding.JSON.Unmarshal(data, &actual)
+	if err != nil {
+		t.Error(err)
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	codec := encoding.NewStrictJSON()
+	err = codec.Unmarshal(data, &foo{})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Known fields only
+	actual = foo{}
+	err = codec.Unmarshal([]byte(`{"Bar":"baz"}`), &actual)
+	if err != nil {
+		t.Error(err)
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	// Data after the value
+	err = codec.Unmarshal([]byte(`{"Bar":"baz"} {}`), &foo{})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Round trip
+	data, err = codec.Marshal(foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual = foo{}
+	err = codec.Unmarshal(data, &actual)
+	if err != nil {
+		t.Error(err)
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+}
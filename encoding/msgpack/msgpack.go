@@ -0,0 +1,26 @@
+package msgpack
+
+import (
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Convenience variable for simpler usage in gokv store options.
+//
+//	options := redis.Options{
+//		Codec: msgpack.Codec,
+//	}
+var Codec = MessagePackCodec{}
+
+// MessagePackCodec encodes/decodes Go values to/from MessagePack.
+// It's more compact and faster than JSON, but not human-readable.
+type MessagePackCodec struct{}
+
+// Marshal encodes a Go value to MessagePack.
+func (c MessagePackCodec) Marshal(v any) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal decodes a MessagePack value into a Go value.
+func (c MessagePackCodec) Unmarshal(data []byte, v any) error {
+	return msgpack.Unmarshal(data, v)
+}
@@ -0,0 +1,80 @@
+package msgpack_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/philippgille/gokv/encoding/msgpack"
+)
+
+type foo struct {
+	Bar  string
+	Baz  int
+	Qux  []string
+	Quux map[string]float64
+	Time time.Time
+}
+
+// TestMessagePackRoundTrip tests that a struct is unmarshalled to the original value.
+func TestMessagePackRoundTrip(t *testing.T) {
+	expected := foo{
+		Bar:  "baz",
+		Baz:  -42,
+		Qux:  []string{"a", "b"},
+		Quux: map[string]float64{"pi": 3.14},
+		// The nanoseconds must survive the round trip
+		Time: time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("CET", 3600)),
+	}
+	data, err := msgpack.Codec.Marshal(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := foo{}
+	err = msgpack.Codec.Unmarshal(data, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The location isn't necessarily the same pointer after the round trip, so the times are compared separately
+	if !actual.Time.Equal(expected.Time) {
+		t.Errorf("Expected %v, but was %v", expected.Time, actual.Time)
+	}
+	actual.Time, expected.Time = time.Time{}, time.Time{}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("Expected %+v, but was %+v", expected, actual)
+	}
+}
+
+// TestMessagePackErrors tests that marshalling an unsupported value and unmarshalling invalid data lead to errors.
+func TestMessagePackErrors(t *testing.T) {
+	_, err := msgpack.Codec.Marshal(make(chan int))
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+
+	actual := foo{}
+	// Truncated data
+	data, err := msgpack.Codec.Marshal(foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = msgpack.Codec.Unmarshal(data[:len(data)-2], &actual)
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+	// Type mismatch
+	data, err = msgpack.Codec.Marshal("baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = msgpack.Codec.Unmarshal(data, &actual)
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+	// Non-pointer
+	err = msgpack.Codec.Unmarshal(data, actual)
+	if err == nil {
+		t.Error("An error should have occurred, but didn't")
+	}
+}
@@ -0,0 +1,5 @@
+/*
+Package encrypt contains a `gokv.Store` wrapper that encrypts values, and optionally keys,
+before they're passed to another store, so that an untrusted backend only sees opaque data.
+*/
+package encrypt
@@ -0,0 +1,180 @@
+package encrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"io"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// Store is a gokv.Store implementation that encrypts values with AES-256-GCM
+// before passing them to another store, and optionally replaces keys by their HMAC-SHA256.
+type Store struct {
+	inner gokv.Store
+	aead  cipher.AEAD
+	// nil if keys are passed to the inner store as they are
+	keyMACKey []byte
+	codec     encoding.Codec
+}
+
+// Set marshals and encrypts the given value and stores it for the given key in the inner store.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	// The original key is used as additional data,
+	// so a value that's copied to another key in the inner store can't be decrypted.
+	ciphertext := s.aead.Seal(nonce, nonce, data, []byte(k))
+
+	return s.inner.Set(s.innerKey(k), ciphertext)
+}
+
+// Get retrieves the stored value for the given key from the inner store and decrypts it.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	var ciphertext []byte
+	found, err = s.inner.Get(s.innerKey(k), &ciphertext)
+	if err != nil || !found {
+		return found, err
+	}
+
+	nonceSize := s.aead.NonceSize()
+	if len(ciphertext) < nonceSize {
+		return true, errors.New("The stored value is too short to be an encrypted value")
+	}
+	data, err := s.aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], []byte(k))
+	if err != nil {
+		return true, errors.New("The stored value couldn't be decrypted. It was either encrypted with a different key or modified")
+	}
+
+	return true, s.codec.Unmarshal(data, v)
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	return s.inner.Delete(s.innerKey(k))
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// innerKey returns the key that's used in the inner store.
+func (s Store) innerKey(k string) string {
+	if s.keyMACKey == nil {
+		return k
+	}
+	mac := hmac.New(sha256.New, s.keyMACKey)
+	mac.Write([]byte(k))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// deriveKey derives a purpose specific key from the secret key,
+// so the same key material is never used for both encryption and HMAC.
+func deriveKey(secretKey []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, secretKey)
+	mac.Write([]byte("gokv/encrypt " + purpose))
+	return mac.Sum(nil)
+}
+
+// Options are the options for the encrypt store.
+type Options struct {
+	// Secret key from which the encryption key (and the HMAC key, if EncryptKeys is true) are derived.
+	// Must be 32 bytes long, for example generated with crypto/rand.
+	// Losing the key means losing access to all stored values.
+	SecretKey []byte
+	// Replace keys by their HMAC-SHA256 (base64url encoded, 43 characters),
+	// so that the inner store doesn't see the original keys.
+	// This is deterministic, otherwise Get and Delete couldn't find the key-value pairs,
+	// which trades some security for lookupability:
+	// Someone with access to the inner store can't recover the original keys
+	// and can't check guessed keys without the SecretKey,
+	// but they can still see how many keys there are
+	// and recognize that the same key is accessed or written repeatedly.
+	// Keys can't be listed in their original form anymore.
+	// Optional (false by default).
+	EncryptKeys bool
+	// Encoding format for the values before they're encrypted.
+	// The inner store's codec is then used for the encrypted bytes.
+	// Optional (encoding.JSON by default).
+	Codec encoding.Codec
+}
+
+// DefaultOptions is an Options object with default values.
+// EncryptKeys: false, Codec: encoding.JSON
+var DefaultOptions = Options{
+	Codec: encoding.JSON,
+	// No need to set EncryptKeys because its zero value is fine.
+}
+
+// NewStore creates a new encrypt store that wraps the given store.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, options Options) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if inner == nil {
+		return result, errors.New("The inner store must not be nil")
+	}
+	if len(options.SecretKey) != 32 {
+		return result, errors.New("The SecretKey must be 32 bytes long")
+	}
+
+	// Set default values
+	if options.Codec == nil {
+		options.Codec = DefaultOptions.Codec
+	}
+
+	block, err := aes.NewCipher(deriveKey(options.SecretKey, "value"))
+	if err != nil {
+		return result, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return result, err
+	}
+
+	result.inner = inner
+	result.aead = aead
+	if options.EncryptKeys {
+		result.keyMACKey = deriveKey(options.SecretKey, "key")
+	}
+	result.codec = options.Codec
+
+	return result, nil
+}
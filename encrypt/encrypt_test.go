@@ -0,0 +1,250 @@
+package encrypt_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/encrypt"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/test"
+)
+
+var secretKey = []byte("0123456789abcdef0123456789abcdef")
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestStore(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, true)
+		test.TestStore(store, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.Gob, true)
+		test.TestStore(store, t)
+	})
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, true)
+		test.TestTypes(store, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.Gob, true)
+		test.TestTypes(store, t)
+	})
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, true)
+
+	goroutineCount := 1000
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+}
+
+// TestOpaque tests that the inner store only sees opaque keys and values.
+func TestOpaque(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+
+	t.Run("keys and values", func(t *testing.T) {
+		store := createStore(t, inner, encoding.JSON, true)
+		err := store.Set("secret-key", test.Foo{Bar: "secret-value"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		found, err := inner.Get("secret-key", new([]byte))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Error("The original key was found in the inner store")
+		}
+		expectOnlyOpaqueData(t, inner)
+
+		actual := test.Foo{}
+		found, err = store.Get("secret-key", &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || actual.Bar != "secret-value" {
+			t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "secret-value"}, found, actual)
+		}
+
+		err = store.Delete("secret-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err = store.Get("secret-key", &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Error("A value was found, but no value was expected")
+		}
+	})
+
+	t.Run("values only", func(t *testing.T) {
+		store := createStore(t, inner, encoding.JSON, false)
+		err := store.Set("plain-key", test.Foo{Bar: "secret-value"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, found, err := inner.GetRaw("plain-key")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Fatal("The key wasn't passed to the inner store as it is")
+		}
+		if bytes.Contains(data, []byte("secret-value")) {
+			t.Errorf("The inner store contains the plaintext value: %s", data)
+		}
+	})
+}
+
+// TestSecretKey tests that a different key can't decrypt the values
+// and that the same key works with another store object.
+func TestSecretKey(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	store := createStore(t, inner, encoding.JSON, false)
+	err := store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sameKeyStore := createStore(t, inner, encoding.JSON, false)
+	actual := test.Foo{}
+	found, err := sameKeyStore.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+
+	otherKeyStore, err := encrypt.NewStore(inner, encrypt.Options{
+		SecretKey: []byte("fedcba9876543210fedcba9876543210"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = otherKeyStore.Get("foo", &actual)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// A value that's copied to another key can't be decrypted either
+	var ciphertext []byte
+	_, err = inner.Get("foo", &ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = inner.Set("bar", ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Get("bar", &actual)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	// Test empty key
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, true)
+	err := store.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Test invalid options
+	_, err = encrypt.NewStore(nil, encrypt.Options{SecretKey: secretKey})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = encrypt.NewStore(gomap.NewStore(gomap.DefaultOptions), encrypt.Options{SecretKey: []byte("too short")})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), encoding.JSON, true)
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func expectOnlyOpaqueData(t *testing.T, inner gomap.Store) {
+	t.Helper()
+
+	// The key in the inner store isn't known here,
+	// so it's recorded while getting the value via another wrapper.
+	recorder := &keyRecorder{Store: inner}
+	store := createStore(t, recorder, encoding.JSON, true)
+	_, err := store.Get("secret-key", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorder.lastKey == "" || bytes.Contains([]byte(recorder.lastKey), []byte("secret")) {
+		t.Errorf("Expected an opaque key in the inner store, but was %q", recorder.lastKey)
+	}
+	data, found, err := inner.GetRaw(recorder.lastKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found in the inner store")
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Errorf("The inner store contains the plaintext value: %s", data)
+	}
+}
+
+// keyRecorder is a gokv.Store that records the last key that was passed to Get.
+type keyRecorder struct {
+	gokv.Store
+	lastKey string
+}
+
+func (s *keyRecorder) Get(k string, v any) (bool, error) {
+	s.lastKey = k
+	return s.Store.Get(k, v)
+}
+
+func createStore(t *testing.T, inner gokv.Store, codec encoding.Codec, encryptKeys bool) encrypt.Store {
+	options := encrypt.Options{
+		SecretKey:   secretKey,
+		EncryptKeys: encryptKeys,
+		Codec:       codec,
+	}
+	store, err := encrypt.NewStore(inner, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
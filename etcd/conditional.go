@@ -0,0 +1,97 @@
+package etcd
+
+import (
+	"context"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// DeleteIf deletes the stored value for the given key if it's equal to the expected value.
+// The comparison and deletion are done in one transaction, so they're atomic.
+// The values are compared in their marshalled form.
+// If the key doesn't exist or holds a different value, it returns (false, nil).
+// The key must not be "" and the expected value must not be nil.
+func (c Client) DeleteIf(k string, expected any) (deleted bool, err error) {
+	if err := util.CheckKeyAndValue(k, expected); err != nil {
+		return false, err
+	}
+
+	data, err := c.codec.Marshal(expected)
+	if err != nil {
+		return false, err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	txnRes, err := c.c.Txn(ctxWithTimeout).
+		If(clientv3.Compare(clientv3.Value(k), "=", string(data))).
+		Then(clientv3.OpDelete(k)).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return txnRes.Succeeded, nil
+}
+
+// CompareAndSwap stores the new value for the given key if the stored value is equal to the old value.
+// The comparison and replacement are done in one transaction, so they're atomic.
+// The values are compared in their marshalled form.
+// If the key doesn't exist or holds a different value, it returns (false, nil).
+// The key must not be "" and the values must not be nil.
+func (c Client) CompareAndSwap(k string, old, new any) (swapped bool, err error) {
+	if err := util.CheckKeyAndValue(k, old); err != nil {
+		return false, err
+	}
+	if err := util.CheckVal(new); err != nil {
+		return false, err
+	}
+
+	oldData, err := c.codec.Marshal(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.codec.Marshal(new)
+	if err != nil {
+		return false, err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	txnRes, err := c.c.Txn(ctxWithTimeout).
+		If(clientv3.Compare(clientv3.Value(k), "=", string(oldData))).
+		Then(clientv3.OpPut(k, string(newData))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return txnRes.Succeeded, nil
+}
+
+// SetIfAbsent stores the given value for the given key if the key doesn't exist yet.
+// The existence check and the write are done in one transaction, so they're atomic.
+// If the key already exists, it returns (false, nil).
+// The key must not be "" and the value must not be nil.
+func (c Client) SetIfAbsent(k string, v any) (set bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	// A key that doesn't exist has the create revision 0
+	txnRes, err := c.c.Txn(ctxWithTimeout).
+		If(clientv3.Compare(clientv3.CreateRevision(k), "=", 0)).
+		Then(clientv3.OpPut(k, string(data))).
+		Commit()
+	if err != nil {
+		return false, err
+	}
+	return txnRes.Succeeded, nil
+}
@@ -0,0 +1,61 @@
+package etcd
+
+import (
+	"context"
+	"strconv"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// Increment adds delta to the counter that's stored for the given key and returns the new count.
+// etcd has no increment operation, so the counter is read and then written in a transaction
+// that only succeeds if the key wasn't modified in between. Otherwise it's retried.
+// The counter is stored as decimal string and can be read with Get into an int64 when using encoding.JSON.
+// A non-existing counter starts at 0. delta can be negative to decrement the counter.
+// If the key holds a value that isn't an integer, gokv.ErrNotACounter is returned.
+// The key must not be "".
+func (c Client) Increment(k string, delta int64) (int64, error) {
+	if err := util.CheckKey(k); err != nil {
+		return 0, err
+	}
+
+	for {
+		count, succeeded, err := c.tryIncrement(k, delta)
+		if err != nil || succeeded {
+			return count, err
+		}
+	}
+}
+
+func (c Client) tryIncrement(k string, delta int64) (count int64, succeeded bool, err error) {
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	getRes, err := c.c.Get(ctxWithTimeout, k)
+	if err != nil {
+		return 0, false, err
+	}
+	// A ModRevision of 0 in the comparison means that the key doesn't exist
+	var modRevision int64
+	if len(getRes.Kvs) > 0 {
+		kv := getRes.Kvs[0]
+		count, err = strconv.ParseInt(string(kv.Value), 10, 64)
+		if err != nil {
+			return 0, false, gokv.ErrNotACounter
+		}
+		modRevision = kv.ModRevision
+	}
+	count += delta
+
+	txnRes, err := c.c.Txn(ctxWithTimeout).
+		If(clientv3.Compare(clientv3.ModRevision(k), "=", modRevision)).
+		Then(clientv3.OpPut(k, strconv.FormatInt(count, 10))).
+		Commit()
+	if err != nil {
+		return 0, false, err
+	}
+	return count, txnRes.Succeeded, nil
+}
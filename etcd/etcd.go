@@ -2,12 +2,14 @@ package etcd
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"time"
 
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"google.golang.org/grpc"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -86,6 +88,23 @@ func (c Client) Delete(k string) error {
 	return err
 }
 
+// Ping checks if at least one of the etcd endpoints is reachable by requesting its status.
+// It returns the error of the last endpoint if none of them is reachable.
+func (c Client) Ping(ctx context.Context) error {
+	var err error
+	for _, endpoint := range c.c.Endpoints() {
+		if _, err = c.c.Status(ctx, endpoint); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// Kind returns gokv.KindEtcd.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindEtcd
+}
+
 // Close closes the client.
 // It must be called to shut down all connections to the etcd server.
 func (c Client) Close() error {
@@ -95,22 +114,40 @@ func (c Client) Close() error {
 // Options are the options for the etcd client.
 type Options struct {
 	// Addresses of the etcd servers in the cluster, including port.
+	// The client balances the requests across the endpoints and fails over to another one
+	// when an endpoint becomes unavailable.
+	// Empty strings are ignored, but if the slice isn't empty it must contain at least one non-empty endpoint.
 	// Optional ([]string{"localhost:2379"} by default).
 	Endpoints []string
 	// The timeout for operations.
 	// Optional (200 * time.Millisecond by default).
 	Timeout *time.Duration
+	// Username for authentication, when authentication is enabled on the etcd cluster.
+	// The client then gets an auth token with the credentials when connecting
+	// and uses it for all operations.
+	// Optional ("" by default).
+	Username string
+	// Password of the user.
+	// Only allowed when Username is set.
+	// Optional ("" by default).
+	Password string
+	// TLS configuration for the connection, for example with the RootCAs for a cluster with a self-signed certificate
+	// or with Certificates for client certificate authentication.
+	// Optional (nil by default, which means no TLS).
+	TLSConfig *tls.Config
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// Endpoints: []string{"localhost:2379"}, Timeout: 200 * time.Millisecond, Codec: encoding.JSON
+// Endpoints: []string{"localhost:2379"}, Timeout: 200 * time.Millisecond,
+// Username: "", Password: "", TLSConfig: nil, Codec: encoding.JSON
 var DefaultOptions = Options{
 	Endpoints: []string{"localhost:2379"},
 	Timeout:   &defaultTimeout,
 	Codec:     encoding.JSON,
+	// No need to set Username, Password or TLSConfig because their Go zero values are fine.
 }
 
 // NewClient creates a new etcd client.
@@ -119,9 +156,24 @@ var DefaultOptions = Options{
 func NewClient(options Options) (Client, error) {
 	result := Client{}
 
+	// Precondition check
+	if options.Password != "" && options.Username == "" {
+		return result, errors.New("The Password can only be used when the Username is set")
+	}
+
+	var endpoints []string
+	for _, endpoint := range options.Endpoints {
+		if endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	if len(options.Endpoints) > 0 && len(endpoints) == 0 {
+		return result, errors.New("The Endpoints must contain at least one non-empty endpoint")
+	}
+
 	// Set default values
-	if options.Endpoints == nil || len(options.Endpoints) == 0 {
-		options.Endpoints = DefaultOptions.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = DefaultOptions.Endpoints
 	}
 	if options.Timeout == nil {
 		options.Timeout = DefaultOptions.Timeout
@@ -131,9 +183,12 @@ func NewClient(options Options) (Client, error) {
 	}
 
 	config := clientv3.Config{
-		Endpoints:   options.Endpoints,
+		Endpoints:   endpoints,
 		DialTimeout: 2 * time.Second,
 		DialOptions: []grpc.DialOption{grpc.WithBlock()},
+		Username:    options.Username,
+		Password:    options.Password,
+		TLS:         options.TLSConfig,
 	}
 
 	cli, err := clientv3.New(config)
@@ -143,7 +198,7 @@ func NewClient(options Options) (Client, error) {
 
 	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	statusRes, err := cli.Status(ctxWithTimeout, options.Endpoints[0])
+	statusRes, err := cli.Status(ctxWithTimeout, endpoints[0])
 	if err != nil {
 		return result, err
 	} else if statusRes == nil {
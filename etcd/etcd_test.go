@@ -14,6 +14,13 @@ import (
 	"github.com/philippgille/gokv/test"
 )
 
+// For the etcd server that's used by most tests.
+var testEndpoint = "localhost:2379"
+
+// For an etcd server with authentication enabled.
+// See TestAuth.
+var authTestEndpoint = "localhost:22379"
+
 // TestClient tests if reading from, writing to and deleting from the store works properly.
 // A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
 func TestClient(t *testing.T) {
@@ -49,6 +56,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the etcd client.
 func TestClientConcurrent(t *testing.T) {
 	// This test always works locally, but depending on the time of day, maybe
@@ -69,6 +93,59 @@ func TestClientConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, client)
 }
 
+// TestLock tests that two clients can't hold the same lock simultaneously
+// and that a lock automatically expires after its TTL, unless it's refreshed by gokv.WithLock.
+func TestLock(t *testing.T) {
+	clientA := createClient(t, encoding.JSON)
+	defer clientA.Close()
+	clientB := createClient(t, encoding.JSON)
+	defer clientB.Close()
+
+	err := clientA.Delete("lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// etcd leases have a granularity of seconds
+	test.TestLocker(t, clientA, clientB, "lock", 2*time.Second)
+	test.TestWithLock(t, clientA, clientB, "lock", 2*time.Second)
+}
+
+// TestDeleteIf tests that a key-value pair is only deleted when it holds the expected value.
+func TestDeleteIf(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestConditionalDeleter(client, t)
+}
+
+// TestCompareAndSwap tests that a value is only replaced when it holds the expected value.
+func TestCompareAndSwap(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestCASStore(client, t)
+}
+
+// TestSetIfAbsent tests that a value is only stored when the key doesn't exist yet.
+func TestSetIfAbsent(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestConditionalSetter(client, t)
+}
+
+// TestUpdateFunc tests if concurrent updates via gokv.UpdateFunc don't get lost.
+func TestUpdateFunc(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestUpdateFunc(client, t)
+}
+
+// TestCounter tests if concurrent increments of a counter are atomic.
+func TestCounter(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestCounterStore(client, t)
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -88,6 +165,94 @@ func TestErrors(t *testing.T) {
 	}
 }
 
+// TestEndpoints tests if the client works with explicitly configured endpoints
+// and rejects endpoints that are all empty.
+func TestEndpoints(t *testing.T) {
+	invalid := [][]string{
+		{""},
+		{"", ""},
+	}
+	for _, endpoints := range invalid {
+		_, err := etcd.NewClient(etcd.Options{
+			Endpoints: endpoints,
+		})
+		if err == nil {
+			t.Errorf("Expected an error for the endpoints %q", endpoints)
+		}
+	}
+
+	if !checkConnection() {
+		t.Skip("No connection to etcd could be established. Probably not running in a proper test environment.")
+	}
+
+	timeout := 2 * time.Second
+	client, err := etcd.NewClient(etcd.Options{
+		// Empty endpoints are ignored
+		Endpoints: []string{"", testEndpoint},
+		Timeout:   &timeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	test.TestStore(client, t)
+}
+
+// TestAuth tests if the client works with an etcd server that has authentication enabled.
+// The server must have a "root" user with the password "secret" and authentication enabled:
+//
+//	etcdctl --endpoints localhost:22379 user add root:secret
+//	etcdctl --endpoints localhost:22379 auth enable
+func TestAuth(t *testing.T) {
+	// The password alone is invalid
+	_, err := etcd.NewClient(etcd.Options{
+		Password: "secret",
+	})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	if !checkAuthConnection() {
+		t.Skip("No connection to an etcd server with authentication could be established. Probably not running in a proper test environment.")
+	}
+
+	timeout := 2 * time.Second
+	client, err := etcd.NewClient(etcd.Options{
+		Endpoints: []string{authTestEndpoint},
+		Timeout:   &timeout,
+		Username:  "root",
+		Password:  "secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	test.TestStore(client, t)
+
+	_, err = etcd.NewClient(etcd.Options{
+		Endpoints: []string{authTestEndpoint},
+		Username:  "root",
+		Password:  "wrong",
+	})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Without credentials the operations are rejected
+	unauthenticated, err := etcd.NewClient(etcd.Options{
+		Endpoints: []string{authTestEndpoint},
+		Timeout:   &timeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unauthenticated.Close()
+	err = unauthenticated.Set("foo", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
 // TestNil tests the behaviour when passing nil or pointers to nil values to some methods.
 func TestNil(t *testing.T) {
 	// Test setting nil
@@ -145,6 +310,13 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestPing tests if the server is reachable via Ping.
+func TestPing(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestPinger(client, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -189,7 +361,7 @@ func checkConnection() bool {
 	// TODO: But it doesn't.
 	// cli, err := clientv3.NewFromURL("localhost:2379")
 	config := clientv3.Config{
-		Endpoints:   []string{"localhost:2379"},
+		Endpoints:   []string{testEndpoint},
 		DialTimeout: 2 * time.Second,
 	}
 
@@ -202,7 +374,7 @@ func checkConnection() bool {
 
 	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
-	statusRes, err := cli.Status(ctxWithTimeout, "localhost:2379")
+	statusRes, err := cli.Status(ctxWithTimeout, testEndpoint)
 	if err != nil {
 		log.Printf("An error occurred during testing the connection to the server: %v\n", err)
 		return false
@@ -212,6 +384,33 @@ func checkConnection() bool {
 	return true
 }
 
+// checkAuthConnection returns true if a connection with credentials could be made, false otherwise.
+func checkAuthConnection() bool {
+	config := clientv3.Config{
+		Endpoints:   []string{authTestEndpoint},
+		DialTimeout: 2 * time.Second,
+		Username:    "root",
+		Password:    "secret",
+	}
+
+	cli, err := clientv3.New(config)
+	if err != nil {
+		log.Printf("An error occurred during testing the connection to the server with authentication: %v\n", err)
+		return false
+	}
+	defer cli.Close()
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	_, err = cli.Get(ctxWithTimeout, "foo")
+	if err != nil {
+		log.Printf("An error occurred during testing the connection to the server with authentication: %v\n", err)
+		return false
+	}
+
+	return true
+}
+
 func createClient(t *testing.T, codec encoding.Codec) etcd.Client {
 	timeout := 2 * time.Second
 	options := etcd.Options{
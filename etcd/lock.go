@@ -0,0 +1,137 @@
+package etcd
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"math"
+	"sync"
+	"time"
+
+	clientv3 "go.etcd.io/etcd/client/v3"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// AcquireLock acquires the lock with the given key.
+// The key is written in a transaction that only succeeds if the key doesn't exist yet,
+// and it's attached to a lease with the given TTL, so etcd deletes it when the lease expires.
+// If the lock is currently held by someone else, gokv.ErrLockHeld is returned.
+// The TTL is rounded up to full seconds.
+// The key must not be "" and the TTL must be positive.
+func (c Client) AcquireLock(k string, ttl time.Duration) (gokv.Lock, error) {
+	if err := util.CheckKey(k); err != nil {
+		return nil, err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(b)
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	leaseRes, err := c.c.Grant(ctxWithTimeout, ttlSeconds(ttl))
+	if err != nil {
+		return nil, err
+	}
+	txnRes, err := c.c.Txn(ctxWithTimeout).
+		If(clientv3.Compare(clientv3.CreateRevision(k), "=", 0)).
+		Then(clientv3.OpPut(k, token, clientv3.WithLease(leaseRes.ID))).
+		Commit()
+	if err != nil || !txnRes.Succeeded {
+		_, _ = c.c.Revoke(ctxWithTimeout, leaseRes.ID)
+		if err != nil {
+			return nil, err
+		}
+		return nil, gokv.ErrLockHeld
+	}
+	return &lock{
+		c:       c,
+		key:     k,
+		token:   token,
+		leaseID: leaseRes.ID,
+		mutex:   &sync.Mutex{},
+	}, nil
+}
+
+// lock is a gokv.Lock implementation for etcd.
+type lock struct {
+	c       Client
+	key     string
+	token   string
+	leaseID clientv3.LeaseID
+	mutex   *sync.Mutex
+}
+
+// Release releases the lock by deleting its key and revoking its lease.
+// If the lock expired in the meantime, gokv.ErrLockLost is returned.
+func (l *lock) Release() error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), l.c.timeOut)
+	defer cancel()
+	txnRes, err := l.c.c.Txn(ctxWithTimeout).
+		If(clientv3.Compare(clientv3.Value(l.key), "=", l.token)).
+		Then(clientv3.OpDelete(l.key)).
+		Commit()
+	if err != nil {
+		return err
+	}
+	// Revoking an expired lease leads to an error, which doesn't matter here.
+	_, _ = l.c.c.Revoke(ctxWithTimeout, l.leaseID)
+	if !txnRes.Succeeded {
+		return gokv.ErrLockLost
+	}
+	return nil
+}
+
+// Refresh extends the lock's expiry to the given TTL from now.
+// etcd can only renew a lease with its original TTL, so a new lease is granted
+// and attached to the key, and the old one is revoked.
+// If the lock expired in the meantime, gokv.ErrLockLost is returned.
+// The TTL must be positive and is rounded up to full seconds.
+func (l *lock) Refresh(ttl time.Duration) error {
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	ctxWithTimeout, cancel := context.WithTimeout(context.Background(), l.c.timeOut)
+	defer cancel()
+	leaseRes, err := l.c.c.Grant(ctxWithTimeout, ttlSeconds(ttl))
+	if err != nil {
+		return err
+	}
+	txnRes, err := l.c.c.Txn(ctxWithTimeout).
+		If(clientv3.Compare(clientv3.Value(l.key), "=", l.token)).
+		Then(clientv3.OpPut(l.key, l.token, clientv3.WithLease(leaseRes.ID))).
+		Commit()
+	if err != nil || !txnRes.Succeeded {
+		_, _ = l.c.c.Revoke(ctxWithTimeout, leaseRes.ID)
+		if err != nil {
+			return err
+		}
+		return gokv.ErrLockLost
+	}
+	_, _ = l.c.c.Revoke(ctxWithTimeout, l.leaseID)
+	l.leaseID = leaseRes.ID
+	return nil
+}
+
+// ttlSeconds converts the TTL to full seconds, as required for etcd leases.
+func ttlSeconds(ttl time.Duration) int64 {
+	seconds := int64(math.Ceil(ttl.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return seconds
+}
@@ -1,5 +1,7 @@
 /*
 Package file contains an implementation of the `gokv.Store` interface for local files.
 Each key-value pair is a file with the key as name and the value as content.
+For many keys, the files can be nested in subdirectories (see Options.DirDepth).
+Alternatively, for small datasets, all key-value pairs can be stored as one JSON object in a single file (see Options.SingleFilename).
 */
 package file
@@ -1,18 +1,46 @@
 package file
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
 	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
+	"runtime"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
 var defaultFilenameExtension = "json"
 
+// Most file systems limit filenames to 255 bytes.
+const maxFilenameLength = 255
+
+// hashedFilenamePrefix precedes the hash in the filename of keys that are too long.
+// "#" is escaped by url.PathEscape, so no escaped key starts with it.
+const hashedFilenamePrefix = "#"
+
+// tmpFilenamePattern is the pattern for temporary files that are written before they're renamed.
+// They start with "#" as well, so ForEach skips them.
+const tmpFilenamePattern = "#tmp-*"
+
+// maxDirDepth is the maximum for the DirDepth option.
+// Each level uses two hex characters of the SHA-256 hash, which has 64 hex characters.
+const maxDirDepth = 32
+
+// ErrKeyTooLong is returned when the filename for a key would exceed 255 bytes after escaping
+// and the HashLongKeys option isn't set.
+var ErrKeyTooLong = errors.New("The key is too long. After escaping it for the filename, the filename must not exceed 255 bytes. See the HashLongKeys option")
+
 // Store is a gokv.Store implementation for storing key-value pairs as files.
 type Store struct {
 	// For locking the locks map
@@ -22,7 +50,12 @@ type Store struct {
 	fileLocks         map[string]*sync.RWMutex
 	filenameExtension string
 	directory         string
+	hashLongKeys      bool
+	dirDepth          int
+	syncWrites        bool
 	codec             encoding.Codec
+	// Only set when all key-value pairs are stored in a single file.
+	single *singleFile
 }
 
 // Set stores the given value for the given key.
@@ -38,21 +71,50 @@ func (s Store) Set(k string, v any) error {
 		return err
 	}
 
-	escapedKey := url.PathEscape(k)
+	return s.SetRaw(k, data)
+}
 
-	// Prepare file lock.
-	lock := s.prepFileLock(escapedKey)
+// SetRaw stores the given bytes for the given key, without marshalling them.
+// The bytes must have been marshalled with the store's codec.
+// The key must not be "" and the data must not be nil.
+func (s Store) SetRaw(k string, data []byte) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+	// util.CheckVal doesn't detect a nil slice, because it's wrapped in a non-nil interface
+	if data == nil {
+		return errors.New("The passed value is nil, which is not allowed")
+	}
 
-	filename := escapedKey
-	if s.filenameExtension != "" {
-		filename += "." + s.filenameExtension
+	if s.single != nil {
+		return s.single.set(k, data)
+	}
+
+	filename, err := s.filename(k)
+	if err != nil {
+		return err
 	}
+
+	// Prepare file lock.
+	lock := s.prepFileLock(filename)
+
 	filePath := filepath.Clean(s.directory + "/" + filename)
 
 	// File lock and file handling.
 	lock.Lock()
 	defer lock.Unlock()
-	return ioutil.WriteFile(filePath, data, 0600)
+	if s.dirDepth > 0 {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0700); err != nil {
+			return err
+		}
+	}
+	if err := writeFile(filePath, data, s.syncWrites); err != nil {
+		return err
+	}
+	if s.syncWrites {
+		return s.syncDirs(filePath)
+	}
+	return nil
 }
 
 // Get retrieves the stored value for the given key.
@@ -66,30 +128,92 @@ func (s Store) Get(k string, v any) (found bool, err error) {
 		return false, err
 	}
 
-	escapedKey := url.PathEscape(k)
+	data, found, err := s.GetRaw(k)
+	if err != nil || !found {
+		return found, err
+	}
 
-	// Prepare file lock.
-	lock := s.prepFileLock(escapedKey)
+	return true, s.codec.Unmarshal(data, v)
+}
 
-	filename := escapedKey
-	if s.filenameExtension != "" {
-		filename += "." + s.filenameExtension
+// GetRaw retrieves the stored bytes for the given key, without unmarshalling them.
+// If no value is found it returns (nil, false, nil).
+// The key must not be "".
+func (s Store) GetRaw(k string) (data []byte, found bool, err error) {
+	if err := util.CheckKey(k); err != nil {
+		return nil, false, err
+	}
+
+	if s.single != nil {
+		data, found = s.single.get(k)
+		return data, found, nil
+	}
+
+	filename, err := s.filename(k)
+	if err != nil {
+		return nil, false, err
 	}
+
+	// Prepare file lock.
+	lock := s.prepFileLock(filename)
+
 	filePath := filepath.Clean(s.directory + "/" + filename)
 
 	// File lock and file handling.
 	lock.RLock()
 	// Deferring the unlocking would lead to the unmarshalling being done during the lock, which is bad for performance.
-	data, err := ioutil.ReadFile(filePath)
+	data, err = ioutil.ReadFile(filePath)
 	lock.RUnlock()
 	if err != nil {
 		if os.IsNotExist(err) {
-			return false, nil
+			return nil, false, nil
 		}
-		return false, err
+		return nil, false, err
 	}
 
-	return true, s.codec.Unmarshal(data, v)
+	return data, true, nil
+}
+
+// ModTime returns the modification time of the file of the given key.
+// With Options.SingleFilename it's the modification time of the single file,
+// which changes with every write to any key.
+// If no value is found it returns (time.Time{}, false, nil).
+// The key must not be "".
+func (s Store) ModTime(k string) (modTime time.Time, found bool, err error) {
+	if err := util.CheckKey(k); err != nil {
+		return time.Time{}, false, err
+	}
+
+	if s.single != nil {
+		return s.single.modTime(k)
+	}
+
+	filename, err := s.filename(k)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	// Prepare file lock.
+	lock := s.prepFileLock(filename)
+
+	filePath := filepath.Clean(s.directory + "/" + filename)
+
+	lock.RLock()
+	info, err := os.Stat(filePath)
+	lock.RUnlock()
+	if err != nil {
+		if os.IsNotExist(err) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+
+	return info.ModTime(), true, nil
+}
+
+// Unmarshal unmarshals the bytes that were returned by GetRaw with the store's codec.
+func (s Store) Unmarshal(data []byte, v any) error {
+	return s.codec.Unmarshal(data, v)
 }
 
 // Delete deletes the stored value for the given key.
@@ -100,27 +224,130 @@ func (s Store) Delete(k string) error {
 		return err
 	}
 
-	escapedKey := url.PathEscape(k)
+	if s.single != nil {
+		return s.single.delete(k)
+	}
+
+	filename, err := s.filename(k)
+	if err != nil {
+		return err
+	}
 
 	// Prepare file lock.
-	lock := s.prepFileLock(escapedKey)
+	lock := s.prepFileLock(filename)
 
-	filename := escapedKey
-	if s.filenameExtension != "" {
-		filename += "." + s.filenameExtension
-	}
 	filePath := filepath.Clean(s.directory + "/" + filename)
 
 	// File lock and file handling.
 	lock.Lock()
 	defer lock.Unlock()
-	err := os.Remove(filePath)
+	err = os.Remove(filePath)
 	if os.IsNotExist(err) {
 		return nil
 	}
+	if err == nil && s.syncWrites {
+		return s.syncDirs(filePath)
+	}
 	return err
 }
 
+// ForEach calls fn for each key that starts with the given prefix.
+// The keys are determined by listing the files in the directory
+// (or in the subdirectories at the level of Options.DirDepth),
+// so files that weren't written by the store but have the same filename extension
+// are treated as key-value pairs as well.
+// Keys that were hashed because of the HashLongKeys option can't be determined from their filename
+// and are skipped.
+// If fn returns an error, the iteration stops and the error is returned.
+func (s Store) ForEach(prefix string, fn func(k string) error) error {
+	var keys []string
+	if s.single != nil {
+		keys = s.single.keys(prefix)
+	} else {
+		filenames, err := s.filenames()
+		if err != nil {
+			return err
+		}
+		suffix := ""
+		if s.filenameExtension != "" {
+			suffix = "." + s.filenameExtension
+		}
+		for _, filename := range filenames {
+			if !strings.HasSuffix(filename, suffix) || strings.HasPrefix(filename, hashedFilenamePrefix) {
+				continue
+			}
+			k, err := url.PathUnescape(strings.TrimSuffix(filename, suffix))
+			// A filename that isn't a valid escaped key can't have been written by the store
+			if err != nil || k == "" {
+				continue
+			}
+			if strings.HasPrefix(k, prefix) {
+				keys = append(keys, k)
+			}
+		}
+	}
+
+	for _, k := range keys {
+		if err := fn(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Sync syncs the directory to disk, and with Options.DirDepth its subdirectories as well.
+// The content of the files is already synced before they're renamed into place,
+// but the renames themselves are only durable after the directory is synced.
+// With Options.SingleFilename only the directory of the single file is synced.
+// On Windows directories can't be synced, so it doesn't do anything there.
+func (s Store) Sync() error {
+	if s.single != nil {
+		return syncDir(filepath.Dir(s.single.path))
+	}
+	return filepath.WalkDir(s.directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			return nil
+		}
+		relPath, err := filepath.Rel(s.directory, path)
+		if err != nil {
+			return err
+		}
+		level := 0
+		if relPath != "." {
+			level = strings.Count(filepath.ToSlash(relPath), "/") + 1
+		}
+		if level > s.dirDepth {
+			return filepath.SkipDir
+		}
+		return syncDir(path)
+	})
+}
+
+// Ping checks if the directory of the store exists and is accessible,
+// for example because it's on a network drive that might be unmounted.
+func (s Store) Ping(ctx context.Context) error {
+	dir := s.directory
+	if s.single != nil {
+		dir = filepath.Dir(s.single.path)
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return errors.New("The path " + dir + " isn't a directory")
+	}
+	return nil
+}
+
+// Kind returns gokv.KindFile.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindFile
+}
+
 // Close closes the store.
 // When called, some resources of the store are left for garbage collection.
 func (s Store) Close() error {
@@ -128,13 +355,136 @@ func (s Store) Close() error {
 	return nil
 }
 
+// filename returns the filename for the given key, which is the escaped key with the filename extension.
+// If that's too long, the filename is either the SHA-256 hash of the key or ErrKeyTooLong is returned,
+// depending on the HashLongKeys option.
+// With the DirDepth option the filename is preceded by the subdirectories, for example "ab/cd/".
+func (s Store) filename(k string) (string, error) {
+	escapedKey := url.PathEscape(k)
+	filename := escapedKey
+	if s.filenameExtension != "" {
+		filename += "." + s.filenameExtension
+	}
+	if len(filename) > maxFilenameLength {
+		if !s.hashLongKeys {
+			return "", ErrKeyTooLong
+		}
+		hash := sha256.Sum256([]byte(k))
+		filename = hashedFilenamePrefix + hex.EncodeToString(hash[:])
+		if s.filenameExtension != "" {
+			filename += "." + s.filenameExtension
+		}
+	}
+	if s.dirDepth == 0 {
+		return filename, nil
+	}
+
+	// Similar to how Git stores objects, two hex characters of the hash per level
+	hash := sha256.Sum256([]byte(escapedKey))
+	hexHash := hex.EncodeToString(hash[:])
+	var dirs strings.Builder
+	for level := 0; level < s.dirDepth; level++ {
+		dirs.WriteString(hexHash[level*2 : level*2+2])
+		dirs.WriteString("/")
+	}
+	return dirs.String() + filename, nil
+}
+
+// filenames returns the names of the files in the directory,
+// or in the subdirectories at the level of the DirDepth option.
+func (s Store) filenames() ([]string, error) {
+	var filenames []string
+	err := filepath.WalkDir(s.directory, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if path == s.directory {
+			return nil
+		}
+		relPath, err := filepath.Rel(s.directory, path)
+		if err != nil {
+			return err
+		}
+		level := strings.Count(filepath.ToSlash(relPath), "/")
+		if entry.IsDir() {
+			// Don't descend into directories that can't contain key-value pairs
+			if level >= s.dirDepth {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if level == s.dirDepth {
+			filenames = append(filenames, entry.Name())
+		}
+		return nil
+	})
+	return filenames, err
+}
+
+// writeFile writes the data to a temporary file in the same directory, which is then renamed,
+// so that the file always contains either the old or the new content, even if the process crashes.
+// Renaming is atomic on POSIX file systems. On Windows it's atomic for files on the same volume in most cases.
+// With syncFile the temporary file is synced to disk before it's renamed,
+// so the renamed file can't be empty after an operating system crash.
+func writeFile(path string, data []byte, syncFile bool) error {
+	tmpFile, err := os.CreateTemp(filepath.Dir(path), tmpFilenamePattern)
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	_, err = tmpFile.Write(data)
+	if err == nil && syncFile {
+		err = tmpFile.Sync()
+	}
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, path)
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+	}
+	return err
+}
+
+// syncDirs syncs the directory of the given file and, with the DirDepth option,
+// the parent directories up to the store's directory, because the subdirectories might have just been created.
+func (s Store) syncDirs(filePath string) error {
+	dir := filepath.Dir(filePath)
+	for level := 0; level <= s.dirDepth; level++ {
+		if err := syncDir(dir); err != nil {
+			return err
+		}
+		dir = filepath.Dir(dir)
+	}
+	return nil
+}
+
+// syncDir syncs the directory entries of the given directory to disk.
+// On Windows directories can't be synced, so it doesn't do anything there.
+func syncDir(path string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	err = dir.Sync()
+	if closeErr := dir.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
 // prepFileLock returns an existing file lock or creates a new one
-func (s Store) prepFileLock(escapedKey string) *sync.RWMutex {
+func (s Store) prepFileLock(filename string) *sync.RWMutex {
 	s.locksLock.Lock()
-	lock, found := s.fileLocks[escapedKey]
+	lock, found := s.fileLocks[filename]
 	if !found {
 		lock = new(sync.RWMutex)
-		s.fileLocks[escapedKey] = lock
+		s.fileLocks[filename] = lock
 	}
 	s.locksLock.Unlock()
 	return lock
@@ -158,10 +508,48 @@ type Options struct {
 	// Note: When you change this, you should also change the FilenameExtension if it's not empty ("").
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
+	// Name of a single file in the Directory in which all key-value pairs are stored
+	// as one JSON object, e.g. "gokv.json".
+	// The file is loaded into memory when the store is created
+	// and rewritten atomically on each Set() and Delete(),
+	// so this is only suitable for small datasets, like configuration data.
+	// FilenameExtension is ignored in this case, and the Codec must be a JSON codec.
+	// Optional ("" by default, which stores each key-value pair in its own file).
+	SingleFilename string
+	// Keys are URL-escaped for their filename, which can make them longer than the 255 bytes
+	// that most file systems allow for filenames, in which case ErrKeyTooLong is returned.
+	// When this is true, the SHA-256 hash of such keys is used for their filename instead.
+	// ForEach skips these keys, because they can't be determined from their filename.
+	// The length of the whole path can be limited as well (for example to 260 characters on Windows),
+	// which isn't checked.
+	// Not used with a SingleFilename.
+	// Optional (false by default).
+	HashLongKeys bool
+	// Number of levels of subdirectories in which the files are nested, to avoid huge flat directories
+	// that hit file system limits and are slow to list.
+	// When > 0, the subdirectories are determined by the SHA-256 hash of the escaped key,
+	// with two hex characters per level, similar to how Git stores objects.
+	// For example with 2 the file for a key could be "ab/cd/key.json".
+	// 256 subdirectories per level are enough for millions of keys with 1 or 2 levels.
+	// Subdirectories are created when needed and not removed when they become empty.
+	// Changing this for an existing directory makes the existing key-value pairs inaccessible.
+	// Must be between 0 and 32.
+	// Not used with a SingleFilename.
+	// Optional (0 by default, which stores all files directly in the Directory).
+	DirDepth int
+	// Sync each written file to disk before it's renamed into place,
+	// and sync its directory after the rename and after deleting a file,
+	// so that a Set or Delete that returned without error survives an operating system crash or power loss.
+	// This makes writes much slower.
+	// Without it, the writes are still atomic and survive a crash of the process,
+	// and Sync can be called to make all previous writes durable at once.
+	// With a SingleFilename the file is always synced, so this only adds syncing the directory.
+	// Optional (false by default).
+	SyncWrites bool
 }
 
 // DefaultOptions is an Options object with default values.
-// Directory: "gokv", Codec: encoding.JSON
+// Directory: "gokv", Codec: encoding.JSON, HashLongKeys: false, DirDepth: 0, SyncWrites: false
 var DefaultOptions = Options{
 	Directory:         "gokv",
 	FilenameExtension: &defaultFilenameExtension,
@@ -185,15 +573,35 @@ func NewStore(options Options) (Store, error) {
 		options.Codec = DefaultOptions.Codec
 	}
 
+	// Precondition check
+	if options.DirDepth < 0 || options.DirDepth > maxDirDepth {
+		return result, errors.New("The DirDepth must be between 0 and 32")
+	}
+	if options.SingleFilename != "" {
+		if _, ok := options.Codec.(encoding.JSONcodec); !ok {
+			return result, errors.New("When storing all key-value pairs in a single file, the Codec must be a JSON codec")
+		}
+	}
+
 	err := os.MkdirAll(options.Directory, 0700)
 	if err != nil {
 		return result, err
 	}
 
+	if options.SingleFilename != "" {
+		result.single, err = openSingleFile(filepath.Join(options.Directory, options.SingleFilename), options.SyncWrites)
+		if err != nil {
+			return result, err
+		}
+	}
+
 	result.directory = options.Directory
 	result.locksLock = new(sync.Mutex)
 	result.fileLocks = make(map[string]*sync.RWMutex)
 	result.filenameExtension = *options.FilenameExtension
+	result.hashLongKeys = options.HashLongKeys
+	result.dirDepth = options.DirDepth
+	result.syncWrites = options.SyncWrites
 	result.codec = options.Codec
 
 	return result, nil
@@ -1,9 +1,14 @@
 package file_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/philippgille/gokv"
@@ -47,6 +52,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestEmptyValues(store, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		store, path := createStore(t, test.BytesCodec{})
+		defer cleanUp(store, path)
+		test.TestEmptyValues(store, t)
+	})
+}
+
 // TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
 // The store is Go map with manual locking via sync.RWMutex, so testing this is important.
 func TestStoreConcurrent(t *testing.T) {
@@ -58,6 +80,478 @@ func TestStoreConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, store)
 }
 
+// TestRawStore tests if the gokv.RawStore methods work properly.
+func TestRawStore(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestRawStore(store, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		store, path := createStore(t, encoding.Gob)
+		defer cleanUp(store, path)
+		test.TestRawStore(store, t)
+	})
+}
+
+// TestIterator tests if listing the keys works properly,
+// both with one file per key-value pair and with a single file.
+func TestIterator(t *testing.T) {
+	t.Run("files", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestIterator(store, t)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+		test.TestIterator(store, t)
+	})
+}
+
+// TestModTime tests if the modification time of the files is reported.
+func TestModTime(t *testing.T) {
+	t.Run("files", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestModTimer(store, t)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+		test.TestModTimer(store, t)
+	})
+}
+
+// TestSync tests if syncing the directories works,
+// with one file per key-value pair, with subdirectories and with a single file.
+func TestSync(t *testing.T) {
+	t.Run("files", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestSyncer(store, t)
+	})
+
+	t.Run("subdirectories", func(t *testing.T) {
+		path := generateRandomTempDBpath(t)
+		store, err := file.NewStore(file.Options{
+			Directory: path,
+			DirDepth:  2,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cleanUp(store, path)
+		test.TestSyncer(store, t)
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+		test.TestSyncer(store, t)
+	})
+}
+
+// TestSyncWrites tests if the store works when each write is synced to disk.
+func TestSyncWrites(t *testing.T) {
+	for _, options := range []file.Options{
+		{SyncWrites: true},
+		{SyncWrites: true, DirDepth: 1},
+		{SyncWrites: true, SingleFilename: "gokv.json"},
+	} {
+		options.Directory = generateRandomTempDBpath(t)
+		store, err := file.NewStore(options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		test.TestStore(store, t)
+		cleanUp(store, options.Directory)
+	}
+}
+
+// TestSingleFile tests if storing all key-value pairs in a single JSON file works properly
+// and that the key-value pairs survive reopening the store.
+func TestSingleFile(t *testing.T) {
+	t.Run("store", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+		test.TestStore(store, t)
+	})
+
+	t.Run("types", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+		test.TestTypes(store, t)
+	})
+
+	t.Run("concurrent", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+		test.TestConcurrentInteractions(t, 100, store)
+	})
+
+	t.Run("raw", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+		test.TestRawStore(store, t)
+	})
+
+	t.Run("reopen", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+
+		err := store.Set("foo", test.Foo{Bar: "baz"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Set("qux", "quux")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Set("deleted", "value")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Delete("deleted")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// All key-value pairs must be in a single file
+		entries, err := os.ReadDir(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Name() != "gokv.json" {
+			t.Fatalf("Expected only the file gokv.json in the directory, but got: %v", entries)
+		}
+		content, err := os.ReadFile(filepath.Join(path, "gokv.json"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var object map[string]any
+		err = json.Unmarshal(content, &object)
+		if err != nil {
+			t.Fatalf("The file doesn't contain a JSON object: %v", err)
+		}
+		if len(object) != 2 {
+			t.Errorf("Expected 2 key-value pairs in the file, but got: %v", object)
+		}
+
+		reopened, err := file.NewStore(file.Options{
+			Directory:      path,
+			SingleFilename: "gokv.json",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer reopened.Close()
+		foo := new(test.Foo)
+		found, err := reopened.Get("foo", foo)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || foo.Bar != "baz" {
+			t.Errorf("Expected %v to be found after reopening, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, *foo)
+		}
+		qux := new(string)
+		found, err = reopened.Get("qux", qux)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || *qux != "quux" {
+			t.Errorf("Expected \"quux\" to be found after reopening, but found was %v and the value %v", found, *qux)
+		}
+		found, err = reopened.Get("deleted", new(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Error("A deleted value was found after reopening")
+		}
+	})
+
+	t.Run("gob", func(t *testing.T) {
+		path := generateRandomTempDBpath(t)
+		defer os.RemoveAll(path)
+		_, err := file.NewStore(file.Options{
+			Directory:      path,
+			SingleFilename: "gokv.json",
+			Codec:          encoding.Gob,
+		})
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+// TestEncryptedCodec tests that two stores on the same directory can read each other's values
+// when they use an encrypted codec with the same key, and that a different key can't read them.
+func TestEncryptedCodec(t *testing.T) {
+	key := []byte("0123456789abcdef0123456789abcdef")
+	codec, err := encoding.NewEncrypted(encoding.JSON, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	store, path := createStore(t, codec)
+	defer cleanUp(store, path)
+	err = store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sameKeyCodec, err := encoding.NewEncrypted(encoding.JSON, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameKeyStore, err := file.NewStore(file.Options{Directory: path, Codec: sameKeyCodec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := test.Foo{}
+	found, err := sameKeyStore.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+
+	otherKeyCodec, err := encoding.NewEncrypted(encoding.JSON, []byte("fedcba9876543210fedcba9876543210"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKeyStore, err := file.NewStore(file.Options{Directory: path, Codec: otherKeyCodec})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = otherKeyStore.Get("foo", &actual)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestLongKeys tests that keys that are too long for a filename after escaping lead to file.ErrKeyTooLong,
+// unless the HashLongKeys option is set.
+func TestLongKeys(t *testing.T) {
+	// Each "/" is escaped to "%2F", so this key leads to a filename with more than 255 bytes
+	longKey := strings.Repeat("/", 100)
+
+	t.Run("error", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		err := store.Set(longKey, "bar")
+		if !errors.Is(err, file.ErrKeyTooLong) {
+			t.Errorf("Expected %v, but was %v", file.ErrKeyTooLong, err)
+		}
+		_, err = store.Get(longKey, new(string))
+		if !errors.Is(err, file.ErrKeyTooLong) {
+			t.Errorf("Expected %v, but was %v", file.ErrKeyTooLong, err)
+		}
+		err = store.Delete(longKey)
+		if !errors.Is(err, file.ErrKeyTooLong) {
+			t.Errorf("Expected %v, but was %v", file.ErrKeyTooLong, err)
+		}
+	})
+
+	t.Run("hashed", func(t *testing.T) {
+		path := generateRandomTempDBpath(t)
+		store, err := file.NewStore(file.Options{
+			Directory:    path,
+			HashLongKeys: true,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer cleanUp(store, path)
+
+		err = store.Set(longKey, "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Set("foo", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual := ""
+		found, err := store.Get(longKey, &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || actual != "bar" {
+			t.Errorf("Expected %q to be found, but found was %v and the value %q", "bar", found, actual)
+		}
+
+		// The hashed key can't be listed
+		keys, err := gokv.Keys(store, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0] != "foo" {
+			t.Errorf("Expected only the key %q, but was %v", "foo", keys)
+		}
+
+		err = store.Delete(longKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err = store.Get(longKey, &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Error("A value was found, but no value was expected")
+		}
+	})
+}
+
+// TestDirDepth tests if the files are nested in subdirectories with the DirDepth option.
+func TestDirDepth(t *testing.T) {
+	path := generateRandomTempDBpath(t)
+	store, err := file.NewStore(file.Options{
+		Directory: path,
+		DirDepth:  2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanUp(store, path)
+
+	test.TestStore(store, t)
+	test.TestIterator(store, t)
+
+	err = store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err := filepath.Glob(filepath.Join(path, "*", "*", "foo.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 1 {
+		t.Fatalf("Expected one file in a subdirectory two levels deep, but found %v", matches)
+	}
+	rel, err := filepath.Rel(path, matches[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	dirs := strings.Split(filepath.ToSlash(filepath.Dir(rel)), "/")
+	for _, dir := range dirs {
+		if len(dir) != 2 {
+			t.Errorf("Expected subdirectories with two hex characters, but was %v", rel)
+		}
+	}
+	if _, err = os.Stat(filepath.Join(path, "foo.json")); !os.IsNotExist(err) {
+		t.Error("Expected no file directly in the directory")
+	}
+
+	// A new store with the same options finds the same file
+	store2, err := file.NewStore(file.Options{
+		Directory: path,
+		DirDepth:  2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := ""
+	found, err := store2.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual != "bar" {
+		t.Errorf("Expected %q to be found, but found was %v and the value %q", "bar", found, actual)
+	}
+
+	err = store2.Delete("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = os.Stat(matches[0]); !os.IsNotExist(err) {
+		t.Error("Expected the file to be deleted")
+	}
+
+	// Invalid depths
+	for _, dirDepth := range []int{-1, 33} {
+		_, err = file.NewStore(file.Options{
+			Directory: path,
+			DirDepth:  dirDepth,
+		})
+		if err == nil {
+			t.Errorf("Expected an error for DirDepth %v", dirDepth)
+		}
+	}
+}
+
+// TestAtomicWrites tests that a reader never observes a partially written file,
+// even without the store's locks, and that no temporary files are left.
+func TestAtomicWrites(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+
+	// Large enough values so that writing them takes more than one write syscall
+	values := []string{strings.Repeat("a", 1<<20), strings.Repeat("b", 1<<19)}
+	err := store.Set("foo", values[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	errs := make(chan error, 1)
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			if err := store.Set("foo", values[i%2]); err != nil {
+				errs <- err
+				return
+			}
+		}
+	}()
+
+	filePath := filepath.Join(path, "foo.json")
+	for reading := true; reading; {
+		select {
+		case <-done:
+			reading = false
+		default:
+		}
+		data, err := ioutil.ReadFile(filePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !json.Valid(data) {
+			t.Fatalf("A partially written file with %v bytes was observed", len(data))
+		}
+	}
+	select {
+	case err := <-errs:
+		t.Fatal(err)
+	default:
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "foo.json" {
+		t.Errorf("Expected only the file %q, but found %v", "foo.json", entries)
+	}
+}
+
+// TestKind tests if the store reports gokv.KindFile via the gokv.KindReporter interface.
+func TestKind(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+	var s gokv.Store = store
+	if kind := gokv.KindOf(s); kind != gokv.KindFile {
+		t.Errorf("Expected %q, but was %q", gokv.KindFile, kind)
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -134,6 +628,31 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestPing tests if pinging works properly and fails when the directory is gone,
+// both with one file per key-value pair and with a single file.
+func TestPing(t *testing.T) {
+	t.Run("files", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestPinger(store, t)
+
+		err := os.RemoveAll(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = gokv.Ping(context.Background(), store)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("single file", func(t *testing.T) {
+		store, path := createSingleFileStore(t)
+		defer cleanUp(store, path)
+		test.TestPinger(store, t)
+	})
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	store, path := createStore(t, encoding.JSON)
@@ -159,6 +678,19 @@ func createStore(t *testing.T, codec encoding.Codec) (file.Store, string) {
 	return store, path
 }
 
+func createSingleFileStore(t *testing.T) (file.Store, string) {
+	path := generateRandomTempDBpath(t)
+	options := file.Options{
+		Directory:      path,
+		SingleFilename: "gokv.json",
+	}
+	store, err := file.NewStore(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, path
+}
+
 func generateRandomTempDBpath(t *testing.T) string {
 	path, err := ioutil.TempDir(os.TempDir(), "gokv")
 	if err != nil {
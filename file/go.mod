@@ -10,3 +10,9 @@ require (
 )
 
 require github.com/go-test/deep v1.1.0 // indirect
+
+replace (
+	github.com/philippgille/gokv => ../
+	github.com/philippgille/gokv/encoding => ../encoding
+	github.com/philippgille/gokv/test => ../test
+)
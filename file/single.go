@@ -0,0 +1,158 @@
+package file
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// singleFile holds all key-value pairs of a store in memory
+// and persists them as one JSON object in a single file.
+type singleFile struct {
+	lock *sync.RWMutex
+	path string
+	data map[string]json.RawMessage
+	// Whether to sync the directory after each write.
+	syncWrites bool
+}
+
+// openSingleFile loads the JSON object from the given file.
+// A non-existing file leads to an empty store.
+func openSingleFile(path string, syncWrites bool) (*singleFile, error) {
+	result := &singleFile{
+		lock:       new(sync.RWMutex),
+		path:       path,
+		data:       make(map[string]json.RawMessage),
+		syncWrites: syncWrites,
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+	if len(bytes.TrimSpace(content)) == 0 {
+		return result, nil
+	}
+	err = json.Unmarshal(content, &result.data)
+	if err != nil {
+		return nil, err
+	}
+	// The values are indented in the file, but should be returned as if they were just marshalled.
+	for k, v := range result.data {
+		buf := new(bytes.Buffer)
+		err = json.Compact(buf, v)
+		if err != nil {
+			return nil, err
+		}
+		result.data[k] = buf.Bytes()
+	}
+
+	return result, nil
+}
+
+func (f *singleFile) set(k string, data []byte) error {
+	if !json.Valid(data) {
+		return errors.New("The value isn't valid JSON, which is required when storing all key-value pairs in a single file")
+	}
+	// Copy the data so that the caller can't modify it afterwards
+	v := make(json.RawMessage, len(data))
+	copy(v, data)
+
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	prev, existed := f.data[k]
+	f.data[k] = v
+	err := f.write()
+	if err != nil {
+		// Roll back so that the in-memory state matches the file
+		if existed {
+			f.data[k] = prev
+		} else {
+			delete(f.data, k)
+		}
+	}
+	return err
+}
+
+func (f *singleFile) get(k string) (data []byte, found bool) {
+	f.lock.RLock()
+	v, found := f.data[k]
+	f.lock.RUnlock()
+	if !found {
+		return nil, false
+	}
+	data = make([]byte, len(v))
+	copy(data, v)
+	return data, true
+}
+
+// modTime returns the modification time of the file if the key exists.
+// The file is rewritten on every change, so it's the time of the last change of any key.
+func (f *singleFile) modTime(k string) (modTime time.Time, found bool, err error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	if _, found := f.data[k]; !found {
+		return time.Time{}, false, nil
+	}
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	return info.ModTime(), true, nil
+}
+
+func (f *singleFile) delete(k string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	prev, existed := f.data[k]
+	if !existed {
+		return nil
+	}
+	delete(f.data, k)
+	err := f.write()
+	if err != nil {
+		f.data[k] = prev
+	}
+	return err
+}
+
+// keys returns the keys that start with the given prefix.
+func (f *singleFile) keys(prefix string) []string {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	result := make([]string, 0, len(f.data))
+	for k := range f.data {
+		if strings.HasPrefix(k, prefix) {
+			result = append(result, k)
+		}
+	}
+	return result
+}
+
+// write writes all key-value pairs to the file, atomically via writeFile.
+// The file is always synced, because rewriting the whole file is slow anyway.
+// The caller must hold the write lock.
+func (f *singleFile) write() error {
+	// Keys are sorted and the output is indented, which makes the file easy to diff.
+	content, err := json.MarshalIndent(f.data, "", "  ")
+	if err != nil {
+		return err
+	}
+	content = append(content, '\n')
+
+	if err := writeFile(f.path, content, true); err != nil {
+		return err
+	}
+	if f.syncWrites {
+		return syncDir(filepath.Dir(f.path))
+	}
+	return nil
+}
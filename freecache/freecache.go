@@ -1,8 +1,12 @@
 package freecache
 
 import (
+	"math"
+	"time"
+
 	"github.com/coocood/freecache"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -31,6 +35,29 @@ func (s Store) Set(k string, v any) error {
 	return s.s.Set([]byte(k), data, 0)
 }
 
+// SetWithTTL stores the given value for the given key, like Set,
+// but the key-value pair expires after the given TTL.
+// After that, Get returns (false, nil) for the key.
+// FreeCache has a precision of one second, so the TTL is rounded up to full seconds.
+// Entries can still be evicted before they expire when the cache is full.
+// The key must not be "", the value must not be nil and the TTL must be positive.
+func (s Store) SetWithTTL(k string, v any, ttl time.Duration) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	expireSeconds := int(math.Ceil(ttl.Seconds()))
+	return s.s.Set([]byte(k), data, expireSeconds)
+}
+
 // Get retrieves the stored value for the given key.
 // You need to pass a pointer to the value, so in case of a struct
 // the automatic unmarshalling can populate the fields of the object
@@ -65,6 +92,11 @@ func (s Store) Delete(k string) error {
 	return nil
 }
 
+// Kind returns gokv.KindFreeCache.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindFreeCache
+}
+
 // Close closes the store.
 // When called, the cache is cleared.
 func (s Store) Close() error {
@@ -80,7 +112,10 @@ type Options struct {
 	// (if you set a lower size, 512 KiB will be used instead).
 	// If you set 0, the default size will be used.
 	// When the size is reached and you store new entries,
-	// old entries are evicted.
+	// old entries are evicted, even if they didn't expire yet.
+	// FreeCache splits the memory into 256 segments and evicts per segment,
+	// so entries can be evicted before the cache is completely full.
+	// Unlike with the persistent stores, an evicted value is lost.
 	// Optional (256 MiB by default).
 	Size int
 	// Encoding format.
@@ -38,6 +38,21 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, encoding.JSON)
+		test.TestEmptyValues(store, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		store := createStore(t, test.BytesCodec{})
+		test.TestEmptyValues(store, t)
+	})
+}
+
 // TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
 func TestStoreConcurrent(t *testing.T) {
 	store := createStore(t, encoding.JSON)
@@ -119,6 +134,12 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestTTL tests if key-value pairs expire after their TTL.
+func TestTTL(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	test.TestTTLStore(store, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	store := createStore(t, encoding.JSON)
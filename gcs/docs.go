@@ -0,0 +1,4 @@
+/*
+Package gcs contains an implementation of the `gokv.Store` interface for Google Cloud Storage.
+*/
+package gcs
@@ -0,0 +1,226 @@
+package gcs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+var defaultTimeout = 2 * time.Second
+
+// Client is a gokv.Store implementation for Google Cloud Storage.
+type Client struct {
+	c         *storage.Client
+	bucket    *storage.BucketHandle
+	keyPrefix string
+	timeOut   time.Duration
+	codec     encoding.Codec
+}
+
+// Set stores the given value for the given key.
+// Values are automatically marshalled to JSON or gob (depending on the configuration).
+// The key must not be "" and the value must not be nil.
+func (c Client) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	// First turn the passed object into something that Cloud Storage can handle.
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	w := c.bucket.Object(c.keyPrefix + k).NewWriter(tctx)
+	if _, err = w.Write(data); err != nil {
+		// Cancelling the context is the documented way to abort the upload,
+		// which happens via defer.
+		return err
+	}
+	// The object is only created or overwritten when the writer is closed successfully.
+	return w.Close()
+}
+
+// Get retrieves the stored value for the given key.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (c Client) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	r, err := c.bucket.Object(c.keyPrefix + k).NewReader(tctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return true, err
+	}
+
+	return true, c.codec.Unmarshal(data, v)
+}
+
+// ModTime returns the time when the object of the given key was last modified.
+// It only retrieves the object's attributes, not the value.
+// If no value is found it returns (time.Time{}, false, nil).
+// The key must not be "".
+func (c Client) ModTime(k string) (modTime time.Time, found bool, err error) {
+	if err := util.CheckKey(k); err != nil {
+		return time.Time{}, false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	attrs, err := c.bucket.Object(c.keyPrefix + k).Attrs(tctx)
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotExist) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+
+	return attrs.Updated, true, nil
+}
+
+// Delete deletes the stored value for the given key.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (c Client) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+	err := c.bucket.Object(c.keyPrefix + k).Delete(tctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Kind returns gokv.KindGCS.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindGCS
+}
+
+// Close closes the client.
+// It must be called to release any open resources.
+func (c Client) Close() error {
+	return c.c.Close()
+}
+
+// Options are the options for the Cloud Storage client.
+type Options struct {
+	// ID of the Google Cloud project.
+	// It's used to create the bucket if it doesn't exist yet.
+	ProjectID string
+	// Name of the Cloud Storage bucket.
+	// The bucket is automatically created if it doesn't exist yet.
+	BucketName string
+	// Path to the credentials file. For example:
+	// "/home/user/Downloads/[FILE_NAME].json".
+	// If you don't set a credentials file explicitly,
+	// the GCP SDK will look for the file path in the
+	// GOOGLE_APPLICATION_CREDENTIALS environment variable.
+	// When the STORAGE_EMULATOR_HOST environment variable is set,
+	// the SDK connects to that emulator without any credentials.
+	// Optional ("" by default, leading to a lookup via environment variable).
+	CredentialsFile string
+	// Prefix for the object names, for example "gokv/".
+	// This allows multiple stores to share one bucket.
+	// Optional ("" by default).
+	KeyPrefix string
+	// The timeout for operations.
+	// Optional (2 * time.Second by default).
+	Timeout *time.Duration
+	// Encoding format.
+	// Optional (encoding.JSON by default).
+	Codec encoding.Codec
+}
+
+// DefaultOptions is an Options object with default values.
+// CredentialsFile: "", KeyPrefix: "", Timeout: 2 * time.Second, Codec: encoding.JSON
+var DefaultOptions = Options{
+	Timeout: &defaultTimeout,
+	Codec:   encoding.JSON,
+	// No need to set CredentialsFile or KeyPrefix because their Go zero values are fine.
+}
+
+// NewClient creates a new Cloud Storage client.
+//
+// You must call the Close() method on the client when you're done working with it.
+func NewClient(options Options) (Client, error) {
+	result := Client{}
+
+	// Precondition check
+	if options.ProjectID == "" {
+		return result, errors.New("The ProjectID in the options must not be empty")
+	}
+	if options.BucketName == "" {
+		return result, errors.New("The BucketName in the options must not be empty")
+	}
+
+	// Set default values
+	if options.Timeout == nil {
+		options.Timeout = DefaultOptions.Timeout
+	}
+	if options.Codec == nil {
+		options.Codec = DefaultOptions.Codec
+	}
+
+	// Don't pass a context with timeout to NewClient,
+	// because it would interfere with credential refreshing.
+	var gcsClient *storage.Client
+	var err error
+	if options.CredentialsFile == "" {
+		gcsClient, err = storage.NewClient(context.Background())
+	} else {
+		gcsClient, err = storage.NewClient(context.Background(), option.WithCredentialsFile(options.CredentialsFile))
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// Create the bucket if it doesn't exist yet.
+	// Also serves as connection test.
+	bucket := gcsClient.Bucket(options.BucketName)
+	tctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = bucket.Attrs(tctx)
+	if errors.Is(err, storage.ErrBucketNotExist) {
+		err = bucket.Create(tctx, options.ProjectID, nil)
+	}
+	if err != nil {
+		_ = gcsClient.Close()
+		return result, err
+	}
+
+	result.c = gcsClient
+	result.bucket = bucket
+	result.keyPrefix = options.KeyPrefix
+	result.timeOut = *options.Timeout
+	result.codec = options.Codec
+
+	return result, nil
+}
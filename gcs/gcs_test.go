@@ -0,0 +1,251 @@
+package gcs_test
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gcs"
+	"github.com/philippgille/gokv/test"
+)
+
+// For the fake-gcs-server Docker container.
+// See https://github.com/fsouza/fake-gcs-server.
+var emulatorHost = "localhost:4443"
+
+// TestConnection only tests the connection to the emulator, allowing to work on connection options with `go test -run TestConnection .` for example.
+func TestConnection(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to the Cloud Storage emulator could be established. Probably not running in a proper test environment.")
+	}
+}
+
+// TestClient tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestClient(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON, "")
+		defer client.Close()
+		test.TestStore(client, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		client := createClient(t, encoding.Gob, "")
+		defer client.Close()
+		test.TestStore(client, t)
+	})
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON, "")
+		defer client.Close()
+		test.TestTypes(client, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		client := createClient(t, encoding.Gob, "")
+		defer client.Close()
+		test.TestTypes(client, t)
+	})
+}
+
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON, "")
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{}, "")
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+}
+
+// TestClientConcurrent launches a bunch of goroutines that concurrently work with the Cloud Storage client.
+func TestClientConcurrent(t *testing.T) {
+	client := createClient(t, encoding.JSON, "")
+	defer client.Close()
+
+	goroutineCount := 100
+
+	test.TestConcurrentInteractions(t, goroutineCount, client)
+}
+
+// TestModTime tests if the modification time of the objects is reported.
+func TestModTime(t *testing.T) {
+	client := createClient(t, encoding.JSON, "")
+	defer client.Close()
+	test.TestModTimer(client, t)
+}
+
+// TestKeyPrefix tests that two clients with different key prefixes don't see each other's values.
+func TestKeyPrefix(t *testing.T) {
+	clientA := createClient(t, encoding.JSON, "a/")
+	defer clientA.Close()
+	clientB := createClient(t, encoding.JSON, "b/")
+	defer clientB.Close()
+
+	err := clientA.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := clientB.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+	found, err = clientA.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	// Test empty key
+	client := createClient(t, encoding.JSON, "")
+	defer client.Close()
+	err := client.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = client.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = client.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestNil tests the behaviour when passing nil or pointers to nil values to some methods.
+func TestNil(t *testing.T) {
+	// Test setting nil
+
+	t.Run("set nil with JSON marshalling", func(t *testing.T) {
+		client := createClient(t, encoding.JSON, "")
+		defer client.Close()
+		err := client.Set("foo", nil)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	t.Run("set nil with Gob marshalling", func(t *testing.T) {
+		client := createClient(t, encoding.Gob, "")
+		defer client.Close()
+		err := client.Set("foo", nil)
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+
+	// Test passing nil or pointer to nil value for retrieval
+
+	createTest := func(codec encoding.Codec) func(t *testing.T) {
+		return func(t *testing.T) {
+			client := createClient(t, codec, "")
+			defer client.Close()
+
+			// Prep
+			err := client.Set("foo", test.Foo{Bar: "baz"})
+			if err != nil {
+				t.Error(err)
+			}
+
+			_, err = client.Get("foo", nil) // actually nil
+			if err == nil {
+				t.Error("An error was expected")
+			}
+
+			var i any // actually nil
+			_, err = client.Get("foo", i)
+			if err == nil {
+				t.Error("An error was expected")
+			}
+
+			var valPtr *test.Foo // nil value
+			_, err = client.Get("foo", valPtr)
+			if err == nil {
+				t.Error("An error was expected")
+			}
+		}
+	}
+	t.Run("get with nil / nil value parameter", createTest(encoding.JSON))
+	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	client := createClient(t, encoding.JSON, "")
+	err := client.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// checkConnection returns true if a connection could be made, false otherwise.
+func checkConnection() bool {
+	err := os.Setenv("STORAGE_EMULATOR_HOST", emulatorHost)
+	if err != nil {
+		log.Printf("Emulator environment variable couldn't be set: %v\n", err)
+		return false
+	}
+	gcsClient, err := storage.NewClient(context.Background())
+	if err != nil {
+		log.Printf("Client couldn't be created: %v\n", err)
+		return false
+	}
+	defer gcsClient.Close()
+
+	tctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = gcsClient.Buckets(tctx, "gokv").Next()
+	if err != nil && !errors.Is(err, iterator.Done) {
+		log.Printf("An error occurred during testing the connection to the server: %v\n", err)
+		return false
+	}
+
+	return true
+}
+
+func createClient(t *testing.T, codec encoding.Codec, keyPrefix string) gcs.Client {
+	if !checkConnection() {
+		t.Skip("No connection to the Cloud Storage emulator could be established. Probably not running in a proper test environment.")
+	}
+	options := gcs.Options{
+		ProjectID:  "gokv",
+		BucketName: "gokv",
+		KeyPrefix:  keyPrefix,
+		Codec:      codec,
+	}
+	client, err := gcs.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
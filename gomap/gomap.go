@@ -1,8 +1,14 @@
 package gomap
 
 import (
+	"bytes"
+	"container/list"
+	"context"
+	"errors"
+	"strings"
 	"sync"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -12,6 +18,12 @@ type Store struct {
 	m     map[string][]byte
 	lock  *sync.RWMutex
 	codec encoding.Codec
+	// Only set when MaxItems is > 0.
+	// The keys in the order of their last access, with the most recently used key at the front,
+	// and the list elements by key.
+	maxItems int
+	order    *list.List
+	elements map[string]*list.Element
 }
 
 // Set stores the given value for the given key.
@@ -27,10 +39,39 @@ func (s Store) Set(k string, v any) error {
 		return err
 	}
 
+	s.set(k, data)
+	return nil
+}
+
+// SetRaw stores the given bytes for the given key, without marshalling them.
+// The bytes must have been marshalled with the store's codec.
+// They're copied, so the caller can modify the passed slice afterwards.
+// The key must not be "" and the data must not be nil.
+func (s Store) SetRaw(k string, data []byte) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+	// util.CheckVal doesn't detect a nil slice, because it's wrapped in a non-nil interface
+	if data == nil {
+		return errors.New("The passed value is nil, which is not allowed")
+	}
+
+	// Copy the data so that the caller can't modify the stored value afterwards
+	s.set(k, bytes.Clone(data))
+	return nil
+}
+
+// set stores the data without copying it.
+func (s Store) set(k string, data []byte) {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.m[k] = data
-	return nil
+	if s.maxItems > 0 {
+		s.touch(k)
+		for len(s.m) > s.maxItems {
+			s.evict()
+		}
+	}
 }
 
 // Get retrieves the stored value for the given key.
@@ -44,17 +85,53 @@ func (s Store) Get(k string, v any) (found bool, err error) {
 		return false, err
 	}
 
+	data, found := s.get(k)
+	if !found {
+		return false, nil
+	}
+
+	return true, s.codec.Unmarshal(data, v)
+}
+
+// GetRaw retrieves the stored bytes for the given key, without unmarshalling them.
+// The returned bytes are a copy, so the caller can modify them.
+// If no value is found it returns (nil, false, nil).
+// The key must not be "".
+func (s Store) GetRaw(k string) (data []byte, found bool, err error) {
+	if err := util.CheckKey(k); err != nil {
+		return nil, false, err
+	}
+
+	data, found = s.get(k)
+	// Copy the data so that the caller can't modify the stored value
+	return bytes.Clone(data), found, nil
+}
+
+// get returns the stored bytes without copying them, so they must not be modified.
+func (s Store) get(k string) (data []byte, found bool) {
+	if s.maxItems > 0 {
+		// Reading changes the order of the keys, so it requires the write lock.
+		s.lock.Lock()
+		data, found = s.m[k]
+		if found {
+			s.touch(k)
+		}
+		s.lock.Unlock()
+		return data, found
+	}
+
 	s.lock.RLock()
-	data, found := s.m[k]
+	data, found = s.m[k]
 	// Unlock right after reading instead of with defer(),
 	// because following unmarshalling will take some time
 	// and we don't want to block writing threads until that's done.
 	s.lock.RUnlock()
-	if !found {
-		return false, nil
-	}
+	return data, found
+}
 
-	return true, s.codec.Unmarshal(data, v)
+// Unmarshal unmarshals the bytes that were returned by GetRaw with the store's codec.
+func (s Store) Unmarshal(data []byte, v any) error {
+	return s.codec.Unmarshal(data, v)
 }
 
 // Delete deletes the stored value for the given key.
@@ -68,9 +145,92 @@ func (s Store) Delete(k string) error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	delete(s.m, k)
+	if s.maxItems > 0 {
+		if e, ok := s.elements[k]; ok {
+			s.order.Remove(e)
+			delete(s.elements, k)
+		}
+	}
+	return nil
+}
+
+// ForEach calls fn for each key that starts with the given prefix.
+// The keys are collected before fn is called, so fn can use the store without deadlocking.
+// If fn returns an error, the iteration stops and the error is returned.
+func (s Store) ForEach(prefix string, fn func(k string) error) error {
+	s.lock.RLock()
+	keys := make([]string, 0, len(s.m))
+	for k := range s.m {
+		if strings.HasPrefix(k, prefix) {
+			keys = append(keys, k)
+		}
+	}
+	s.lock.RUnlock()
+
+	for _, k := range keys {
+		if err := fn(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// touch marks the key as the most recently used one.
+// The caller must hold the write lock.
+func (s Store) touch(k string) {
+	if e, ok := s.elements[k]; ok {
+		s.order.MoveToFront(e)
+		return
+	}
+	s.elements[k] = s.order.PushFront(k)
+}
+
+// evict deletes the least recently used key-value pair.
+// The caller must hold the write lock.
+func (s Store) evict() {
+	e := s.order.Back()
+	k := e.Value.(string)
+	s.order.Remove(e)
+	delete(s.elements, k)
+	delete(s.m, k)
+}
+
+// Len returns the number of stored key-value pairs.
+// It's not part of the gokv.Store interface, but specific to this store.
+func (s Store) Len() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return len(s.m)
+}
+
+// Clear deletes all stored key-value pairs, for example between tests.
+// It's not part of the gokv.Store interface, but specific to this store.
+func (s Store) Clear() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	// The map is cleared instead of replaced, because copies of the store refer to the same map.
+	for k := range s.m {
+		delete(s.m, k)
+	}
+	if s.maxItems > 0 {
+		s.order.Init()
+		for k := range s.elements {
+			delete(s.elements, k)
+		}
+	}
+	return nil
+}
+
+// Ping always returns nil, because the map is in memory.
+func (s Store) Ping(ctx context.Context) error {
 	return nil
 }
 
+// Kind returns gokv.KindGoMap.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindGoMap
+}
+
 // Close closes the store.
 // When called, the store's pointer to the internal Go map is set to nil,
 // leading to the map being free for garbage collection.
@@ -78,6 +238,8 @@ func (s Store) Close() error {
 	s.lock.Lock()
 	defer s.lock.Unlock()
 	s.m = nil
+	s.order = nil
+	s.elements = nil
 	return nil
 }
 
@@ -86,10 +248,16 @@ type Options struct {
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
+	// Maximum number of key-value pairs, for using the store as a bounded cache.
+	// When a Set exceeds it, the least recently used key-value pair is evicted.
+	// Both Set and Get count as use, so Get requires the write lock in this case,
+	// which makes concurrent reads slower.
+	// Optional (0 by default, which means no limit).
+	MaxItems int
 }
 
 // DefaultOptions is an Options object with default values.
-// Codec: encoding.JSON
+// Codec: encoding.JSON, MaxItems: 0
 var DefaultOptions = Options{
 	Codec: encoding.JSON,
 }
@@ -103,9 +271,15 @@ func NewStore(options Options) Store {
 		options.Codec = DefaultOptions.Codec
 	}
 
-	return Store{
+	result := Store{
 		m:     make(map[string][]byte),
 		lock:  new(sync.RWMutex),
 		codec: options.Codec,
 	}
+	if options.MaxItems > 0 {
+		result.maxItems = options.MaxItems
+		result.order = list.New()
+		result.elements = make(map[string]*list.Element)
+	}
+	return result
 }
@@ -1,11 +1,16 @@
 package gomap_test
 
 import (
+	"bytes"
+	"context"
 	"testing"
+	"time"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/gomap"
 	"github.com/philippgille/gokv/test"
+	"github.com/philippgille/gokv/util"
 )
 
 // TestStore tests if reading from, writing to and deleting from the store works properly.
@@ -39,6 +44,21 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, encoding.JSON)
+		test.TestEmptyValues(store, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		store := createStore(t, test.BytesCodec{})
+		test.TestEmptyValues(store, t)
+	})
+}
+
 // TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
 // The store is Go map with manual locking via sync.RWMutex, so testing this is important.
 func TestStoreConcurrent(t *testing.T) {
@@ -49,6 +69,326 @@ func TestStoreConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, store)
 }
 
+// TestRawStore tests if the gokv.RawStore methods work properly.
+func TestRawStore(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, encoding.JSON)
+		test.TestRawStore(store, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, encoding.Gob)
+		test.TestRawStore(store, t)
+	})
+}
+
+// TestIterator tests if listing the keys works properly.
+func TestIterator(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	test.TestIterator(store, t)
+}
+
+// TestGetMulti tests if one retrieval populates multiple destinations.
+func TestGetMulti(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	err := store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actualStruct := test.Foo{}
+	actualMap := map[string]any{}
+	found, err := gokv.GetMulti(store, "foo", &actualStruct, &actualMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if actualStruct.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actualStruct.Bar)
+	}
+	if actualMap["Bar"] != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actualMap["Bar"])
+	}
+
+	found, err = gokv.GetMulti(store, "bar", &actualStruct, &actualMap)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+}
+
+// TestGetRawAndDecode tests if the stored bytes are returned even if unmarshalling fails.
+func TestGetRawAndDecode(t *testing.T) {
+	store := createStore(t, encoding.Gob)
+	// Stored with a different codec than the store uses
+	stored := []byte(`{"Bar":"baz"}`)
+	err := store.SetRaw("foo", stored)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := test.Foo{}
+	raw, found, err := gokv.GetRawAndDecode(store, "foo", &actual)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+	if !bytes.Equal(raw, stored) {
+		t.Errorf("Expected %q, but was %q", stored, raw)
+	}
+
+	// Matching codec
+	err = store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, found, err = gokv.GetRawAndDecode(store, "foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+	if len(raw) == 0 {
+		t.Error("Expected the stored bytes, but got none")
+	}
+
+	raw, found, err = gokv.GetRawAndDecode(store, "bar", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found || raw != nil {
+		t.Errorf("Expected no value to be found, but found was %v and the bytes %q", found, raw)
+	}
+}
+
+// TestWrapStoreCtx tests if a store without native context support can be used as gokv.StoreCtx.
+func TestWrapStoreCtx(t *testing.T) {
+	store := util.WrapStoreCtx(createStore(t, encoding.JSON))
+
+	err := store.SetCtx(context.Background(), "foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := test.Foo{}
+	found, err := store.GetCtx(context.Background(), "foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = store.SetCtx(canceledCtx, "foo", test.Foo{Bar: "qux"})
+	if err != context.Canceled {
+		t.Errorf("Expected %v, but was %v", context.Canceled, err)
+	}
+	_, err = store.GetCtx(canceledCtx, "foo", &actual)
+	if err != context.Canceled {
+		t.Errorf("Expected %v, but was %v", context.Canceled, err)
+	}
+	err = store.DeleteCtx(canceledCtx, "foo")
+	if err != context.Canceled {
+		t.Errorf("Expected %v, but was %v", context.Canceled, err)
+	}
+
+	// The canceled operations must not have had any effect
+	found, err = store.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+}
+
+// TestDeleteIfNotSupported tests that conditional deletes are reported as unsupported.
+func TestDeleteIfNotSupported(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	_, err := gokv.DeleteIf(store, "foo", "bar")
+	if err != gokv.ErrConditionalDeleteNotSupported {
+		t.Errorf("Expected %v, but was %v", gokv.ErrConditionalDeleteNotSupported, err)
+	}
+}
+
+// TestWrapBatchStore tests if a store without native batch support can be used as gokv.BatchStore.
+func TestWrapBatchStore(t *testing.T) {
+	store := util.WrapBatchStore(createStore(t, encoding.JSON))
+	test.TestBatchStore(store, t)
+}
+
+// TestCompareAndSwapNotSupported tests that compare-and-swap is reported as unsupported.
+func TestCompareAndSwapNotSupported(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	_, err := gokv.CompareAndSwap(store, "foo", "bar", "baz")
+	if err != gokv.ErrCompareAndSwapNotSupported {
+		t.Errorf("Expected gokv.ErrCompareAndSwapNotSupported, but was %v", err)
+	}
+}
+
+// TestIncrementNotSupported tests that atomic counters are reported as unsupported.
+func TestIncrementNotSupported(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	_, err := gokv.Increment(store, "foo", 1)
+	if err != gokv.ErrIncrementUnsupported {
+		t.Errorf("Expected gokv.ErrIncrementUnsupported, but was %v", err)
+	}
+}
+
+// TestUpdateFuncNotSupported tests that updates via compare-and-swap are reported as unsupported.
+func TestUpdateFuncNotSupported(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	_, err := gokv.UpdateFunc(store, "foo", []string{}, func(current any) (any, error) {
+		return current, nil
+	})
+	if err != gokv.ErrCompareAndSwapNotSupported {
+		t.Errorf("Expected gokv.ErrCompareAndSwapNotSupported, but was %v", err)
+	}
+}
+
+// TestTTLNotSupported tests that expiring key-value pairs are reported as unsupported.
+func TestTTLNotSupported(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	err := gokv.SetWithTTL(store, "foo", test.Foo{Bar: "baz"}, time.Second)
+	if err != gokv.ErrTTLUnsupported {
+		t.Errorf("Expected gokv.ErrTTLUnsupported, but was %v", err)
+	}
+}
+
+// TestLenAndClear tests if the number of key-value pairs is reported and if clearing the store works,
+// also with MaxItems.
+func TestLenAndClear(t *testing.T) {
+	for _, options := range []gomap.Options{gomap.DefaultOptions, {MaxItems: 3}} {
+		store := gomap.NewStore(options)
+		if store.Len() != 0 {
+			t.Errorf("Expected 0 key-value pairs, but was %v", store.Len())
+		}
+		for _, k := range []string{"1", "2", "3"} {
+			err := store.Set(k, k)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Overwriting doesn't change the length
+		err := store.Set("1", "one")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if store.Len() != 3 {
+			t.Errorf("Expected 3 key-value pairs, but was %v", store.Len())
+		}
+
+		err = store.Clear()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if store.Len() != 0 {
+			t.Errorf("Expected 0 key-value pairs after clearing, but was %v", store.Len())
+		}
+		found, err := store.Get("1", new(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Error("A value was found, but no value was expected")
+		}
+
+		// The store is still usable
+		test.TestStore(store, t)
+	}
+}
+
+// TestMaxItems tests that the least recently used key-value pairs are evicted
+// when the number of key-value pairs exceeds MaxItems.
+func TestMaxItems(t *testing.T) {
+	store := gomap.NewStore(gomap.Options{
+		MaxItems: 3,
+	})
+	test.TestStore(store, t)
+
+	for _, k := range []string{"1", "2", "3", "4", "5"} {
+		err := store.Set(k, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectKeys(t, store, []string{"3", "4", "5"}, []string{"1", "2"})
+
+	// Get counts as use, so "4" is the least recently used key now
+	_, err := store.Get("3", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("6", "6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, store, []string{"3", "5", "6"}, []string{"4"})
+
+	// Overwriting an existing key doesn't evict anything, deleting makes room
+	err = store.Set("5", "five")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Delete("3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("7", "7")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, store, []string{"5", "6", "7"}, []string{"3"})
+}
+
+func expectKeys(t *testing.T, store gomap.Store, present, absent []string) {
+	t.Helper()
+
+	for _, k := range present {
+		_, found, err := store.GetRaw(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Errorf("Expected key %v to be present, but it was evicted", k)
+		}
+	}
+	for _, k := range absent {
+		_, found, err := store.GetRaw(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Errorf("Expected key %v to be evicted, but it was found", k)
+		}
+	}
+}
+
+// TestKind tests if the store reports gokv.KindGoMap, and that a store wrapper without the method reports gokv.KindUnknown.
+func TestKind(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	if kind := gokv.KindOf(store); kind != gokv.KindGoMap {
+		t.Errorf("Expected %q, but was %q", gokv.KindGoMap, kind)
+	}
+	wrapper := struct{ gokv.Store }{store}
+	if kind := gokv.KindOf(wrapper); kind != gokv.KindUnknown {
+		t.Errorf("Expected %q, but was %q", gokv.KindUnknown, kind)
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -121,6 +461,12 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestPing tests if pinging works properly.
+func TestPing(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	test.TestPinger(store, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	store := createStore(t, encoding.JSON)
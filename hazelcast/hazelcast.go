@@ -7,6 +7,7 @@ import (
 	hazelcast "github.com/hazelcast/hazelcast-go-client"
 	"github.com/hazelcast/hazelcast-go-client/logger"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -80,6 +81,11 @@ func (c Client) Delete(k string) error {
 	return c.m.Delete(context.Background(), k)
 }
 
+// Kind returns gokv.KindHazelcast.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindHazelcast
+}
+
 // Close closes the client.
 // This must be called to properly shut down connections and services (e.g. HeartBeatService).
 func (c Client) Close() error {
@@ -94,7 +100,13 @@ type Options struct {
 	// If the server dies, the client will automatically switch to another server in the cluster.
 	// Optional ("localhost:5701" by default).
 	Address string
+	// Name of the Hazelcast cluster to connect to.
+	// The server rejects the connection if it's not the name of its cluster.
+	// Optional ("dev" by default, which is the default of the Hazelcast server as well).
+	ClusterName string
 	// Name of the Hazelcast distributed map to use.
+	// Different services that use the same cluster should use different map names,
+	// otherwise their keys can collide.
 	// Optional ("gokv" by default).
 	MapName string
 	// Encoding format.
@@ -103,11 +115,12 @@ type Options struct {
 }
 
 // DefaultOptions is an Options object with default values.
-// Addresses: "localhost:5701", MapName: "gokv", Codec: encoding.JSON
+// Addresses: "localhost:5701", ClusterName: "dev", MapName: "gokv", Codec: encoding.JSON
 var DefaultOptions = Options{
-	Address: "localhost:5701",
-	MapName: "gokv",
-	Codec:   encoding.JSON,
+	Address:     "localhost:5701",
+	ClusterName: "dev",
+	MapName:     "gokv",
+	Codec:       encoding.JSON,
 }
 
 // NewClient creates a new Hazelcast client.
@@ -118,6 +131,9 @@ func NewClient(options Options) (Client, error) {
 	if options.Address == "" {
 		options.Address = DefaultOptions.Address
 	}
+	if options.ClusterName == "" {
+		options.ClusterName = DefaultOptions.ClusterName
+	}
 	if options.MapName == "" {
 		options.MapName = DefaultOptions.MapName
 	}
@@ -126,6 +142,7 @@ func NewClient(options Options) (Client, error) {
 	}
 
 	config := hazelcast.NewConfig()
+	config.Cluster.Name = options.ClusterName
 	config.Cluster.Network.SetAddresses(options.Address)
 	config.Logger.Level = logger.OffLevel
 	client, err := hazelcast.StartNewClientWithConfig(context.Background(), config)
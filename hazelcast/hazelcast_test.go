@@ -44,6 +44,21 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the Hazelcast client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -53,6 +68,48 @@ func TestClientConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, client)
 }
 
+// TestMapName tests that clients with different map names don't see each other's key-value pairs.
+func TestMapName(t *testing.T) {
+	clientA := createClient(t, encoding.JSON)
+	defer clientA.Close()
+	options := hazelcast.Options{
+		ClusterName: "dev",
+		MapName:     "gokv-other",
+	}
+	clientB, err := hazelcast.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientB.Close()
+
+	err = clientA.Set("foo", test.Foo{Bar: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientA.Delete("foo")
+	found, err := clientB.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value from another map was found")
+	}
+
+	err = clientB.Set("foo", test.Foo{Bar: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer clientB.Delete("foo")
+	actual := test.Foo{}
+	found, err = clientA.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "a" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "a"}, found, actual)
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -7,6 +7,7 @@ import (
 
 	ignite "github.com/amsokol/ignite-go-client/binary/v1"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -80,6 +81,11 @@ func (c Client) Delete(k string) error {
 	return err
 }
 
+// Kind returns gokv.KindIgnite.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindIgnite
+}
+
 // Close closes the client.
 // It must be called to shut down all connections to the Apache Ignite server.
 func (c Client) Close() error {
@@ -0,0 +1,36 @@
+package gokv
+
+import "errors"
+
+// ErrIterationUnsupported is returned when keys are listed for a store that can't enumerate its keys.
+var ErrIterationUnsupported = errors.New("The store doesn't support iterating over its keys")
+
+// Iterator is an optional interface for stores that can enumerate their keys,
+// for example to build an admin view or an export tool.
+// Check for it with a type assertion or use the Keys function.
+type Iterator interface {
+	// ForEach calls fn for each key that starts with the given prefix.
+	// An empty prefix matches all keys.
+	// The order of the keys is undefined, and keys that are set or deleted
+	// while the iteration is running may or may not be passed to fn.
+	// If fn returns an error, the iteration stops and the error is returned.
+	ForEach(prefix string, fn func(k string) error) error
+}
+
+// Keys returns all keys that start with the given prefix via the store if it implements the Iterator interface.
+// Otherwise ErrIterationUnsupported is returned.
+func Keys(store Store, prefix string) ([]string, error) {
+	iterator, ok := store.(Iterator)
+	if !ok {
+		return nil, ErrIterationUnsupported
+	}
+	var keys []string
+	err := iterator.ForEach(prefix, func(k string) error {
+		keys = append(keys, k)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
@@ -0,0 +1,4 @@
+/*
+Package jsonpatch contains a `gokv.Store` wrapper that can apply JSON Patch (RFC 6902) and JSON Merge Patch (RFC 7386) documents to stored JSON values.
+*/
+package jsonpatch
@@ -0,0 +1,196 @@
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"hash/fnv"
+	"math/rand"
+	"sync"
+	"time"
+
+	evanphx "github.com/evanphx/json-patch/v5"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// ErrNotFound is returned by Patch when no value is stored for the key.
+var ErrNotFound = errors.New("no value is stored for the key")
+
+// ErrNotCanonical is returned by Patch when the inner store is a gokv.CASStore,
+// but the stored JSON isn't in the compact form that encoding.JSON marshals it to,
+// for example because it contains whitespace or was written by another JSON encoder.
+// Such a value can't be compared with compare-and-swap, because the expected value is marshalled first.
+var ErrNotCanonical = errors.New("The stored JSON isn't in the form that encoding.JSON marshals it to, so it can't be compared")
+
+// Number of attempts after which Patch gives up with gokv.ErrUpdateConflict, like gokv.UpdateFunc.
+const maxPatchAttempts = 100
+
+// The backoff between the attempts of Patch grows by the step with each attempt, up to the maximum,
+// and is randomized, so that concurrent patches don't keep conflicting with each other.
+const (
+	patchBackoffStep = time.Millisecond
+	maxPatchBackoff  = 50 * time.Millisecond
+)
+
+// Number of locks that the keys are distributed over.
+const keyLockCount = 256
+
+// Store is a gokv.Store implementation that forwards all calls to another store
+// and additionally offers a Patch method for partial updates of JSON values,
+// for example for implementing HTTP PATCH.
+type Store struct {
+	inner gokv.Store
+	// For locking the read-modify-write cycle of Patch per key.
+	// Keys are hashed to a fixed number of locks, so the memory doesn't grow with the number of keys.
+	keyLocks []sync.Mutex
+}
+
+// Set stores the given value for the given key in the inner store, overwriting any existing value.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	lock := s.keyLock(k)
+	lock.Lock()
+	defer lock.Unlock()
+	return s.inner.Set(k, v)
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	return s.inner.Get(k, v)
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	lock := s.keyLock(k)
+	lock.Lock()
+	defer lock.Unlock()
+	return s.inner.Delete(k)
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// Patch applies the given patch document to the JSON value that's stored for the given key.
+// If the patch is a JSON array it's applied as JSON Patch (RFC 6902),
+// otherwise as JSON Merge Patch (RFC 7386).
+// If no value is stored for the key, ErrNotFound is returned.
+// If the patch can't be applied, for example because a "test" operation fails,
+// an error is returned and the stored value stays unchanged.
+//
+// The read-modify-write cycle is locked per key, so concurrent calls to Patch, Set and Delete
+// on the same Store don't lead to lost updates.
+// If the inner store implements gokv.CASStore, the patched value is only stored
+// if the value wasn't changed in the meantime, otherwise the patch is applied again to the new value.
+// This also prevents lost updates when other processes write to the inner store.
+// After 100 attempts gokv.ErrUpdateConflict is returned.
+// With a CAS store, the stored JSON must be in the form that encoding.JSON marshals it to,
+// otherwise ErrNotCanonical is returned.
+// The key must not be "" and the patch must not be empty.
+func (s Store) Patch(k string, patch []byte) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+	patch = bytes.TrimSpace(patch)
+	if len(patch) == 0 {
+		return errors.New("The patch must not be empty")
+	}
+
+	apply := evanphx.MergePatch
+	if patch[0] == '[' {
+		decodedPatch, err := evanphx.DecodePatch(patch)
+		if err != nil {
+			return err
+		}
+		apply = func(doc, _ []byte) ([]byte, error) {
+			return decodedPatch.Apply(doc)
+		}
+	}
+
+	lock := s.keyLock(k)
+	lock.Lock()
+	defer lock.Unlock()
+
+	casStore, isCASStore := s.inner.(gokv.CASStore)
+	for attempt := 0; attempt < maxPatchAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(patchBackoff(attempt))
+		}
+
+		var existing json.RawMessage
+		found, err := s.inner.Get(k, &existing)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return ErrNotFound
+		}
+
+		patched, err := apply(existing, patch)
+		if err != nil {
+			return err
+		}
+
+		if !isCASStore {
+			return s.inner.Set(k, json.RawMessage(patched))
+		}
+		// The CAS store marshals the expected value before comparing it with the stored bytes,
+		// which compacts the JSON. If that doesn't result in the exact bytes that were read,
+		// the comparison can never succeed, no matter how often it's retried.
+		marshalled, err := encoding.JSON.Marshal(existing)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(marshalled, existing) {
+			return ErrNotCanonical
+		}
+		swapped, err := casStore.CompareAndSwap(k, existing, json.RawMessage(patched))
+		if err != nil || swapped {
+			return err
+		}
+	}
+	return gokv.ErrUpdateConflict
+}
+
+// patchBackoff returns a random duration up to the backoff for the given attempt.
+func patchBackoff(attempt int) time.Duration {
+	backoff := time.Duration(attempt) * patchBackoffStep
+	if backoff > maxPatchBackoff {
+		backoff = maxPatchBackoff
+	}
+	return time.Duration(rand.Int63n(int64(backoff))) + 1
+}
+
+// keyLock returns the lock for the given key, which can be shared with other keys.
+func (s Store) keyLock(k string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(k))
+	return &s.keyLocks[h.Sum32()%keyLockCount]
+}
+
+// NewStore creates a new jsonpatch store that wraps the given store.
+// The inner store must use encoding.JSON as codec, because the stored values are patched as JSON.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store) Store {
+	return Store{
+		inner:    inner,
+		keyLocks: make([]sync.Mutex, keyLockCount),
+	}
+}
@@ -0,0 +1,288 @@
+package jsonpatch_test
+
+import (
+	"bytes"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/jsonpatch"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+func TestStore(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.Options{Codec: encoding.JSON}))
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.Options{Codec: encoding.JSON}))
+	test.TestTypes(store, t)
+}
+
+// TestPatch tests that merge patches and JSON patches are applied to the stored value.
+func TestPatch(t *testing.T) {
+	t.Run("merge patch", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.Options{Codec: encoding.JSON}))
+		err := store.Set("foo", map[string]any{
+			"a": "1",
+			"b": "2",
+			"nested": map[string]any{
+				"x": "1",
+				"y": "2",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Patch("foo", []byte(`{"b": null, "c": "3", "nested": {"x": null, "z": "3"}}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]any{
+			"a": "1",
+			"c": "3",
+			"nested": map[string]any{
+				"y": "2",
+				"z": "3",
+			},
+		}
+		assertStored(t, store, "foo", expected)
+	})
+
+	t.Run("JSON patch", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.Options{Codec: encoding.JSON}))
+		err := store.Set("foo", map[string]any{
+			"a":    "1",
+			"b":    "2",
+			"list": []any{"x"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Patch("foo", []byte(`[
+			{"op": "test", "path": "/a", "value": "1"},
+			{"op": "remove", "path": "/b"},
+			{"op": "add", "path": "/c", "value": "3"},
+			{"op": "add", "path": "/list/-", "value": "y"}
+		]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]any{
+			"a":    "1",
+			"c":    "3",
+			"list": []any{"x", "y"},
+		}
+		assertStored(t, store, "foo", expected)
+	})
+
+	t.Run("failed test operation", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.Options{Codec: encoding.JSON}))
+		original := map[string]any{"a": "1"}
+		err := store.Set("foo", original)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Patch("foo", []byte(`[
+			{"op": "add", "path": "/b", "value": "2"},
+			{"op": "test", "path": "/a", "value": "2"}
+		]`))
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		assertStored(t, store, "foo", original)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.Options{Codec: encoding.JSON}))
+		err := store.Patch("foo", []byte(`{"a": "1"}`))
+		if !errors.Is(err, jsonpatch.ErrNotFound) {
+			t.Errorf("Expected ErrNotFound, but was: %v", err)
+		}
+	})
+}
+
+// TestPatchConcurrent tests that concurrent patches of the same key don't lead to lost updates,
+// both with the per key locks of one store and with compare-and-swap of the inner store
+// that's shared by two stores.
+func TestPatchConcurrent(t *testing.T) {
+	inner := casStore{
+		Store: gomap.NewStore(gomap.Options{Codec: encoding.JSON}),
+		lock:  new(sync.Mutex),
+	}
+	stores := []jsonpatch.Store{createStore(t, inner), createStore(t, inner)}
+	err := stores[0].Set("foo", map[string]any{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	goroutineCount := 100
+	expected := make(map[string]any, goroutineCount)
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		key := strconv.Itoa(i)
+		expected[key] = key
+		store := stores[i%2]
+		go func() {
+			defer waitGroup.Done()
+			if err := store.Patch("foo", []byte(`{"`+key+`": "`+key+`"}`)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	assertStored(t, stores[0], "foo", expected)
+}
+
+// TestPatchNotCanonical tests that Patch with a CAS store fails instead of retrying forever
+// when the stored JSON isn't in the form that encoding.JSON marshals it to,
+// because then the compare-and-swap can never succeed.
+func TestPatchNotCanonical(t *testing.T) {
+	inner := casStore{
+		Store: gomap.NewStore(gomap.Options{Codec: encoding.JSON}),
+		lock:  new(sync.Mutex),
+	}
+	store := createStore(t, inner)
+	for _, stored := range []string{`{"a": "x"}`, `{"a":"<x>"}`} {
+		err := inner.SetRaw("foo", []byte(stored))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Patch("foo", []byte(`{"b": "y"}`))
+		if !errors.Is(err, jsonpatch.ErrNotCanonical) {
+			t.Errorf("Expected jsonpatch.ErrNotCanonical for %v, but was %v", stored, err)
+		}
+		data, _, err := inner.GetRaw("foo")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != stored {
+			t.Errorf("Expected the stored value to stay unchanged, but was %s", data)
+		}
+	}
+
+	// Without compare-and-swap the value is patched
+	store = createStore(t, inner.Store)
+	err := store.Patch("foo", []byte(`{"b": "y"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStored(t, store, "foo", map[string]any{"a": "<x>", "b": "y"})
+}
+
+// TestPatchConflict tests that Patch gives up when the value is changed concurrently in each attempt.
+func TestPatchConflict(t *testing.T) {
+	inner := conflictingCASStore{
+		Store: gomap.NewStore(gomap.Options{Codec: encoding.JSON}),
+	}
+	store := createStore(t, inner)
+	err := store.Set("foo", map[string]any{"a": "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Patch("foo", []byte(`{"b": "y"}`))
+	if !errors.Is(err, gokv.ErrUpdateConflict) {
+		t.Errorf("Expected gokv.ErrUpdateConflict, but was %v", err)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.Options{Codec: encoding.JSON}))
+	err := store.Patch("", []byte(`{"a": "1"}`))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Patch("foo", nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Patch("foo", []byte(`[{"op": "unknown"`))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store := jsonpatch.NewStore(gomap.NewStore(gomap.DefaultOptions))
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// casStore is a gokv.CASStore for testing, based on a gomap.Store.
+type casStore struct {
+	gomap.Store
+	lock *sync.Mutex
+}
+
+func (s casStore) Set(k string, v any) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.Store.Set(k, v)
+}
+
+func (s casStore) CompareAndSwap(k string, old, new any) (bool, error) {
+	oldData, err := encoding.JSON.Marshal(old)
+	if err != nil {
+		return false, err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	data, found, err := s.GetRaw(k)
+	if err != nil || !found || !bytes.Equal(data, oldData) {
+		return false, err
+	}
+	return true, s.Store.Set(k, new)
+}
+
+var _ gokv.CASStore = casStore{}
+
+// conflictingCASStore is a gokv.CASStore for testing that never swaps,
+// as if the value was always changed concurrently.
+type conflictingCASStore struct {
+	gomap.Store
+}
+
+func (s conflictingCASStore) CompareAndSwap(k string, old, new any) (bool, error) {
+	return false, nil
+}
+
+func assertStored(t *testing.T, store jsonpatch.Store, k string, expected map[string]any) {
+	t.Helper()
+	var actual map[string]any
+	found, err := store.Get(k, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if diff := deep.Equal(actual, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func createStore(t *testing.T, inner gokv.Store) jsonpatch.Store {
+	store := jsonpatch.NewStore(inner)
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return store
+}
@@ -0,0 +1,58 @@
+package gokv
+
+// Kind is the kind of backend of a store.
+// Its value is the name of the package that implements the store, so it can be used in log messages as well.
+type Kind string
+
+// The kinds of the store implementations in this repository.
+const (
+	KindUnknown      Kind = ""
+	KindBadgerDB     Kind = "badgerdb"
+	KindBbolt        Kind = "bbolt"
+	KindBigCache     Kind = "bigcache"
+	KindBlobStorage  Kind = "blobstorage"
+	KindCockroachDB  Kind = "cockroachdb"
+	KindConsul       Kind = "consul"
+	KindDatastore    Kind = "datastore"
+	KindDynamoDB     Kind = "dynamodb"
+	KindEtcd         Kind = "etcd"
+	KindFile         Kind = "file"
+	KindFreeCache    Kind = "freecache"
+	KindGCS          Kind = "gcs"
+	KindGoMap        Kind = "gomap"
+	KindHazelcast    Kind = "hazelcast"
+	KindIgnite       Kind = "ignite"
+	KindLevelDB      Kind = "leveldb"
+	KindLRUCache     Kind = "lrucache"
+	KindMemcached    Kind = "memcached"
+	KindMongoDB      Kind = "mongodb"
+	KindMySQL        Kind = "mysql"
+	KindNoop         Kind = "noop"
+	KindPostgreSQL   Kind = "postgresql"
+	KindRedis        Kind = "redis"
+	KindS3           Kind = "s3"
+	KindSyncMap      Kind = "syncmap"
+	KindTableStorage Kind = "tablestorage"
+	KindTableStore   Kind = "tablestore"
+	KindZooKeeper    Kind = "zookeeper"
+)
+
+// KindReporter is an optional interface for stores that report which kind of backend they are,
+// so that generic code can branch on it (for example to enable backend specific optimizations)
+// without type switches on the concrete types of many packages.
+// All store implementations in this repository implement it, but store wrappers don't.
+// Check for it with a type assertion or use the KindOf function.
+type KindReporter interface {
+	// Kind returns the kind of backend of the store.
+	Kind() Kind
+}
+
+// KindOf returns the kind of backend of the store if it implements the KindReporter interface.
+// Otherwise KindUnknown is returned.
+func KindOf(store Store) Kind {
+	reporter, ok := store.(KindReporter)
+	if !ok {
+		return KindUnknown
+	}
+	return reporter.Kind()
+}
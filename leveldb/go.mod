@@ -14,3 +14,9 @@ require (
 	github.com/go-test/deep v1.1.0 // indirect
 	github.com/golang/snappy v0.0.1 // indirect
 )
+
+replace (
+	github.com/philippgille/gokv => ../
+	github.com/philippgille/gokv/test => ../test
+	github.com/philippgille/gokv/util => ../util
+)
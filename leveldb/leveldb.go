@@ -3,16 +3,23 @@ package leveldb
 import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	leveldbutil "github.com/syndtr/goleveldb/leveldb/util"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
 // Store is a gokv.Store implementation for LevelDB.
 type Store struct {
-	db        *leveldb.DB
+	db *leveldb.DB
+	// True if the DB was passed via the options, so it's not closed by the store.
+	sharedDB  bool
+	keyPrefix string
 	writeSync bool
 	codec     encoding.Codec
+	// Compacts the DB when started via StartMaintenance.
+	maintenance *util.Maintenance
 }
 
 // Set stores the given value for the given key.
@@ -35,7 +42,7 @@ func (s Store) Set(k string, v any) error {
 			Sync: true,
 		}
 	}
-	return s.db.Put([]byte(k), data, writeOptions)
+	return s.db.Put([]byte(s.keyPrefix+k), data, writeOptions)
 }
 
 // Get retrieves the stored value for the given key.
@@ -49,7 +56,7 @@ func (s Store) Get(k string, v any) (found bool, err error) {
 		return false, err
 	}
 
-	data, err := s.db.Get([]byte(k), nil)
+	data, err := s.db.Get([]byte(s.keyPrefix+k), nil)
 	if err != nil {
 		if err == leveldb.ErrNotFound {
 			return false, nil
@@ -74,13 +81,54 @@ func (s Store) Delete(k string) error {
 			Sync: true,
 		}
 	}
-	return s.db.Delete([]byte(k), writeOptions)
+	return s.db.Delete([]byte(s.keyPrefix+k), writeOptions)
+}
+
+// ForEach calls fn for each key that starts with the given prefix.
+// The iteration works on an implicit snapshot, so fn can modify the store,
+// but the modifications aren't reflected in the iteration.
+// If fn returns an error, the iteration stops and the error is returned.
+func (s Store) ForEach(prefix string, fn func(k string) error) error {
+	iter := s.db.NewIterator(leveldbutil.BytesPrefix([]byte(s.keyPrefix+prefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		// Converting to a string copies the key, which is only valid until the next call of Next()
+		if err := fn(string(iter.Key()[len(s.keyPrefix):])); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// Kind returns gokv.KindLevelDB.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindLevelDB
+}
+
+// Sync flushes all writes that were done so far to disk, including the ones that were done without WriteSync.
+// goleveldb doesn't have a separate sync operation, but opening a transaction flushes the in-memory table
+// into a table file, which is synced to disk, so the writes don't depend on the journal anymore.
+// The transaction is discarded right away, so Sync doesn't write anything itself.
+// Each Sync with pending writes creates a small table file, which LevelDB merges in its regular compactions.
+func (s Store) Sync() error {
+	tr, err := s.db.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	tr.Discard()
+	return nil
 }
 
 // Close closes the store.
 // It must be called to releases any outstanding snapshots,
 // abort any in-flight compactions and discard open transactions.
+// If the DB was passed via the options, it's not closed, because other stores might still use it.
+// A maintenance that was started via StartMaintenance is stopped.
 func (s Store) Close() error {
+	s.maintenance.Stop()
+	if s.sharedDB {
+		return nil
+	}
 	return s.db.Close()
 }
 
@@ -95,17 +143,27 @@ type Options struct {
 	// Set() and Delete() are both writes.
 	// Optional (false by default).
 	WriteSync bool
+	// Prefix that's added to all keys in the DB, which makes the store a logical namespace,
+	// for example when multiple stores share one DB.
+	// Optional ("" by default).
+	KeyPrefix string
+	// An already opened DB, for example to share one DB between multiple stores with different KeyPrefix values,
+	// instead of opening many DBs.
+	// The store doesn't close the DB in this case, that's up to the caller.
+	// Path is ignored if DB is set.
+	// Optional (nil by default).
+	DB *leveldb.DB
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// Path: "leveldb", WriteSync: false, Codec: encoding.JSON
+// Path: "leveldb", WriteSync: false, KeyPrefix: "", DB: nil, Codec: encoding.JSON
 var DefaultOptions = Options{
 	Path:  "leveldb",
 	Codec: encoding.JSON,
-	// No need to set WriteSync because its zero values is fine.
+	// No need to set WriteSync, KeyPrefix or DB because their zero values are fine.
 }
 
 // NewStore creates a new LevelDB store.
@@ -123,14 +181,21 @@ func NewStore(options Options) (Store, error) {
 	}
 
 	// Open DB
-	db, err := leveldb.OpenFile(options.Path, nil)
-	if err != nil {
-		return result, err
+	db := options.DB
+	if db == nil {
+		var err error
+		db, err = leveldb.OpenFile(options.Path, nil)
+		if err != nil {
+			return result, err
+		}
 	}
 
 	result.db = db
+	result.sharedDB = options.DB != nil
+	result.keyPrefix = options.KeyPrefix
 	result.writeSync = options.WriteSync
 	result.codec = options.Codec
+	result.maintenance = util.NewMaintenance()
 
 	return result, nil
 }
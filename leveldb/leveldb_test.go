@@ -1,10 +1,16 @@
 package leveldb_test
 
 import (
+	"errors"
 	"io/ioutil"
 	"log"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	goleveldb "github.com/syndtr/goleveldb/leveldb"
 
 	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
@@ -47,6 +53,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store, path := createStore(t, encoding.JSON)
+		defer cleanUp(store, path)
+		test.TestEmptyValues(store, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		store, path := createStore(t, test.BytesCodec{})
+		defer cleanUp(store, path)
+		test.TestEmptyValues(store, t)
+	})
+}
+
 // TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
 // The store works with a single file, so everything should be locked properly.
 // The locking is implemented in the leveldb package, but test it nonetheless.
@@ -59,6 +82,153 @@ func TestStoreConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, store)
 }
 
+// TestIterator tests if listing the keys works properly.
+func TestIterator(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+	test.TestIterator(store, t)
+}
+
+// TestSync tests if syncing works with the default asynchronous writes.
+func TestSync(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+	test.TestSyncer(store, t)
+}
+
+// TestSyncDurability tests that after Sync the writes are in the synced table files,
+// so they don't depend on the journal, which isn't synced with the default asynchronous writes.
+// The DB files without the journal are what remains of an unsynced journal after a crash in the worst case.
+func TestSyncDurability(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+
+	err := store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Only in the journal so far
+	if found := getFromCopyWithoutJournal(t, path, "foo"); found {
+		t.Fatal("Expected the value to only be in the journal before the sync")
+	}
+
+	err = store.Sync()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found := getFromCopyWithoutJournal(t, path, "foo"); !found {
+		t.Error("Expected the value to be in the table files after the sync")
+	}
+}
+
+// getFromCopyWithoutJournal copies the DB files except for the journal and the lock file,
+// opens the copy and checks if a value exists for the given key.
+func getFromCopyWithoutJournal(t *testing.T, path, k string) bool {
+	t.Helper()
+
+	copyPath := t.TempDir()
+	dirEntries, err := os.ReadDir(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, dirEntry := range dirEntries {
+		if strings.HasSuffix(dirEntry.Name(), ".log") || dirEntry.Name() == "LOCK" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(path, dirEntry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = os.WriteFile(filepath.Join(copyPath, dirEntry.Name()), data, 0o600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := leveldb.NewStore(leveldb.Options{Path: copyPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	found, err := store.Get(k, new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return found
+}
+
+// TestSharedDB tests that two stores with different key prefixes can share one DB,
+// that they're isolated from each other and that they work concurrently.
+func TestSharedDB(t *testing.T) {
+	path := generateRandomTempDbPath(t)
+	defer os.RemoveAll(path)
+	db, err := goleveldb.OpenFile(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	storeA := createSharedStore(t, db, "a:")
+	storeB := createSharedStore(t, db, "b:")
+
+	err = storeA.Set("foo", test.Foo{Bar: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = storeB.Set("foo", test.Foo{Bar: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for expected, store := range map[string]gokv.Store{"a": storeA, "b": storeB} {
+		actual := test.Foo{}
+		found, err := store.Get("foo", &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found || actual.Bar != expected {
+			t.Errorf("Expected %q to be found, but found was %v and the value %q", expected, found, actual.Bar)
+		}
+		keys, err := gokv.Keys(store, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != 1 || keys[0] != "foo" {
+			t.Errorf("Expected only the store's own key, but got %v", keys)
+		}
+	}
+	err = storeA.Delete("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := storeB.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("Deleting the key in one store deleted it in the other store")
+	}
+
+	t.Run("concurrent", func(t *testing.T) {
+		t.Run("a", func(t *testing.T) {
+			t.Parallel()
+			test.TestConcurrentInteractions(t, 500, storeA)
+		})
+		t.Run("b", func(t *testing.T) {
+			t.Parallel()
+			test.TestConcurrentInteractions(t, 500, storeB)
+		})
+	})
+
+	// Closing a store doesn't close the shared DB
+	err = storeA.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = storeB.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -135,6 +305,53 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestMaintenance tests that the maintenance runs after the interval and stops when the store is closed.
+func TestMaintenance(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer os.RemoveAll(path)
+	err := store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.StartMaintenance(0)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.StartMaintenance(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.StartMaintenance(10 * time.Millisecond)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	for i := 0; i < 100 && store.MaintenanceStats().Runs == 0; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	stats := store.MaintenanceStats()
+	if stats.Runs == 0 {
+		t.Fatal("Expected at least one maintenance run")
+	}
+	if stats.LastErr != nil {
+		t.Error(stats.LastErr)
+	}
+	if stats.LastRun.IsZero() {
+		t.Error("Expected the time of the last run to be set")
+	}
+
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	runs := store.MaintenanceStats().Runs
+	time.Sleep(50 * time.Millisecond)
+	if store.MaintenanceStats().Runs != runs {
+		t.Error("Expected no maintenance runs after closing the store")
+	}
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	store, path := createStore(t, encoding.JSON)
@@ -209,6 +426,71 @@ func TestSyncWrite(t *testing.T) {
 	}
 }
 
+// TestSnapshot tests that a snapshot isn't affected by later writes and that it's read-only.
+func TestSnapshot(t *testing.T) {
+	store, path := createStore(t, encoding.JSON)
+	defer cleanUp(store, path)
+
+	err := store.Set("foo", test.Foo{Bar: "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot, err := store.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("foo", test.Foo{Bar: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("bar", test.Foo{Bar: "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := test.Foo{}
+	found, err := snapshot.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "a" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "a"}, found, actual)
+	}
+	found, err = snapshot.Get("bar", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+	keys, err := gokv.Keys(snapshot, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(keys) != 1 || keys[0] != "foo" {
+		t.Errorf("Expected only the key that existed when the snapshot was taken, but got %v", keys)
+	}
+
+	err = snapshot.Set("foo", test.Foo{Bar: "c"})
+	if !errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected gokv.ErrReadOnly, but was %v", err)
+	}
+	err = snapshot.Delete("foo")
+	if !errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected gokv.ErrReadOnly, but was %v", err)
+	}
+
+	// Closing the snapshot releases it, but doesn't close the store
+	err = snapshot.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Get("foo", &actual)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
 func createStore(t *testing.T, codec encoding.Codec) (leveldb.Store, string) {
 	path := generateRandomTempDbPath(t)
 	options := leveldb.Options{
@@ -222,6 +504,18 @@ func createStore(t *testing.T, codec encoding.Codec) (leveldb.Store, string) {
 	return store, path
 }
 
+func createSharedStore(t *testing.T, db *goleveldb.DB, keyPrefix string) leveldb.Store {
+	options := leveldb.Options{
+		KeyPrefix: keyPrefix,
+		DB:        db,
+	}
+	store, err := leveldb.NewStore(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
 func generateRandomTempDbPath(t *testing.T) string {
 	path, err := ioutil.TempDir(os.TempDir(), "leveldb")
 	if err != nil {
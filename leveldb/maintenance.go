@@ -0,0 +1,29 @@
+package leveldb
+
+import (
+	"time"
+
+	leveldbutil "github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// StartMaintenance starts a goroutine that compacts the key range of the store after each interval,
+// until Close is called.
+// LevelDB compacts automatically, but a manual compaction reclaims the disk space of deleted
+// and overwritten values sooner and can speed up reads after many writes.
+// With a KeyPrefix only the keys with that prefix are compacted.
+// Use MaintenanceStats for information about the runs.
+// It returns an error if the interval isn't positive or if the maintenance is already running.
+func (s Store) StartMaintenance(interval time.Duration) error {
+	return s.maintenance.Start(interval, s.compact)
+}
+
+// MaintenanceStats returns statistics about the maintenance runs so far.
+func (s Store) MaintenanceStats() util.MaintenanceStats {
+	return s.maintenance.Stats()
+}
+
+func (s Store) compact() error {
+	return s.db.CompactRange(*leveldbutil.BytesPrefix([]byte(s.keyPrefix)))
+}
@@ -0,0 +1,98 @@
+package leveldb
+
+import (
+	"github.com/syndtr/goleveldb/leveldb"
+	leveldbutil "github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// snapshot is a read-only gokv.Store implementation for a LevelDB snapshot.
+// Reads from it aren't affected by writes to the store after the snapshot was taken,
+// so multiple reads see a consistent state.
+type snapshot struct {
+	snap      *leveldb.Snapshot
+	keyPrefix string
+	codec     encoding.Codec
+}
+
+// Snapshot takes a snapshot of the current state of the DB and returns it as read-only gokv.Store,
+// whose Set and Delete return gokv.ErrReadOnly. It also implements gokv.Iterator.
+// It's limited to the KeyPrefix of the store, like the store itself.
+//
+// You must call the Close() method on the snapshot when you're done working with it,
+// because LevelDB keeps the data that the snapshot refers to until then.
+func (s Store) Snapshot() (gokv.Store, error) {
+	snap, err := s.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot{
+		snap:      snap,
+		keyPrefix: s.keyPrefix,
+		codec:     s.codec,
+	}, nil
+}
+
+// Set always returns gokv.ErrReadOnly unless the key or value are invalid.
+func (s snapshot) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	return gokv.ErrReadOnly
+}
+
+// Get retrieves the value that was stored for the given key when the snapshot was taken.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s snapshot) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	data, err := s.snap.Get([]byte(s.keyPrefix+k), nil)
+	if err != nil {
+		if err == leveldb.ErrNotFound {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, s.codec.Unmarshal(data, v)
+}
+
+// Delete always returns gokv.ErrReadOnly unless the key is invalid.
+func (s snapshot) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	return gokv.ErrReadOnly
+}
+
+// ForEach calls fn for each key that starts with the given prefix and existed when the snapshot was taken.
+// If fn returns an error, the iteration stops and the error is returned.
+func (s snapshot) ForEach(prefix string, fn func(k string) error) error {
+	iter := s.snap.NewIterator(leveldbutil.BytesPrefix([]byte(s.keyPrefix+prefix)), nil)
+	defer iter.Release()
+	for iter.Next() {
+		// Converting to a string copies the key, which is only valid until the next call of Next()
+		if err := fn(string(iter.Key()[len(s.keyPrefix):])); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// Close releases the snapshot, which must not be used afterwards.
+// The store that the snapshot was taken from isn't closed.
+func (s snapshot) Close() error {
+	s.snap.Release()
+	return nil
+}
@@ -0,0 +1,132 @@
+package gokv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrLockNotSupported is returned by AcquireLock when the store doesn't implement the Locker interface.
+var ErrLockNotSupported = errors.New("The store doesn't support locks")
+
+// ErrLockHeld is returned by Locker.AcquireLock when the lock is currently held by someone else.
+var ErrLockHeld = errors.New("The lock is held by someone else")
+
+// ErrLockLost is returned by Lock.Refresh and Lock.Release when the lock
+// expired or was acquired by someone else in the meantime.
+var ErrLockLost = errors.New("The lock expired or is held by someone else")
+
+// Locker is an optional interface for stores that can be used for distributed locks,
+// for example for mutual exclusion or leader election among multiple processes.
+// Check for it with a type assertion or use the AcquireLock function.
+type Locker interface {
+	// AcquireLock acquires the lock with the given key.
+	// It doesn't block. If the lock is currently held by someone else, ErrLockHeld is returned.
+	// The lock automatically expires after the TTL, unless it's refreshed before,
+	// so a crashed process doesn't hold the lock forever.
+	// The TTL must be positive. Depending on the implementation it can be rounded up to full seconds.
+	// The lock key should not be used for regular values.
+	AcquireLock(key string, ttl time.Duration) (Lock, error)
+}
+
+// Lock is a lock that was acquired via Locker.AcquireLock.
+type Lock interface {
+	// Release releases the lock, so that someone else can acquire it.
+	// If the lock expired in the meantime, ErrLockLost is returned.
+	Release() error
+	// Refresh extends the lock's expiry to the given TTL from now.
+	// If the lock expired in the meantime, ErrLockLost is returned.
+	// The TTL must be positive.
+	Refresh(ttl time.Duration) error
+}
+
+// AcquireLock acquires the lock with the given key via the store if it implements the Locker interface.
+// Otherwise ErrLockNotSupported is returned.
+func AcquireLock(store Store, key string, ttl time.Duration) (Lock, error) {
+	locker, ok := store.(Locker)
+	if !ok {
+		return nil, ErrLockNotSupported
+	}
+	return locker.AcquireLock(key, ttl)
+}
+
+// WithLock acquires the lock with the given key, runs fn and releases the lock afterwards,
+// also when fn panics.
+// It doesn't block. If the lock is currently held by someone else, ErrLockHeld is returned and fn isn't run.
+// While fn runs, the lock is refreshed in the background every third of the TTL,
+// so fn can take longer than the TTL without losing the lock,
+// while a crashed process still only holds the lock until the TTL expires.
+// A failed refresh is retried with the next one, as long as the lock hasn't expired until then.
+// If the lock gets lost anyway (for example due to network issues), the context that's passed to fn is canceled,
+// so fn can stop working on what the lock protects, and ErrLockLost is returned after fn finished.
+// The error of fn takes precedence over the errors of refreshing and releasing the lock.
+func WithLock(locker Locker, key string, ttl time.Duration, fn func(ctx context.Context) error) (err error) {
+	if ttl <= 0 {
+		return errors.New("The TTL must be positive")
+	}
+	interval := ttl / 3
+	if interval <= 0 {
+		interval = ttl
+	}
+
+	// The lock expires at the latest one TTL after the start of the last successful acquisition or refresh.
+	refreshed := time.Now()
+	lock, err := locker.AcquireLock(key, ttl)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	var refreshErr error
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				start := time.Now()
+				err := lock.Refresh(ttl)
+				if err == nil {
+					refreshed = start
+					continue
+				}
+				// Other errors can be transient, so the next refresh is tried if it's still in time.
+				if !errors.Is(err, ErrLockLost) {
+					if time.Now().Add(interval).Before(refreshed.Add(ttl)) {
+						continue
+					}
+					err = fmt.Errorf("%w: %v", ErrLockLost, err)
+				}
+				refreshErr = err
+				cancel(err)
+				return
+			}
+		}
+	}()
+
+	defer func() {
+		// Stop refreshing before releasing, so a refresh can't race with the release.
+		close(stop)
+		wg.Wait()
+		releaseErr := lock.Release()
+		if err != nil {
+			return
+		}
+		if refreshErr != nil {
+			err = refreshErr
+		} else {
+			err = releaseErr
+		}
+	}()
+
+	return fn(ctx)
+}
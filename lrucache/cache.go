@@ -0,0 +1,192 @@
+package lrucache
+
+import (
+	"container/heap"
+	"time"
+)
+
+// entry is a key-value pair in the cache.
+// It's an element of the LRU list and, if it has an expiry, of the expiry heap.
+type entry struct {
+	key  string
+	data []byte
+	// Zero if the entry doesn't expire.
+	expiry time.Time
+	// Neighbors in the LRU list, with prev pointing towards the most recently used entry.
+	prev, next *entry
+	// Index in the expiry heap, -1 if the entry isn't in the heap.
+	heapIndex int
+}
+
+// expired returns whether the entry's expiry is at or before now.
+func (e *entry) expired(now time.Time) bool {
+	return !e.expiry.IsZero() && !now.Before(e.expiry)
+}
+
+// cache is the data structure of the store:
+// a map for the lookup, an intrusive doubly linked list for the LRU order
+// and a min-heap of the entries with an expiry, ordered by the expiry.
+// It's not safe for concurrent use, the store holds a lock.
+type cache struct {
+	entries map[string]*entry
+	// Most and least recently used entry.
+	head, tail *entry
+	expiries   expiryHeap
+	maxEntries int
+}
+
+func newCache(maxEntries int) *cache {
+	return &cache{
+		entries:    make(map[string]*entry),
+		maxEntries: maxEntries,
+	}
+}
+
+// get returns the entry for the key and marks it as the most recently used one.
+// An expired entry is removed and not returned.
+func (c *cache) get(k string, now time.Time) (*entry, bool) {
+	e, ok := c.entries[k]
+	if !ok {
+		return nil, false
+	}
+	if e.expired(now) {
+		c.remove(e)
+		return nil, false
+	}
+	c.moveToFront(e)
+	return e, true
+}
+
+// set stores the data for the key with the given expiry (zero for none)
+// and marks it as the most recently used entry.
+// If that exceeds the maximum number of entries, expired entries are removed first
+// and then the least recently used entries.
+func (c *cache) set(k string, data []byte, expiry time.Time, now time.Time) {
+	e, ok := c.entries[k]
+	if ok {
+		e.data = data
+		c.setExpiry(e, expiry)
+		c.moveToFront(e)
+		return
+	}
+
+	e = &entry{
+		key:       k,
+		data:      data,
+		heapIndex: -1,
+	}
+	c.entries[k] = e
+	c.pushFront(e)
+	c.setExpiry(e, expiry)
+
+	if len(c.entries) > c.maxEntries {
+		c.removeExpired(now)
+	}
+	for len(c.entries) > c.maxEntries {
+		c.remove(c.tail)
+	}
+}
+
+// delete removes the entry for the key if it exists.
+func (c *cache) delete(k string) {
+	if e, ok := c.entries[k]; ok {
+		c.remove(e)
+	}
+}
+
+// clear removes all entries.
+func (c *cache) clear() {
+	c.entries = make(map[string]*entry)
+	c.head, c.tail = nil, nil
+	c.expiries = nil
+}
+
+// removeExpired removes all entries whose expiry is at or before now.
+func (c *cache) removeExpired(now time.Time) {
+	for len(c.expiries) > 0 && c.expiries[0].expired(now) {
+		c.remove(c.expiries[0])
+	}
+}
+
+func (c *cache) setExpiry(e *entry, expiry time.Time) {
+	e.expiry = expiry
+	switch {
+	case expiry.IsZero() && e.heapIndex >= 0:
+		heap.Remove(&c.expiries, e.heapIndex)
+	case !expiry.IsZero() && e.heapIndex >= 0:
+		heap.Fix(&c.expiries, e.heapIndex)
+	case !expiry.IsZero():
+		heap.Push(&c.expiries, e)
+	}
+}
+
+func (c *cache) remove(e *entry) {
+	delete(c.entries, e.key)
+	c.unlink(e)
+	if e.heapIndex >= 0 {
+		heap.Remove(&c.expiries, e.heapIndex)
+	}
+}
+
+func (c *cache) pushFront(e *entry) {
+	e.prev = nil
+	e.next = c.head
+	if c.head != nil {
+		c.head.prev = e
+	}
+	c.head = e
+	if c.tail == nil {
+		c.tail = e
+	}
+}
+
+func (c *cache) unlink(e *entry) {
+	if e.prev != nil {
+		e.prev.next = e.next
+	} else {
+		c.head = e.next
+	}
+	if e.next != nil {
+		e.next.prev = e.prev
+	} else {
+		c.tail = e.prev
+	}
+	e.prev, e.next = nil, nil
+}
+
+func (c *cache) moveToFront(e *entry) {
+	if c.head == e {
+		return
+	}
+	c.unlink(e)
+	c.pushFront(e)
+}
+
+// expiryHeap implements heap.Interface for entries, ordered by their expiry.
+type expiryHeap []*entry
+
+func (h expiryHeap) Len() int { return len(h) }
+
+func (h expiryHeap) Less(i, j int) bool { return h[i].expiry.Before(h[j].expiry) }
+
+func (h expiryHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+
+func (h *expiryHeap) Push(x any) {
+	e := x.(*entry)
+	e.heapIndex = len(*h)
+	*h = append(*h, e)
+}
+
+func (h *expiryHeap) Pop() any {
+	old := *h
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.heapIndex = -1
+	*h = old[:n-1]
+	return e
+}
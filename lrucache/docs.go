@@ -0,0 +1,10 @@
+/*
+Package lrucache contains an implementation of the `gokv.Store` interface for an in-memory cache
+with a maximum number of entries and an optional expiry per entry.
+
+When the cache is full, expired entries are removed first,
+and only if there are none, the least recently used entry is evicted.
+Expired entries are removed when they're accessed or when space is needed,
+and optionally by a background sweep (see Options.SweepInterval).
+*/
+package lrucache
@@ -0,0 +1,230 @@
+package lrucache
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+var defaultMaxEntries = 10000
+
+// Store is a gokv.Store implementation for an in-memory cache
+// with LRU eviction and an optional expiry per entry.
+type Store struct {
+	lock       *sync.Mutex
+	c          *cache
+	defaultTTL time.Duration
+	codec      encoding.Codec
+	// Only set when there's a background sweep.
+	done      chan struct{}
+	closeOnce *sync.Once
+}
+
+// Set stores the given value for the given key.
+// It expires after the DefaultTTL from the options, if one is set.
+// Values are automatically marshalled to JSON or gob (depending on the configuration).
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var expiry time.Time
+	if s.defaultTTL > 0 {
+		expiry = now.Add(s.defaultTTL)
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.c.set(k, data, expiry, now)
+	return nil
+}
+
+// SetWithTTL stores the given value for the given key, like Set,
+// but the key-value pair expires after the given TTL instead of the DefaultTTL.
+// After that, Get returns (false, nil) for the key.
+// The key must not be "", the value must not be nil and the TTL must be positive.
+func (s Store) SetWithTTL(k string, v any, ttl time.Duration) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.c.set(k, data, now.Add(ttl), now)
+	return nil
+}
+
+// Get retrieves the stored value for the given key and marks it as the most recently used one.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If no value is found or it expired it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	s.lock.Lock()
+	e, found := s.c.get(k, time.Now())
+	var data []byte
+	if found {
+		data = e.data
+	}
+	// Unlock before unmarshalling, which can take some time.
+	// The data isn't modified in place, so it can be read without the lock.
+	s.lock.Unlock()
+	if !found {
+		return false, nil
+	}
+
+	return true, s.codec.Unmarshal(data, v)
+}
+
+// Delete deletes the stored value for the given key.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.c.delete(k)
+	return nil
+}
+
+// Len returns the number of entries, including expired entries that weren't removed yet.
+// It's not part of the gokv.Store interface, but specific to this store.
+func (s Store) Len() int {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return len(s.c.entries)
+}
+
+// Kind returns gokv.KindLRUCache.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindLRUCache
+}
+
+// Close stops the background sweep, if there is one, and removes all entries.
+// Calling Close multiple times is safe.
+func (s Store) Close() error {
+	s.closeOnce.Do(func() {
+		if s.done != nil {
+			close(s.done)
+		}
+	})
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.c.clear()
+	return nil
+}
+
+// sweep removes expired entries in the given interval until the store is closed.
+func (s Store) sweep(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.done:
+			return
+		case now := <-ticker.C:
+			s.lock.Lock()
+			s.c.removeExpired(now)
+			s.lock.Unlock()
+		}
+	}
+}
+
+// Options are the options for the LRU cache store.
+type Options struct {
+	// Maximum number of entries.
+	// When a Set exceeds it, expired entries are removed first
+	// and if there are none, the least recently used entry is evicted.
+	// Set and Get count as use.
+	// Optional (10000 by default).
+	MaxEntries int
+	// TTL for values that are stored with Set.
+	// SetWithTTL overrides it.
+	// Optional (0 by default, which means values that are stored with Set don't expire).
+	DefaultTTL time.Duration
+	// Interval in which expired entries are removed in the background.
+	// Without it, expired entries are only removed when they're accessed or when space is needed,
+	// so their memory isn't freed before that.
+	// Optional (0 by default, which means there's no background sweep).
+	SweepInterval time.Duration
+	// Encoding format.
+	// Optional (encoding.JSON by default).
+	Codec encoding.Codec
+}
+
+// DefaultOptions is an Options object with default values.
+// MaxEntries: 10000, DefaultTTL: 0, SweepInterval: 0, Codec: encoding.JSON
+var DefaultOptions = Options{
+	MaxEntries: defaultMaxEntries,
+	Codec:      encoding.JSON,
+	// No need to set DefaultTTL or SweepInterval because their zero values are fine.
+}
+
+// NewStore creates a new LRU cache store.
+//
+// You must call the Close() method on the store when you're done working with it,
+// at least when SweepInterval is set.
+func NewStore(options Options) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if options.MaxEntries < 0 {
+		return result, errors.New("The MaxEntries must not be negative")
+	}
+	if options.DefaultTTL < 0 {
+		return result, errors.New("The DefaultTTL must not be negative")
+	}
+	if options.SweepInterval < 0 {
+		return result, errors.New("The SweepInterval must not be negative")
+	}
+
+	// Set default values
+	if options.MaxEntries == 0 {
+		options.MaxEntries = DefaultOptions.MaxEntries
+	}
+	if options.Codec == nil {
+		options.Codec = DefaultOptions.Codec
+	}
+
+	result.lock = new(sync.Mutex)
+	result.c = newCache(options.MaxEntries)
+	result.defaultTTL = options.DefaultTTL
+	result.codec = options.Codec
+	result.closeOnce = &sync.Once{}
+
+	if options.SweepInterval > 0 {
+		result.done = make(chan struct{})
+		go result.sweep(options.SweepInterval)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,242 @@
+package lrucache_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/lrucache"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestStore(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, lrucache.Options{Codec: encoding.JSON})
+		test.TestStore(store, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, lrucache.Options{Codec: encoding.Gob})
+		test.TestStore(store, t)
+	})
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, lrucache.Options{Codec: encoding.JSON})
+		test.TestTypes(store, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, lrucache.Options{Codec: encoding.Gob})
+		test.TestTypes(store, t)
+	})
+}
+
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	store := createStore(t, lrucache.DefaultOptions)
+	test.TestEmptyValues(store, t)
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	store := createStore(t, lrucache.DefaultOptions)
+
+	goroutineCount := 1000
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+}
+
+// TestTTL tests if key-value pairs expire after their TTL.
+func TestTTL(t *testing.T) {
+	store := createStore(t, lrucache.DefaultOptions)
+	test.TestTTLStore(store, t)
+}
+
+// TestEviction tests that the least recently used entries are evicted when the cache is full.
+func TestEviction(t *testing.T) {
+	store := createStore(t, lrucache.Options{MaxEntries: 3})
+
+	for _, k := range []string{"1", "2", "3", "4", "5"} {
+		err := store.Set(k, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expectKeys(t, store, []string{"3", "4", "5"}, []string{"1", "2"})
+	if store.Len() != 3 {
+		t.Errorf("Expected 3 entries, but was %v", store.Len())
+	}
+
+	// Get counts as use, so after reading "3" (and "4" and "5" above), "4" is the least recently used entry
+	_, err := store.Get("3", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("6", "6")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, store, []string{"3", "5", "6"}, []string{"4"})
+}
+
+// TestDefaultTTL tests that values stored with Set expire after the DefaultTTL
+// and that SetWithTTL overrides it.
+func TestDefaultTTL(t *testing.T) {
+	store := createStore(t, lrucache.Options{DefaultTTL: 100 * time.Millisecond})
+
+	err := store.Set("short", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.SetWithTTL("long", "value", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(150 * time.Millisecond)
+	expectKeys(t, store, []string{"long"}, []string{"short"})
+}
+
+// TestExpiredEntryFreesSlot tests that an expired entry is removed to make room
+// instead of evicting the least recently used entry that's still valid.
+func TestExpiredEntryFreesSlot(t *testing.T) {
+	store := createStore(t, lrucache.Options{MaxEntries: 2})
+
+	// "lru" is the least recently used entry, but it doesn't expire
+	err := store.Set("lru", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.SetWithTTL("expiring", "value", 50*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	err = store.Set("new", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, store, []string{"lru", "new"}, []string{"expiring"})
+
+	// Without expired entries, the least recently used entry is evicted again
+	err = store.Set("newer", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectKeys(t, store, []string{"new", "newer"}, []string{"lru"})
+}
+
+// TestSweep tests that expired entries are removed in the background.
+func TestSweep(t *testing.T) {
+	store := createStore(t, lrucache.Options{SweepInterval: 20 * time.Millisecond})
+	defer store.Close()
+
+	err := store.SetWithTTL("foo", "bar", 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("baz", "qux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	// Len doesn't remove expired entries itself
+	if store.Len() != 1 {
+		t.Errorf("Expected 1 entry after the sweep, but was %v", store.Len())
+	}
+}
+
+// TestKind tests if the store reports gokv.KindLRUCache via the gokv.KindReporter interface.
+func TestKind(t *testing.T) {
+	store := createStore(t, lrucache.DefaultOptions)
+	var s gokv.Store = store
+	if kind := gokv.KindOf(s); kind != gokv.KindLRUCache {
+		t.Errorf("Expected %q, but was %q", gokv.KindLRUCache, kind)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	// Test empty key
+	store := createStore(t, lrucache.DefaultOptions)
+	err := store.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Test invalid options
+	for _, options := range []lrucache.Options{
+		{MaxEntries: -1},
+		{DefaultTTL: -time.Second},
+		{SweepInterval: -time.Second},
+	} {
+		_, err = lrucache.NewStore(options)
+		if err == nil {
+			t.Errorf("Expected an error for the options %+v", options)
+		}
+	}
+}
+
+// TestClose tests if the close method returns any errors, also when it's called multiple times.
+func TestClose(t *testing.T) {
+	store := createStore(t, lrucache.Options{SweepInterval: time.Second})
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+
+	// For example with `defer store.Close()` and an explicit call
+	err = store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func expectKeys(t *testing.T, store lrucache.Store, present, absent []string) {
+	t.Helper()
+
+	for _, k := range present {
+		found, err := store.Get(k, new(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Errorf("Expected key %v to be present, but it was evicted or expired", k)
+		}
+	}
+	for _, k := range absent {
+		found, err := store.Get(k, new(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Errorf("Expected key %v to be evicted or expired, but it was found", k)
+		}
+	}
+}
+
+func createStore(t *testing.T, options lrucache.Options) lrucache.Store {
+	store, err := lrucache.NewStore(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
@@ -19,11 +19,16 @@ func testImpl(impl string) (err error) {
 	// Implementations that don't require a separate service
 
 	switch impl {
-	case "badgerdb", "bbolt", "bigcache", "file", "freecache", "gomap", "leveldb", "syncmap", "noop":
+	case "audit", "badgerdb", "bbolt", "bigcache", "cascontent", "codecfallback", "defaults", "encoding", "encoding/cbor", "encoding/compressed", "encoding/msgpack", "encrypt", "file", "freecache", "gomap", "jsonpatch", "leveldb", "lrucache", "merge", "migrate", "observe", "readonly", "recover", "replay", "retry", "rw", "syncmap", "wal", "noop":
+		var rootDir string
+		if rootDir, err = os.Getwd(); err != nil {
+			return err
+		}
 		if err = os.Chdir("./" + impl); err != nil {
 			return err
 		}
-		defer os.Chdir("..") // This swallows the error in case there is one, but that's okay as the mage process is exited anyway
+		// Not ".." because modules like "encoding/cbor" are nested.
+		defer os.Chdir(rootDir) // This swallows the error in case there is one, but that's okay as the mage process is exited anyway
 
 		var out string
 		out, err = script.Exec("go test -v -race -coverprofile=coverage.txt -covermode=atomic").String()
@@ -39,6 +44,17 @@ func testImpl(impl string) (err error) {
 	var setup func() error
 	// TODO: Check quoting on Windows
 	switch impl {
+	case "blobstorage": // Blob Storage via Azurite
+		dockerImage = "mcr.microsoft.com/azure-storage/azurite"
+		dockerCmd += `blobstorage -p 10000:10000 ` + dockerImage + ` azurite-blob --blobHost 0.0.0.0`
+		setup = func() error {
+			// The tests only run when a connection string is set.
+			// This is the well-known connection string for the Azurite emulator.
+			if _, found := os.LookupEnv("BLOB_STORAGE_CONNECTION_STRING"); !found {
+				return os.Setenv("BLOB_STORAGE_CONNECTION_STRING", "DefaultEndpointsProtocol=http;AccountName=devstoreaccount1;AccountKey=Eby8vdM02xNOcqFlqUwJPLlmEtlCDXJ1OUzFT50uSRZ6IFsuFq2UVErCz4I6tq/K1SZFPTOtr/KBHBeksoGMGw==;BlobEndpoint=http://127.0.0.1:10000/devstoreaccount1;")
+			}
+			return nil
+		}
 	case "cockroachdb":
 		dockerImage = "cockroachdb/cockroach"
 		dockerCmd += `cockroachdb -p 26257:26257 --health-cmd='curl -f http://localhost:8080/health?ready=1' --health-interval 1s ` + dockerImage + ` start-single-node --insecure`
@@ -68,6 +84,10 @@ func testImpl(impl string) (err error) {
 	case "etcd":
 		dockerImage = "bitnami/etcd"
 		dockerCmd += `etcd -p 2379:2379 --env ALLOW_NONE_AUTHENTICATION=yes --health-cmd='etcdctl endpoint health' --health-interval 1s ` + dockerImage
+	case "gcs": // Google Cloud Storage via "fake-gcs-server"
+		// The tests connect to it via the STORAGE_EMULATOR_HOST environment variable, which they set themselves.
+		dockerImage = "fsouza/fake-gcs-server"
+		dockerCmd += `gcs -p 4443:4443 ` + dockerImage + ` -scheme http -port 4443`
 	case "hazelcast":
 		dockerImage = "hazelcast/hazelcast"
 		dockerCmd += `hazelcast -p 5701:5701 --health-cmd='curl -f http://localhost:5701/hazelcast/health/node-state' --health-interval 1s ` + dockerImage
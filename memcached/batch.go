@@ -0,0 +1,91 @@
+package memcached
+
+import (
+	"github.com/bradfitz/gomemcache/memcache"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// SetMany stores the given values for the given keys.
+// Memcached doesn't have a command for storing multiple items,
+// so there's one request per key-value pair.
+// The operation is not atomic.
+// The keys must not be "" and the values must not be nil.
+func (c Client) SetMany(values map[string]any) error {
+	if err := util.CheckKeysAndValues(values); err != nil {
+		return err
+	}
+
+	for k, v := range values {
+		data, err := c.codec.Marshal(v)
+		if err != nil {
+			return err
+		}
+		item := memcache.Item{
+			Key:   c.key(k),
+			Value: data,
+		}
+		if err = c.c.Set(&item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMany retrieves the stored values for the given keys.
+// It uses Memcached's multi-get, so it's only one round trip per server.
+// values must be a slice with the same length as keys, for example a []any or []*Foo,
+// with each element being a non-nil pointer that the value of the key at the same index is unmarshalled into.
+// found contains whether a value was found for the key at the same index.
+// The keys must not be "".
+func (c Client) GetMany(keys []string, values any) (found []bool, err error) {
+	pointers, err := util.BatchPointers(keys, values)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []bool{}, nil
+	}
+
+	memcachedKeys := make([]string, len(keys))
+	for i, k := range keys {
+		memcachedKeys[i] = c.key(k)
+	}
+	// Missing keys are just not contained in the result
+	items, err := c.c.GetMulti(memcachedKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	found = make([]bool, len(keys))
+	for i, k := range memcachedKeys {
+		item, ok := items[k]
+		if !ok {
+			continue
+		}
+		found[i] = true
+		if err = c.codec.Unmarshal(item.Value, pointers[i]); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// DeleteMany deletes the stored values for the given keys.
+// Memcached doesn't have a command for deleting multiple items,
+// so there's one request per key.
+// Deleting non-existing key-value pairs does NOT lead to an error.
+// The keys must not be "".
+func (c Client) DeleteMany(keys []string) error {
+	if err := util.CheckKeys(keys); err != nil {
+		return err
+	}
+
+	for _, k := range keys {
+		err := c.c.Delete(c.key(k))
+		if err != nil && err != memcache.ErrCacheMiss {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,24 +1,36 @@
 package memcached
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
 var defaultTimeout = 200 * time.Millisecond
 
+// maxKeyLength is the maximum key length of Memcached.
+const maxKeyLength = 250
+
+// hashedKeyPrefix is prepended to hashed keys, which makes them recognizable.
+const hashedKeyPrefix = "sha256:"
+
 // Client is a gokv.Store implementation for Memcached.
 type Client struct {
-	c     *memcache.Client
-	codec encoding.Codec
+	c            *memcache.Client
+	hashLongKeys bool
+	codec        encoding.Codec
 }
 
 // Set stores the given value for the given key.
-// The key must not be longer than 250 bytes (this is a restriction of Memcached).
+// The key must not be longer than 250 bytes (this is a restriction of Memcached),
+// unless HashLongKeys is enabled in the options.
 // Values are automatically marshalled to JSON or gob (depending on the configuration).
 // The key must not be "" and the value must not be nil.
 func (c Client) Set(k string, v any) error {
@@ -33,7 +45,7 @@ func (c Client) Set(k string, v any) error {
 	}
 
 	item := memcache.Item{
-		Key:   k,
+		Key:   c.key(k),
 		Value: data,
 	}
 	err = c.c.Set(&item)
@@ -45,7 +57,8 @@ func (c Client) Set(k string, v any) error {
 }
 
 // Get retrieves the stored value for the given key.
-// The key must not be longer than 250 bytes (this is a restriction of Memcached).
+// The key must not be longer than 250 bytes (this is a restriction of Memcached),
+// unless HashLongKeys is enabled in the options.
 // You need to pass a pointer to the value, so in case of a struct
 // the automatic unmarshalling can populate the fields of the object
 // that v points to with the values of the retrieved object's values.
@@ -56,7 +69,7 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 		return false, err
 	}
 
-	item, err := c.c.Get(k)
+	item, err := c.c.Get(c.key(k))
 	// If no value was found return false
 	if err == memcache.ErrCacheMiss {
 		return false, nil
@@ -69,7 +82,8 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 }
 
 // Delete deletes the stored value for the given key.
-// The key must not be longer than 250 bytes (this is a restriction of Memcached).
+// The key must not be longer than 250 bytes (this is a restriction of Memcached),
+// unless HashLongKeys is enabled in the options.
 // Deleting a non-existing key-value pair does NOT lead to an error.
 // The key must not be "".
 func (c Client) Delete(k string) error {
@@ -77,13 +91,38 @@ func (c Client) Delete(k string) error {
 		return err
 	}
 
-	err := c.c.Delete(k)
+	err := c.c.Delete(c.key(k))
 	if err == memcache.ErrCacheMiss {
 		return nil
 	}
 	return err
 }
 
+// key returns the key that's used in Memcached for the given key.
+// With HashLongKeys enabled, keys that are too long for Memcached are replaced by their SHA-256 hash.
+func (c Client) key(k string) string {
+	if !c.hashLongKeys || len(k) <= maxKeyLength {
+		return k
+	}
+	hash := sha256.Sum256([]byte(k))
+	return hashedKeyPrefix + hex.EncodeToString(hash[:])
+}
+
+// Ping checks if all Memcached servers are reachable.
+// The client doesn't accept a context, so the configured timeout applies to each server
+// and the context is only checked before the servers are pinged.
+func (c Client) Ping(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return c.c.Ping()
+}
+
+// Kind returns gokv.KindMemcached.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindMemcached
+}
+
 // Close closes the client.
 // In the Memcached implementation this doesn't have any effect.
 func (c Client) Close() error {
@@ -108,18 +147,26 @@ type Options struct {
 	// 0 will lead to the default value being used.
 	// Optional (100 by default).
 	MaxIdleConns int
+	// Replace keys that are longer than the 250 bytes that Memcached allows
+	// by a deterministic hash (SHA-256), which is transparent for Set, Get and Delete.
+	// Shorter keys are used as they are.
+	// Collisions are astronomically unlikely.
+	// The original keys are not stored, so they can't be listed via Memcached tools.
+	// Optional (false by default).
+	HashLongKeys bool
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// Addresses: "localhost:11211", Timeout: 200 milliseconds, MaxIdleConns: 100, Codec: encoding.JSON
+// Addresses: "localhost:11211", Timeout: 200 milliseconds, MaxIdleConns: 100, HashLongKeys: false, Codec: encoding.JSON
 var DefaultOptions = Options{
 	Addresses:    []string{"localhost:11211"},
 	Timeout:      &defaultTimeout,
 	MaxIdleConns: 100,
 	Codec:        encoding.JSON,
+	// No need to set HashLongKeys because its zero value is fine.
 }
 
 // NewClient creates a new Memcached client.
@@ -145,6 +192,7 @@ func NewClient(options Options) (Client, error) {
 	mc.MaxIdleConns = options.MaxIdleConns
 
 	result.c = mc
+	result.hashLongKeys = options.HashLongKeys
 	result.codec = options.Codec
 
 	return result, nil
@@ -2,6 +2,7 @@ package memcached_test
 
 import (
 	"log"
+	"strings"
 	"testing"
 	"time"
 
@@ -43,6 +44,21 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the Memcached client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -54,6 +70,13 @@ func TestClientConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, client)
 }
 
+// TestBatch tests if the gokv.BatchStore methods work properly.
+func TestBatch(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestBatchStore(client, t)
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -126,6 +149,13 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestPing tests if the server is reachable via Ping.
+func TestPing(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestPinger(client, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -162,6 +192,59 @@ func TestDefaultTimeout(t *testing.T) {
 	}
 }
 
+// TestHashLongKeys tests if keys that are longer than what Memcached allows work when HashLongKeys is enabled.
+func TestHashLongKeys(t *testing.T) {
+	longKey := strings.Repeat("a", 300)
+
+	// Without the option Memcached rejects the key
+	client := createClient(t, encoding.JSON)
+	err := client.Set(longKey, "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	timeout := 2 * time.Second
+	options := memcached.Options{
+		Timeout:      &timeout,
+		HashLongKeys: true,
+	}
+	client, err = memcached.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Set(longKey, "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Another long key with the same prefix must not collide
+	err = client.Set(longKey+"b", "baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vPtr := new(string)
+	found, err := client.Get(longKey, vPtr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("A value should have been found, but wasn't.")
+	}
+	if *vPtr != "bar" {
+		t.Errorf("Expected %v, but was %v", "bar", *vPtr)
+	}
+	err = client.Delete(longKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err = client.Get(longKey, vPtr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+}
+
 // checkConnection returns true if a connection could be made, false otherwise.
 func checkConnection() bool {
 	mc := memcache.New("localhost:11211")
@@ -0,0 +1,4 @@
+/*
+Package merge contains a `gokv.Store` wrapper that can merge a partial map into a stored map value.
+*/
+package merge
@@ -0,0 +1,184 @@
+package merge
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"sync"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// Number of locks that the keys are distributed over.
+const keyLockCount = 256
+
+// ErrNotAMap is returned by Merge when the existing value can't be read as map.
+var ErrNotAMap = errors.New("The existing value is not a map")
+
+// Store is a gokv.Store implementation that forwards all calls to another store
+// and additionally offers a Merge method for map values.
+type Store struct {
+	inner gokv.Store
+	deep  bool
+	// For locking the read-modify-write cycle of Merge per key.
+	// Keys are hashed to a fixed number of locks, so the memory doesn't grow with the number of keys.
+	keyLocks []sync.Mutex
+}
+
+// Set stores the given value for the given key in the inner store, overwriting any existing value.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	lock := s.keyLock(k)
+	lock.Lock()
+	defer lock.Unlock()
+	return s.inner.Set(k, v)
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	return s.inner.Get(k, v)
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	lock := s.keyLock(k)
+	lock.Lock()
+	defer lock.Unlock()
+	return s.inner.Delete(k)
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// Merge merges the given partial map into the map that's stored for the given key.
+// Keys in partial overwrite existing keys, all other existing keys are preserved.
+// With the Deep option, nested maps are merged recursively instead of being overwritten.
+// If no value exists yet, partial is stored as is.
+// If the existing value isn't a map, ErrNotAMap is returned.
+//
+// The read-modify-write cycle is locked per key, so concurrent calls to Merge, Set and Delete
+// on the same Store don't lead to lost updates.
+// Writes to the inner store that bypass this Store are not covered by the lock.
+// The key must not be "" and partial must not be nil.
+func (s Store) Merge(k string, partial map[string]any) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+	if partial == nil {
+		return util.CheckVal(nil)
+	}
+
+	lock := s.keyLock(k)
+	lock.Lock()
+	defer lock.Unlock()
+
+	existing, found, err := s.getMap(k)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return s.inner.Set(k, partial)
+	}
+	if existing == nil {
+		// A stored JSON "null" for example.
+		return ErrNotAMap
+	}
+
+	mergeMaps(existing, partial, s.deep)
+	return s.inner.Set(k, existing)
+}
+
+// getMap retrieves the map that's stored for the given key.
+// If the inner store implements gokv.RawStore, the value is retrieved and unmarshalled separately,
+// so any unmarshal error means that the value isn't a map, independent of the codec.
+// Otherwise only JSON type errors can be told apart from errors of the inner store.
+func (s Store) getMap(k string) (m map[string]any, found bool, err error) {
+	if rawStore, ok := s.inner.(gokv.RawStore); ok {
+		data, found, err := rawStore.GetRaw(k)
+		if err != nil || !found {
+			return nil, found, err
+		}
+		if err := rawStore.Unmarshal(data, &m); err != nil {
+			return nil, true, fmt.Errorf("%w: %v", ErrNotAMap, err)
+		}
+		return m, true, nil
+	}
+
+	found, err = s.inner.Get(k, &m)
+	if err != nil {
+		var typeErr *json.UnmarshalTypeError
+		if errors.As(err, &typeErr) {
+			return nil, true, fmt.Errorf("%w: %v", ErrNotAMap, err)
+		}
+		return nil, false, err
+	}
+	return m, found, nil
+}
+
+// mergeMaps merges src into dst.
+// If deep is true, maps that exist in both dst and src under the same key are merged recursively.
+func mergeMaps(dst, src map[string]any, deep bool) {
+	for k, srcVal := range src {
+		if deep {
+			srcMap, srcIsMap := srcVal.(map[string]any)
+			dstMap, dstIsMap := dst[k].(map[string]any)
+			if srcIsMap && dstIsMap {
+				mergeMaps(dstMap, srcMap, deep)
+				continue
+			}
+		}
+		dst[k] = srcVal
+	}
+}
+
+// keyLock returns the lock for the given key, which can be shared with other keys.
+func (s Store) keyLock(k string) *sync.Mutex {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(k))
+	return &s.keyLocks[h.Sum32()%keyLockCount]
+}
+
+// Options are the options for the merge store.
+type Options struct {
+	// Merge nested maps recursively instead of overwriting them.
+	// Optional (false by default).
+	Deep bool
+}
+
+// DefaultOptions is an Options object with default values.
+// Deep: false
+var DefaultOptions = Options{
+	// No need to set Deep because its zero value is fine.
+}
+
+// NewStore creates a new merge store that wraps the given store.
+// The inner store must use a codec that can decode stored maps into a map[string]any,
+// like encoding.JSON.
+// Merge only detects that an existing value isn't a map (ErrNotAMap) with any codec
+// if the inner store implements gokv.RawStore. Otherwise it's only detected with encoding.JSON,
+// and with other codecs their unmarshal error is returned.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, options Options) Store {
+	return Store{
+		inner:    inner,
+		deep:     options.Deep,
+		keyLocks: make([]sync.Mutex, keyLockCount),
+	}
+}
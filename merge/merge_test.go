@@ -0,0 +1,210 @@
+package merge_test
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/merge"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+func TestStore(t *testing.T) {
+	store := createStore(t, merge.DefaultOptions)
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := createStore(t, merge.DefaultOptions)
+	test.TestTypes(store, t)
+}
+
+// TestMerge tests that merging adds and overrides keys while preserving untouched ones.
+func TestMerge(t *testing.T) {
+	t.Run("shallow", func(t *testing.T) {
+		store := createStore(t, merge.Options{Deep: false})
+		err := store.Set("foo", map[string]any{
+			"a": "1",
+			"b": "2",
+			"nested": map[string]any{
+				"x": "1",
+				"y": "2",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Merge("foo", map[string]any{
+			"b": "3",
+			"c": "4",
+			"nested": map[string]any{
+				"y": "3",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]any{
+			"a": "1",
+			"b": "3",
+			"c": "4",
+			"nested": map[string]any{
+				"y": "3",
+			},
+		}
+		assertStored(t, store, "foo", expected)
+	})
+
+	t.Run("deep", func(t *testing.T) {
+		store := createStore(t, merge.Options{Deep: true})
+		err := store.Set("foo", map[string]any{
+			"a": "1",
+			"nested": map[string]any{
+				"x": "1",
+				"y": "2",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Merge("foo", map[string]any{
+			"nested": map[string]any{
+				"y": "3",
+				"z": "4",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		expected := map[string]any{
+			"a": "1",
+			"nested": map[string]any{
+				"x": "1",
+				"y": "3",
+				"z": "4",
+			},
+		}
+		assertStored(t, store, "foo", expected)
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		store := createStore(t, merge.DefaultOptions)
+		partial := map[string]any{"a": "1"}
+		err := store.Merge("foo", partial)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStored(t, store, "foo", partial)
+	})
+
+	t.Run("not a map", func(t *testing.T) {
+		store := createStore(t, merge.DefaultOptions)
+		err := store.Set("foo", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Merge("foo", map[string]any{"a": "1"})
+		if !errors.Is(err, merge.ErrNotAMap) {
+			t.Errorf("Expected ErrNotAMap, but was: %v", err)
+		}
+	})
+
+	// Without gokv.RawStore only JSON type errors are detected
+	t.Run("not a map without raw store", func(t *testing.T) {
+		inner := gomap.NewStore(gomap.Options{Codec: encoding.JSON})
+		store := merge.NewStore(nonRawStore{inner}, merge.DefaultOptions)
+		err := store.Set("foo", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Merge("foo", map[string]any{"a": "1"})
+		if !errors.Is(err, merge.ErrNotAMap) {
+			t.Errorf("Expected ErrNotAMap, but was: %v", err)
+		}
+	})
+
+	t.Run("not a map with gob", func(t *testing.T) {
+		inner := gomap.NewStore(gomap.Options{Codec: encoding.Gob})
+		store := merge.NewStore(inner, merge.DefaultOptions)
+		err := store.Set("foo", "bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Merge("foo", map[string]any{"a": "1"})
+		if !errors.Is(err, merge.ErrNotAMap) {
+			t.Errorf("Expected ErrNotAMap, but was: %v", err)
+		}
+
+		err = store.Set("foo", map[string]any{"a": "1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = store.Merge("foo", map[string]any{"b": "2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertStored(t, store, "foo", map[string]any{"a": "1", "b": "2"})
+	})
+}
+
+// nonRawStore hides that the embedded store implements gokv.RawStore.
+type nonRawStore struct {
+	gokv.Store
+}
+
+// TestMergeConcurrent tests that concurrent merges into the same key don't lead to lost updates.
+func TestMergeConcurrent(t *testing.T) {
+	store := createStore(t, merge.DefaultOptions)
+
+	goroutineCount := 100
+	expected := make(map[string]any, goroutineCount)
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		key := strconv.Itoa(i)
+		expected[key] = key
+		go func() {
+			defer waitGroup.Done()
+			if err := store.Merge("foo", map[string]any{key: key}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	waitGroup.Wait()
+
+	assertStored(t, store, "foo", expected)
+}
+
+func assertStored(t *testing.T, store merge.Store, k string, expected map[string]any) {
+	t.Helper()
+	var actual map[string]any
+	found, err := store.Get(k, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if diff := deep.Equal(actual, expected); diff != nil {
+		t.Error(diff)
+	}
+}
+
+func createStore(t *testing.T, options merge.Options) merge.Store {
+	inner := gomap.NewStore(gomap.Options{Codec: encoding.JSON})
+	store := merge.NewStore(inner, options)
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return store
+}
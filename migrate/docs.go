@@ -0,0 +1,30 @@
+/*
+Package migrate contains a `gokv.Store` wrapper that stores a schema version with each value
+and upgrades values that were stored with an older version when they're retrieved,
+so that the structs of long-lived values can change over releases.
+
+The schema version is the number of registered migrations plus one.
+When a value is retrieved that was stored with an older version,
+the migrations from its version up to the current version are run one after another,
+for example v1 -> v2 -> v3, before the value is unmarshalled.
+Optionally the upgraded value is written back to the inner store,
+so the migrations only run once per value.
+
+Example:
+
+	// Release 1 stored a name, release 2 split it into first and last name.
+	func splitName(data []byte) ([]byte, error) {
+		var v1 struct{ Name string }
+		if err := json.Unmarshal(data, &v1); err != nil {
+			return nil, err
+		}
+		first, last, _ := strings.Cut(v1.Name, " ")
+		return json.Marshal(struct{ FirstName, LastName string }{first, last})
+	}
+
+	store, err := migrate.NewStore(inner, migrate.Options{
+		Migrations:      []migrate.Migration{splitName},
+		PersistUpgrades: true,
+	})
+*/
+package migrate
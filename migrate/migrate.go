@@ -0,0 +1,197 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// Migration upgrades a value from one schema version to the next.
+// It gets the value marshalled with the codec from the options
+// and must return the upgraded value marshalled with the same codec.
+type Migration func(data []byte) ([]byte, error)
+
+// envelope is what's stored in the inner store for each value.
+type envelope struct {
+	Version int
+	Data    []byte
+}
+
+// Store is a gokv.Store implementation that stores a schema version with each value
+// in another store and upgrades values with an older version on Get.
+type Store struct {
+	inner           gokv.Store
+	migrations      []Migration
+	persistUpgrades bool
+	codec           encoding.Codec
+}
+
+// Version returns the current schema version, which is the number of migrations plus one.
+// Set stores all values with this version.
+func (s Store) Version() int {
+	return len(s.migrations) + 1
+}
+
+// Set stores the given value for the given key in the inner store,
+// together with the current schema version.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.inner.Set(k, envelope{
+		Version: s.Version(),
+		Data:    data,
+	})
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// If it was stored with an older schema version, the migrations up to the current version
+// are run before the value is unmarshalled into v, and with Options.PersistUpgrades
+// the upgraded value is written back to the inner store.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	stored := envelope{}
+	found, err = s.inner.Get(k, &stored)
+	if err != nil || !found {
+		return found, err
+	}
+
+	upgraded, err := s.upgrade(k, stored)
+	if err != nil {
+		return true, err
+	}
+
+	if err = s.codec.Unmarshal(upgraded.Data, v); err != nil {
+		return true, err
+	}
+
+	if s.persistUpgrades && upgraded.Version != stored.Version {
+		return true, s.persist(k, stored, upgraded)
+	}
+	return true, nil
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	return s.inner.Delete(k)
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// upgrade runs the migrations from the version of the stored value up to the current version.
+func (s Store) upgrade(k string, stored envelope) (envelope, error) {
+	if stored.Version < 1 {
+		return stored, fmt.Errorf("The value for key %v has no schema version. It probably wasn't stored via a migrate store", k)
+	}
+	if stored.Version > s.Version() {
+		return stored, fmt.Errorf("The value for key %v was stored with schema version %v, but the current version is only %v", k, stored.Version, s.Version())
+	}
+
+	data := stored.Data
+	for version := stored.Version; version < s.Version(); version++ {
+		var err error
+		data, err = s.migrations[version-1](data)
+		if err != nil {
+			return stored, fmt.Errorf("Migrating the value for key %v from schema version %v to %v failed: %w", k, version, version+1, err)
+		}
+	}
+
+	return envelope{
+		Version: s.Version(),
+		Data:    data,
+	}, nil
+}
+
+// persist writes the upgraded value back to the inner store.
+// If the inner store implements gokv.CASStore, the value is only replaced
+// if it wasn't changed in the meantime, so a concurrent Set isn't overwritten.
+func (s Store) persist(k string, stored, upgraded envelope) error {
+	if _, ok := s.inner.(gokv.CASStore); ok {
+		// When the value was changed concurrently, the changed value has the current version
+		// or will be upgraded with the next Get, so there's nothing to do in that case.
+		_, err := gokv.CompareAndSwap(s.inner, k, stored, upgraded)
+		return err
+	}
+	return s.inner.Set(k, upgraded)
+}
+
+// Options are the options for the migrate store.
+type Options struct {
+	// Migrations between consecutive schema versions.
+	// The first migration upgrades values from version 1 to 2, the second from 2 to 3 and so on,
+	// so the current version is len(Migrations) + 1.
+	// Stored values refer to the migrations by their position,
+	// so new migrations must only be appended, never inserted, removed or reordered.
+	// Optional (nil by default, which means all values have version 1).
+	Migrations []Migration
+	// Write upgraded values back to the inner store during Get,
+	// so the migrations only run once per value.
+	// If the inner store implements gokv.CASStore, the upgraded value
+	// doesn't overwrite a value that was set concurrently.
+	// Optional (false by default).
+	PersistUpgrades bool
+	// Encoding format of the values that are passed to and returned by the migrations.
+	// The inner store's codec is then used for the value together with its schema version.
+	// Optional (encoding.JSON by default).
+	Codec encoding.Codec
+}
+
+// DefaultOptions is an Options object with default values.
+// Migrations: nil, PersistUpgrades: false, Codec: encoding.JSON
+var DefaultOptions = Options{
+	Codec: encoding.JSON,
+	// No need to set Migrations or PersistUpgrades because their Go zero values are fine.
+}
+
+// NewStore creates a new migrate store that wraps the given store.
+// The slice of migrations is copied, so changing it afterwards doesn't have any effect.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, options Options) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if inner == nil {
+		return result, errors.New("The inner store must not be nil")
+	}
+	for i, migration := range options.Migrations {
+		if migration == nil {
+			return result, fmt.Errorf("The migration from schema version %v to %v must not be nil", i+1, i+2)
+		}
+	}
+
+	// Set default values
+	if options.Codec == nil {
+		options.Codec = DefaultOptions.Codec
+	}
+
+	result.inner = inner
+	result.migrations = append([]Migration(nil), options.Migrations...)
+	result.persistUpgrades = options.PersistUpgrades
+	result.codec = options.Codec
+
+	return result, nil
+}
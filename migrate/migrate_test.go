@@ -0,0 +1,229 @@
+package migrate_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/migrate"
+	"github.com/philippgille/gokv/test"
+)
+
+type userV1 struct {
+	Name string
+}
+
+type userV2 struct {
+	FirstName string
+	LastName  string
+}
+
+type userV3 struct {
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+// splitName migrates userV1 to userV2.
+func splitName(data []byte) ([]byte, error) {
+	v1 := userV1{}
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return nil, err
+	}
+	first, last, _ := strings.Cut(v1.Name, " ")
+	return json.Marshal(userV2{FirstName: first, LastName: last})
+}
+
+// addEmail migrates userV2 to userV3.
+func addEmail(data []byte) ([]byte, error) {
+	v2 := userV2{}
+	if err := json.Unmarshal(data, &v2); err != nil {
+		return nil, err
+	}
+	email := strings.ToLower(v2.FirstName) + "@example.com"
+	return json.Marshal(userV3{FirstName: v2.FirstName, LastName: v2.LastName, Email: email})
+}
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestStore(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), migrate.Options{Codec: encoding.JSON})
+		test.TestStore(store, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, gomap.NewStore(gomap.DefaultOptions), migrate.Options{Codec: encoding.Gob})
+		test.TestStore(store, t)
+	})
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), migrate.DefaultOptions)
+	test.TestTypes(store, t)
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), migrate.DefaultOptions)
+
+	goroutineCount := 1000
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+}
+
+// TestMigrations tests that a value stored with version 1 is migrated to version 3 on Get.
+func TestMigrations(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	storeV1 := createStore(t, inner, migrate.DefaultOptions)
+
+	// Count the calls to check whether the upgraded value is persisted
+	calls := 0
+	countingSplitName := func(data []byte) ([]byte, error) {
+		calls++
+		return splitName(data)
+	}
+
+	expected := userV3{FirstName: "Jane", LastName: "Doe", Email: "jane@example.com"}
+	for _, persistUpgrades := range []bool{false, true} {
+		calls = 0
+		err := storeV1.Set("user", userV1{Name: "Jane Doe"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		storeV3 := createStore(t, inner, migrate.Options{
+			Migrations:      []migrate.Migration{countingSplitName, addEmail},
+			PersistUpgrades: persistUpgrades,
+		})
+		if storeV3.Version() != 3 {
+			t.Errorf("Expected version 3, but was %v", storeV3.Version())
+		}
+
+		for i := 0; i < 2; i++ {
+			actual := userV3{}
+			found, err := storeV3.Get("user", &actual)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !found {
+				t.Fatal("No value was found, but should have been")
+			}
+			if actual != expected {
+				t.Errorf("Expected %+v, but was %+v", expected, actual)
+			}
+		}
+
+		expectedCalls := 2
+		if persistUpgrades {
+			expectedCalls = 1
+		}
+		if calls != expectedCalls {
+			t.Errorf("Expected %v migration calls with PersistUpgrades %v, but were %v", expectedCalls, persistUpgrades, calls)
+		}
+	}
+
+	// Values of a newer version can't be read by an older release
+	_, err := storeV1.Get("user", new(userV1))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestMigrationError tests that an error of a migration is returned and nothing is persisted.
+func TestMigrationError(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	storeV1 := createStore(t, inner, migrate.DefaultOptions)
+	err := storeV1.Set("user", userV1{Name: "Jane Doe"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errMigration := errors.New("migration failed")
+	storeV3 := createStore(t, inner, migrate.Options{
+		Migrations: []migrate.Migration{
+			splitName,
+			func([]byte) ([]byte, error) { return nil, errMigration },
+		},
+		PersistUpgrades: true,
+	})
+	_, err = storeV3.Get("user", new(userV3))
+	if !errors.Is(err, errMigration) {
+		t.Errorf("Expected the migration error, but was %v", err)
+	}
+
+	// The value is still at version 1
+	actual := userV1{}
+	found, err := storeV1.Get("user", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Name != "Jane Doe" {
+		t.Errorf("Expected %+v, but was %+v (found: %v)", userV1{Name: "Jane Doe"}, actual, found)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	// Test empty key
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), migrate.DefaultOptions)
+	err := store.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Test values that weren't stored via a migrate store
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	err = inner.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store = createStore(t, inner, migrate.DefaultOptions)
+	_, err = store.Get("foo", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Test invalid options
+	_, err = migrate.NewStore(nil, migrate.DefaultOptions)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = migrate.NewStore(gomap.NewStore(gomap.DefaultOptions), migrate.Options{
+		Migrations: []migrate.Migration{splitName, nil},
+	})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), migrate.DefaultOptions)
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func createStore(t *testing.T, inner gokv.Store, options migrate.Options) migrate.Store {
+	store, err := migrate.NewStore(inner, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
@@ -0,0 +1,33 @@
+package gokv
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrModTimeUnsupported is returned by ModTime when the store doesn't implement the ModTimer interface.
+var ErrModTimeUnsupported = errors.New("The store doesn't support modification times")
+
+// ModTimer is an optional interface for stores that can report when the value of a key was last modified,
+// without retrieving the value, for example to decide whether a cached value must be refreshed.
+// Check for it with a type assertion or use the ModTime function.
+//
+// It's implemented by the blobstorage, file, gcs and s3 implementations.
+type ModTimer interface {
+	// ModTime returns the time when the value for the given key was last modified.
+	// The precision depends on the implementation, for example S3 only reports full seconds.
+	// If no value is found it returns (time.Time{}, false, nil).
+	// The key must not be "".
+	ModTime(k string) (modTime time.Time, found bool, err error)
+}
+
+// ModTime returns the time when the value for the given key was last modified
+// via the store if it implements the ModTimer interface.
+// Otherwise ErrModTimeUnsupported is returned.
+func ModTime(store Store, k string) (modTime time.Time, found bool, err error) {
+	modTimer, ok := store.(ModTimer)
+	if !ok {
+		return time.Time{}, false, ErrModTimeUnsupported
+	}
+	return modTimer.ModTime(k)
+}
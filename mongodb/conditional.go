@@ -0,0 +1,105 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// DeleteIf deletes the stored value for the given key if it's equal to the expected value.
+// The value is part of the filter of a single DeleteOne, so the comparison and deletion are atomic.
+// The values are compared in their marshalled form.
+// If the key doesn't exist, is expired or holds a different value, it returns (false, nil).
+// The key must not be "" and the expected value must not be nil.
+func (c Client) DeleteIf(k string, expected any) (deleted bool, err error) {
+	if err := util.CheckKeyAndValue(k, expected); err != nil {
+		return false, err
+	}
+
+	data, err := c.codec.Marshal(expected)
+	if err != nil {
+		return false, err
+	}
+
+	ctx, cancel := withTimeout(context.Background(), c.timeout)
+	defer cancel()
+	res, err := c.c.DeleteOne(ctx, notExpired(bson.D{{c.keyField, k}, {"v", data}}))
+	if err != nil {
+		return false, err
+	}
+	return res.DeletedCount == 1, nil
+}
+
+// CompareAndSwap stores the new value for the given key if the stored value is equal to the old value.
+// The old value is part of the filter of a single FindOneAndReplace, so the comparison and replacement are atomic.
+// The values are compared in their marshalled form.
+// If the key doesn't exist, is expired or holds a different value, it returns (false, nil).
+// The key must not be "" and the values must not be nil.
+func (c Client) CompareAndSwap(k string, old, new any) (swapped bool, err error) {
+	if err := util.CheckKeyAndValue(k, old); err != nil {
+		return false, err
+	}
+	if err := util.CheckVal(new); err != nil {
+		return false, err
+	}
+
+	oldData, err := c.codec.Marshal(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.codec.Marshal(new)
+	if err != nil {
+		return false, err
+	}
+
+	item := item{
+		K: k,
+		V: newData,
+	}
+	ctx, cancel := withTimeout(context.Background(), c.timeout)
+	defer cancel()
+	err = c.c.FindOneAndReplace(ctx, notExpired(bson.D{{c.keyField, k}, {"v", oldData}}), c.document(item)).Err()
+	if err == mongo.ErrNoDocuments {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// SetIfAbsent stores the given value for the given key if the key doesn't exist yet or is expired.
+// It uses ReplaceOne with upsert and a filter that only matches expired documents,
+// so for an existing document that isn't expired the upsert leads to a duplicate key error,
+// and the existence check and the write are atomic.
+// If the key already exists, it returns (false, nil).
+// The key must not be "" and the value must not be nil.
+func (c Client) SetIfAbsent(k string, v any) (set bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	item := item{
+		K: k,
+		V: data,
+	}
+	filter := bson.D{{c.keyField, k}, {"expiresAt", bson.D{{"$lte", time.Now()}}}}
+	ctx, cancel := withTimeout(context.Background(), c.timeout)
+	defer cancel()
+	_, err = c.c.ReplaceOne(ctx, filter, c.document(item), options.Replace().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,58 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+var incrementOpt = options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+// Increment adds delta to the counter that's stored for the given key and returns the new count.
+// It uses FindOneAndUpdate with $inc, so the counter is stored as integer in the "n" field
+// and Get passes its decimal representation to the codec, which means it can be read into an int64 when using encoding.JSON.
+// A non-existing or expired counter starts at 0. delta can be negative to decrement the counter.
+// If the key holds a value that was stored with Set, gokv.ErrNotACounter is returned.
+// The key must not be "".
+func (c Client) Increment(k string, delta int64) (int64, error) {
+	if err := util.CheckKey(k); err != nil {
+		return 0, err
+	}
+
+	// Documents with a value that was stored with Set and expired documents don't match the filter,
+	// so the upsert leads to a duplicate key error for them.
+	filter := notExpired(bson.D{{c.keyField, k}, {"v", bson.D{{"$exists", false}}}})
+	update := bson.D{{"$inc", bson.D{{"n", delta}}}}
+	item := new(item)
+	ctx, cancel := withTimeout(context.Background(), c.timeout)
+	defer cancel()
+	err := c.decode(c.c.FindOneAndUpdate(ctx, filter, update, incrementOpt), item)
+	if mongo.IsDuplicateKeyError(err) {
+		// An expired document that MongoDB's TTL monitor didn't delete yet is treated as missing,
+		// so it's replaced by a new counter, as long as it's still expired.
+		expiredFilter := bson.D{{c.keyField, k}, {"expiresAt", bson.D{{"$lte", time.Now()}}}}
+		reset := bson.D{{"$set", bson.D{{"n", delta}}}, {"$unset", bson.D{{"v", ""}, {"expiresAt", ""}}}}
+		err = c.decode(c.c.FindOneAndUpdate(ctx, expiredFilter, reset, options.FindOneAndUpdate().SetReturnDocument(options.After)), item)
+		if err == mongo.ErrNoDocuments {
+			// Concurrent upserts of a new counter can also lead to a duplicate key error,
+			// in which case the counter exists now and the update without upsert succeeds.
+			err = c.decode(c.c.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().SetReturnDocument(options.After)), item)
+			if err == mongo.ErrNoDocuments {
+				return 0, gokv.ErrNotACounter
+			}
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+	if item.N == nil {
+		return 0, gokv.ErrNotACounter
+	}
+	return *item.N, nil
+}
@@ -3,5 +3,7 @@ Package mongodb contains an implementation of the `gokv.Store` interface for Mon
 
 Note: If you use a sharded cluster, you must use "_id" as the shard key!
 You should also use hashed sharding as opposed to ranged sharding to enable more evenly distributed data no matter how your key looks like.
+With Options.UseSeparateKeyField the shard key must be "k" instead, with ranged sharding,
+because MongoDB can't enforce the unique index on "k" across shards otherwise.
 */
 package mongodb
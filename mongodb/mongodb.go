@@ -2,6 +2,8 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -9,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -43,12 +46,34 @@ type item struct {
 	// - https://github.com/mongodb/docs/blob/81d03d2463bc995a451759ce44087fe7ecd4db74/source/core/sharding-shard-key.txt#L91
 	K string `bson:"_id"`
 	V []byte // "v" will be used as field name
+	// Only set by SetWithTTL. MongoDB deletes the document after this time via a TTL index.
+	ExpiresAt *time.Time `bson:"expiresAt,omitempty"`
+	// Only set by Increment, in which case V isn't set.
+	N *int64 `bson:"n,omitempty"`
 }
 
+// keyFieldItem is like item, but the key is stored in the separately indexed "k" field,
+// so MongoDB generates an ObjectId as "_id". Used when Options.UseSeparateKeyField is true.
+// It has the same fields as item, so they can be converted into each other.
+type keyFieldItem struct {
+	K         string     `bson:"k"`
+	V         []byte     // "v" will be used as field name
+	ExpiresAt *time.Time `bson:"expiresAt,omitempty"`
+	N         *int64     `bson:"n,omitempty"`
+}
+
+var (
+	defaultConnectionTimeout = 2 * time.Second
+	defaultOperationTimeout  = 2 * time.Second
+)
+
 // Client is a gokv.Store implementation for MongoDB.
 type Client struct {
-	c     *mongo.Collection
-	codec encoding.Codec
+	c       *mongo.Collection
+	codec   encoding.Codec
+	timeout time.Duration
+	// "_id" or "k", depending on Options.UseSeparateKeyField
+	keyField string
 
 	// Client and cancel are required on call to `Close()`
 	client *mongo.Client
@@ -59,6 +84,12 @@ type Client struct {
 // Values are automatically marshalled to JSON or gob (depending on the configuration).
 // The key must not be "" and the value must not be nil.
 func (c Client) Set(k string, v any) error {
+	return c.SetCtx(context.Background(), k, v)
+}
+
+// SetCtx is like Set, but the context is passed to the MongoDB driver.
+// The operation timeout still applies, unless the context has an earlier deadline.
+func (c Client) SetCtx(ctx context.Context, k string, v any) error {
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return err
 	}
@@ -76,7 +107,9 @@ func (c Client) Set(k string, v any) error {
 		K: k,
 		V: data,
 	}
-	_, err = c.c.ReplaceOne(context.Background(), bson.D{{"_id", k}}, item, setOpt)
+	ctx, cancel := withTimeout(ctx, c.timeout)
+	defer cancel()
+	_, err = c.c.ReplaceOne(ctx, bson.D{{c.keyField, k}}, c.document(item), setOpt)
 	if err != nil {
 		return err
 	}
@@ -91,19 +124,34 @@ func (c Client) Set(k string, v any) error {
 // If no value is found it returns (false, nil).
 // The key must not be "" and the pointer must not be nil.
 func (c Client) Get(k string, v any) (found bool, err error) {
+	return c.GetCtx(context.Background(), k, v)
+}
+
+// GetCtx is like Get, but the context is passed to the MongoDB driver.
+// The operation timeout still applies, unless the context has an earlier deadline.
+func (c Client) GetCtx(ctx context.Context, k string, v any) (found bool, err error) {
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return false, err
 	}
 
+	ctx, cancel := withTimeout(ctx, c.timeout)
+	defer cancel()
 	item := new(item)
-	err = c.c.FindOne(context.Background(), bson.D{{"_id", k}}).Decode(item)
+	err = c.decode(c.c.FindOne(ctx, bson.D{{c.keyField, k}}), item)
 	// If no value was found return false
 	if err == mongo.ErrNoDocuments {
 		return false, nil
 	} else if err != nil {
 		return false, err
 	}
+	// MongoDB's TTL monitor only runs every 60 seconds, so expired documents can still exist.
+	if item.ExpiresAt != nil && !time.Now().Before(*item.ExpiresAt) {
+		return false, nil
+	}
 	data := item.V
+	if item.N != nil {
+		data = strconv.AppendInt(nil, *item.N, 10)
+	}
 
 	return true, c.codec.Unmarshal(data, v)
 }
@@ -112,17 +160,36 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 // Deleting a non-existing key-value pair does NOT lead to an error.
 // The key must not be "".
 func (c Client) Delete(k string) error {
+	return c.DeleteCtx(context.Background(), k)
+}
+
+// DeleteCtx is like Delete, but the context is passed to the MongoDB driver.
+// The operation timeout still applies, unless the context has an earlier deadline.
+func (c Client) DeleteCtx(ctx context.Context, k string) error {
 	if err := util.CheckKey(k); err != nil {
 		return err
 	}
 
-	_, err := c.c.DeleteOne(context.Background(), bson.D{{"_id", k}})
+	ctx, cancel := withTimeout(ctx, c.timeout)
+	defer cancel()
+	_, err := c.c.DeleteOne(ctx, bson.D{{c.keyField, k}})
 	// No need to check for mongo.ErrNoDocuments, because DeleteOne() doesn't return
 	// any error if no document was deleted. This differs from a previous version
 	// where we used mgo.
 	return err
 }
 
+// Ping checks if the MongoDB server is reachable.
+// It uses the read preference of the MongoDB client.
+func (c Client) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx, nil)
+}
+
+// Kind returns gokv.KindMongoDB.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindMongoDB
+}
+
 // Close closes the client.
 // It must be called to release any open resources.
 func (c Client) Close() error {
@@ -130,6 +197,38 @@ func (c Client) Close() error {
 	return c.client.Disconnect(context.Background())
 }
 
+// document returns the document that's stored for the item,
+// which has the key either in the "_id" or the "k" field.
+func (c Client) document(i item) any {
+	if c.keyField == "k" {
+		return keyFieldItem(i)
+	}
+	return i
+}
+
+// decode decodes the document of the result into the item,
+// with the key either from the "_id" or the "k" field.
+func (c Client) decode(res *mongo.SingleResult, i *item) error {
+	if c.keyField == "k" {
+		doc := new(keyFieldItem)
+		if err := res.Decode(doc); err != nil {
+			return err
+		}
+		*i = item(*doc)
+		return nil
+	}
+	return res.Decode(i)
+}
+
+// withTimeout returns a context that's canceled after the timeout.
+// A timeout of 0 means no timeout.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
 // Options are the options for the MongoDB client.
 type Options struct {
 	// Seed servers for the initial connection to the MongoDB cluster.
@@ -144,18 +243,38 @@ type Options struct {
 	// The name of the collection to use.
 	// Optional ("item" by default).
 	CollectionName string
+	// The timeout for connecting to the server when the client is created,
+	// which includes the server discovery and the creation of the index for SetWithTTL.
+	// 0 means no timeout.
+	// Optional (2 * time.Second by default).
+	ConnectionTimeout *time.Duration
+	// The timeout for each operation, for example for Set, Get and Delete.
+	// For the methods with a context parameter, the earlier of the timeout and the context's deadline applies.
+	// 0 means no timeout.
+	// Optional (2 * time.Second by default).
+	OperationTimeout *time.Duration
+	// Store the key in a separate "k" field with a unique index instead of using it as "_id",
+	// in which case MongoDB generates an ObjectId as "_id".
+	// This allows to use "k" as shard key in a sharded cluster instead of "_id" (see the package documentation).
+	// Documents that were stored without this option (or with it) aren't found after changing it,
+	// so it must not be changed for an existing collection.
+	// Optional (false by default).
+	UseSeparateKeyField bool
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// ConnectionString: "localhost", DatabaseName: "gokv", CollectionName: "item", Codec: encoding.JSON
+// ConnectionString: "localhost", DatabaseName: "gokv", CollectionName: "item",
+// ConnectionTimeout: 2 * time.Second, OperationTimeout: 2 * time.Second, UseSeparateKeyField: false, Codec: encoding.JSON
 var DefaultOptions = Options{
-	ConnectionString: "mongodb://localhost",
-	DatabaseName:     "gokv",
-	CollectionName:   "item",
-	Codec:            encoding.JSON,
+	ConnectionString:  "mongodb://localhost",
+	DatabaseName:      "gokv",
+	CollectionName:    "item",
+	ConnectionTimeout: &defaultConnectionTimeout,
+	OperationTimeout:  &defaultOperationTimeout,
+	Codec:             encoding.JSON,
 }
 
 // NewClient creates a new MongoDB client.
@@ -164,6 +283,11 @@ var DefaultOptions = Options{
 func NewClient(opts Options) (Client, error) {
 	result := Client{}
 
+	// Precondition check
+	if (opts.ConnectionTimeout != nil && *opts.ConnectionTimeout < 0) || (opts.OperationTimeout != nil && *opts.OperationTimeout < 0) {
+		return result, errors.New("The ConnectionTimeout and OperationTimeout must not be negative")
+	}
+
 	// Set default values
 	if opts.ConnectionString == "" {
 		opts.ConnectionString = DefaultOptions.ConnectionString
@@ -174,11 +298,17 @@ func NewClient(opts Options) (Client, error) {
 	if opts.CollectionName == "" {
 		opts.CollectionName = DefaultOptions.CollectionName
 	}
+	if opts.ConnectionTimeout == nil {
+		opts.ConnectionTimeout = DefaultOptions.ConnectionTimeout
+	}
+	if opts.OperationTimeout == nil {
+		opts.OperationTimeout = DefaultOptions.OperationTimeout
+	}
 	if opts.Codec == nil {
 		opts.Codec = DefaultOptions.Codec
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	ctx, cancel := withTimeout(context.Background(), *opts.ConnectionTimeout)
 	defer cancel()
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(opts.ConnectionString))
 	if err != nil {
@@ -188,7 +318,7 @@ func NewClient(opts Options) (Client, error) {
 	// The above `Connect` doesn't block for server discovery. But like with other
 	// gokv store implementations, we want to ensure that after client creation
 	// it's ready to use. So we call `Ping` to block until the server is discovered.
-	pingCtx, pingCancel := context.WithTimeout(context.Background(), 2*time.Second)
+	pingCtx, pingCancel := withTimeout(context.Background(), *opts.ConnectionTimeout)
 	defer pingCancel()
 	err = client.Ping(pingCtx, readpref.Primary())
 	if err != nil {
@@ -197,8 +327,33 @@ func NewClient(opts Options) (Client, error) {
 
 	c := client.Database(opts.DatabaseName).Collection(opts.CollectionName)
 
+	// Let MongoDB delete the documents that were stored with SetWithTTL after they expired.
+	// Creating an index that already exists doesn't have any effect.
+	indexCtx, indexCancel := withTimeout(context.Background(), *opts.ConnectionTimeout)
+	defer indexCancel()
+	_, err = c.Indexes().CreateOne(indexCtx, mongo.IndexModel{
+		Keys:    bson.D{{"expiresAt", 1}},
+		Options: options.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return result, err
+	}
+	keyField := "_id"
+	if opts.UseSeparateKeyField {
+		keyField = "k"
+		_, err = c.Indexes().CreateOne(indexCtx, mongo.IndexModel{
+			Keys:    bson.D{{"k", 1}},
+			Options: options.Index().SetUnique(true),
+		})
+		if err != nil {
+			return result, err
+		}
+	}
+
 	result.c = c
 	result.codec = opts.Codec
+	result.timeout = *opts.OperationTimeout
+	result.keyField = keyField
 	result.client = client
 	result.cancel = cancel
 
@@ -7,6 +7,8 @@ import (
 	"testing"
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"go.mongodb.org/mongo-driver/mongo/readpref"
@@ -51,6 +53,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the MongoDB client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -61,6 +80,212 @@ func TestClientConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, client)
 }
 
+// TestDeleteIf tests that a key-value pair is only deleted when it holds the expected value.
+func TestDeleteIf(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestConditionalDeleter(client, t)
+}
+
+// TestDeleteIfExpired tests that an expired key-value pair that MongoDB hasn't deleted yet isn't deleted by DeleteIf,
+// just like Get doesn't find it.
+func TestDeleteIfExpired(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	setExpired(t, client, "foo", "bar")
+
+	deleted, err := client.DeleteIf("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted {
+		t.Error("Expected the expired value not to be deleted")
+	}
+}
+
+// TestCompareAndSwap tests that a value is only replaced when it holds the expected value.
+func TestCompareAndSwap(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestCASStore(client, t)
+}
+
+// TestCompareAndSwapExpired tests that an expired key-value pair that MongoDB hasn't deleted yet isn't swapped,
+// just like Get doesn't find it.
+func TestCompareAndSwapExpired(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	setExpired(t, client, "foo", "bar")
+
+	swapped, err := client.CompareAndSwap("foo", "bar", "baz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapped {
+		t.Error("Expected the expired value not to be swapped")
+	}
+	found, err := client.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("Expected the value to still be expired")
+	}
+}
+
+// TestSetIfAbsent tests that a value is only stored when the key doesn't exist yet.
+func TestSetIfAbsent(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestConditionalSetter(client, t)
+}
+
+// TestUpdateFunc tests if concurrent updates via gokv.UpdateFunc don't get lost.
+func TestUpdateFunc(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestUpdateFunc(client, t)
+}
+
+// TestCounter tests if concurrent increments of a counter are atomic.
+func TestCounter(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestCounterStore(client, t)
+}
+
+// TestCounterExpired tests that an expired key-value pair that MongoDB hasn't deleted yet
+// is treated like a non-existing counter, even if its value isn't a number.
+func TestCounterExpired(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	setExpired(t, client, "foo", "bar")
+
+	count, err := client.Increment("foo", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("Expected %v, but was %v", 3, count)
+	}
+	count, err = client.Increment("foo", 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 {
+		t.Errorf("Expected %v, but was %v", 5, count)
+	}
+	var actual int64
+	found, err := client.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual != 5 {
+		t.Errorf("Expected %v, but found was %v and the value %v", 5, found, actual)
+	}
+}
+
+// TestTTL tests if key-value pairs expire after their TTL.
+func TestTTL(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestTTLStore(client, t)
+}
+
+// TestSeparateKeyField tests if the store works when the key is stored in a separate "k" field
+// and that MongoDB generates the "_id" in that case.
+func TestSeparateKeyField(t *testing.T) {
+	if !checkConnection() {
+		t.Skip("No connection to MongoDB could be established. Probably not running in a proper test environment.")
+	}
+
+	client, err := mongodb.NewClient(mongodb.Options{
+		// A separate collection is used, because documents with the key as "_id" aren't compatible
+		CollectionName:      "item-k",
+		UseSeparateKeyField: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	test.TestStore(client, t)
+	test.TestCASStore(client, t)
+	test.TestCounterStore(client, t)
+	test.TestTTLStore(client, t)
+
+	err = client.Set("separate", "value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	mongoClient, err := mongo.Connect(ctx, options.Client().ApplyURI("mongodb://localhost"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mongoClient.Disconnect(ctx)
+	doc := bson.M{}
+	err = mongoClient.Database("gokv").Collection("item-k").FindOne(ctx, bson.D{{"k", "separate"}}).Decode(&doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := doc["_id"].(primitive.ObjectID); !ok {
+		t.Errorf("Expected an ObjectId as _id, but was %v", doc["_id"])
+	}
+}
+
+// TestTimeouts tests if the connection and operation timeouts are applied.
+func TestTimeouts(t *testing.T) {
+	// Connecting to a non-routable address only ends with the timeout
+	connectionTimeout := 200 * time.Millisecond
+	start := time.Now()
+	_, err := mongodb.NewClient(mongodb.Options{
+		ConnectionString:  "mongodb://10.255.255.1",
+		ConnectionTimeout: &connectionTimeout,
+	})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 10*connectionTimeout {
+		t.Errorf("Expected the connection attempt to time out after %v, but it took %v", connectionTimeout, elapsed)
+	}
+
+	// Negative timeouts are invalid
+	negativeTimeout := -time.Second
+	_, err = mongodb.NewClient(mongodb.Options{
+		OperationTimeout: &negativeTimeout,
+	})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	if !checkConnection() {
+		t.Skip("No connection to MongoDB could be established. Probably not running in a proper test environment.")
+	}
+
+	operationTimeout := time.Nanosecond
+	client, err := mongodb.NewClient(mongodb.Options{
+		OperationTimeout: &operationTimeout,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	err = client.Set("foo", "bar")
+	if !mongo.IsTimeout(err) {
+		t.Errorf("Expected a timeout error, but was: %v", err)
+	}
+	_, err = client.Get("foo", new(string))
+	if !mongo.IsTimeout(err) {
+		t.Errorf("Expected a timeout error, but was: %v", err)
+	}
+	err = client.Delete("foo")
+	if !mongo.IsTimeout(err) {
+		t.Errorf("Expected a timeout error, but was: %v", err)
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -146,6 +371,13 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestPing tests if the server is reachable via Ping.
+func TestPing(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestPinger(client, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -173,6 +405,17 @@ func checkConnection() bool {
 	return true
 }
 
+// setExpired stores the value with a TTL and waits until it's expired.
+// MongoDB's TTL monitor only runs every 60 seconds, so the document still exists afterwards.
+func setExpired(t *testing.T, client mongodb.Client, k string, v any) {
+	t.Helper()
+	err := client.SetWithTTL(k, v, time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+}
+
 func createClient(t *testing.T, codec encoding.Codec) mongodb.Client {
 	options := mongodb.Options{
 		Codec: codec,
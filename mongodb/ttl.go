@@ -0,0 +1,51 @@
+package mongodb
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// SetWithTTL stores the given value for the given key, like Set,
+// but with an "expiresAt" field, for which the client created a TTL index.
+// After the TTL, Get returns (false, nil) for the key,
+// even if MongoDB didn't delete the document yet.
+// The expiry is based on the clock of the client, so the clients should be roughly in sync.
+// The key must not be "", the value must not be nil and the TTL must be positive.
+func (c Client) SetWithTTL(k string, v any, ttl time.Duration) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	expiresAt := time.Now().Add(ttl)
+	item := item{
+		K:         k,
+		V:         data,
+		ExpiresAt: &expiresAt,
+	}
+	ctx, cancel := withTimeout(context.Background(), c.timeout)
+	defer cancel()
+	_, err = c.c.ReplaceOne(ctx, bson.D{{c.keyField, k}}, c.document(item), setOpt)
+	return err
+}
+
+// notExpired adds a condition to the filter that only matches documents without an expiry or with one in the future.
+// MongoDB's TTL monitor only runs every 60 seconds, so expired documents can still exist,
+// and conditional writes must treat them as missing, just like Get does.
+func notExpired(filter bson.D) bson.D {
+	return append(filter, bson.D{{"$or", bson.A{
+		bson.D{{"expiresAt", bson.D{{"$exists", false}}}},
+		bson.D{{"expiresAt", bson.D{{"$gt", time.Now()}}}},
+	}}}...)
+}
@@ -0,0 +1,154 @@
+package mysql
+
+import (
+	"strings"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// Limits the number of key-value pairs per statement,
+// to stay below MySQL's limit of 65,535 placeholders and the max_allowed_packet size.
+const maxBatchSize = 1000
+
+// SetMany stores the given values for the given keys.
+// It uses a multi-row INSERT ... ON DUPLICATE KEY UPDATE with up to 1000 key-value pairs per statement.
+// The operation is not atomic.
+// The length of the keys must not exceed the KeyColumnLength (255 characters by default).
+// The keys must not be "" and the values must not be nil.
+func (c Client) SetMany(values map[string]any) error {
+	if err := util.CheckKeysAndValues(values); err != nil {
+		return err
+	}
+	for k := range values {
+		if err := c.checkKeyLength(k); err != nil {
+			return err
+		}
+	}
+
+	args := make([]any, 0, 2*len(values))
+	for k, v := range values {
+		data, err := c.c.Codec.Marshal(v)
+		if err != nil {
+			return err
+		}
+		args = append(args, k, data)
+	}
+
+	for start := 0; start < len(args); start += 2 * maxBatchSize {
+		end := start + 2*maxBatchSize
+		if end > len(args) {
+			end = len(args)
+		}
+		query := "INSERT INTO " + c.tableName + " (k, v) VALUES " + placeholders("(?, ?)", (end-start)/2) + " ON DUPLICATE KEY UPDATE v = VALUES(v)"
+		if c.timestamps {
+			query += ", updated_at = CURRENT_TIMESTAMP(6)"
+		}
+		_, err := c.c.C.Exec(query, args[start:end]...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetMany retrieves the stored values for the given keys.
+// It uses SELECT ... WHERE k IN (...) with up to 1000 keys per statement.
+// values must be a slice with the same length as keys, for example a []any or []*Foo,
+// with each element being a non-nil pointer that the value of the key at the same index is unmarshalled into.
+// found contains whether a value was found for the key at the same index.
+// The length of the keys must not exceed the KeyColumnLength (255 characters by default).
+// The keys must not be "".
+func (c Client) GetMany(keys []string, values any) (found []bool, err error) {
+	pointers, err := util.BatchPointers(keys, values)
+	if err != nil {
+		return nil, err
+	}
+	for _, k := range keys {
+		if err := c.checkKeyLength(k); err != nil {
+			return nil, err
+		}
+	}
+
+	data := make(map[string][]byte, len(keys))
+	for start := 0; start < len(keys); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		err = c.getChunk(keys[start:end], data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	found = make([]bool, len(keys))
+	for i, k := range keys {
+		d, ok := data[k]
+		if !ok {
+			continue
+		}
+		found[i] = true
+		if err = c.c.Codec.Unmarshal(d, pointers[i]); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+func (c Client) getChunk(keys []string, data map[string][]byte) error {
+	rows, err := c.c.C.Query("SELECT k, v FROM "+c.tableName+" WHERE k IN ("+placeholders("?", len(keys))+")", toArgs(keys)...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k string
+		var d []byte
+		if err = rows.Scan(&k, &d); err != nil {
+			return err
+		}
+		data[k] = d
+	}
+	return rows.Err()
+}
+
+// DeleteMany deletes the stored values for the given keys.
+// It uses DELETE ... WHERE k IN (...) with up to 1000 keys per statement.
+// Deleting non-existing key-value pairs does NOT lead to an error.
+// The length of the keys must not exceed the KeyColumnLength (255 characters by default).
+// The keys must not be "".
+func (c Client) DeleteMany(keys []string) error {
+	if err := util.CheckKeys(keys); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := c.checkKeyLength(k); err != nil {
+			return err
+		}
+	}
+
+	for start := 0; start < len(keys); start += maxBatchSize {
+		end := start + maxBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		_, err := c.c.C.Exec("DELETE FROM "+c.tableName+" WHERE k IN ("+placeholders("?", end-start)+")", toArgs(keys[start:end])...)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// placeholders returns the given placeholder n times, separated by commas.
+func placeholders(placeholder string, n int) string {
+	return strings.TrimSuffix(strings.Repeat(placeholder+", ", n), ", ")
+}
+
+func toArgs(keys []string) []any {
+	args := make([]any, len(keys))
+	for i, k := range keys {
+		args[i] = k
+	}
+	return args
+}
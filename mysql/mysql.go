@@ -1,38 +1,59 @@
 package mysql
 
 import (
+	"context"
+	"crypto/tls"
 	gosql "database/sql"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 
 	// Usually a blank import is enough as it calls the package's init() function and loads the driver,
 	// but we'll use the package's ParseDNS() function so we make this an actual import.
 	gosqldriver "github.com/go-sql-driver/mysql"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/sql"
 )
 
 const defaultDBname = "gokv"
-const keyLength = "255"
 
 // It's a code smell to work with a hard coded number,
 // but the error doesn't seem to be defined as constant or variable
 // in neither of the two packages (database/sql and github.com/go-sql-driver/mysql).
 const errDBnotFound = 1049
 
+// Counter for the names under which the TLSConfig of each client is registered with the driver.
+var tlsConfigCounter uint64
+
 // Client is a gokv.Store implementation for MySQL.
 type Client struct {
 	c *sql.Client
+	// For building the batch statements, which depend on the number of key-value pairs.
+	tableName  string
+	timestamps bool
+	keyLength  int
+	// Name of the TLS config that was registered with the driver, "" if none was registered.
+	tlsConfigName string
 }
 
 // Set stores the given value for the given key.
 // Values are automatically marshalled to JSON or gob (depending on the configuration).
-// The length of the key must not exceed 255 characters.
+// The length of the key must not exceed the KeyColumnLength (255 characters by default).
 // The key must not be "" and the value must not be nil.
 func (c Client) Set(k string, v any) error {
 	// It's tempting to remove this "wrapper" method
 	// and just use *sql.Client as embedded field,
 	// But we need this explicit method for a different GoDoc
-	// (255 character limit).
+	// and the key length check.
+	if err := c.checkKeyLength(k); err != nil {
+		return err
+	}
 	return c.c.Set(k, v)
 }
 
@@ -41,31 +62,75 @@ func (c Client) Set(k string, v any) error {
 // the automatic unmarshalling can populate the fields of the object
 // that v points to with the values of the retrieved object's values.
 // If no value is found it returns (false, nil).
-// The length of the key must not exceed 255 characters.
+// The length of the key must not exceed the KeyColumnLength (255 characters by default).
 // The key must not be "" and the pointer must not be nil.
 func (c Client) Get(k string, v any) (found bool, err error) {
 	// It's tempting to remove this "wrapper" method
 	// and just use *sql.Client as embedded field,
 	// But we need this explicit method for a different GoDoc
-	// (255 character limit).
+	// and the key length check.
+	if err := c.checkKeyLength(k); err != nil {
+		return false, err
+	}
 	return c.c.Get(k, v)
 }
 
 // Delete deletes the stored value for the given key.
 // Deleting a non-existing key-value pair does NOT lead to an error.
-// The length of the key must not exceed 255 characters.
+// The length of the key must not exceed the KeyColumnLength (255 characters by default).
 // The key must not be "".
 func (c Client) Delete(k string) error {
 	// It's tempting to remove this "wrapper" method
 	// and just use *sql.Client as embedded field,
 	// But we need this explicit method for a different GoDoc
-	// (255 character limit).
+	// and the key length check.
+	if err := c.checkKeyLength(k); err != nil {
+		return err
+	}
 	return c.c.Delete(k)
 }
 
+// DeleteOlderThan deletes all key-value pairs that haven't been updated for at least the given duration
+// and returns the number of deleted key-value pairs.
+// It can only be used when the client was created with the Timestamps option.
+func (c Client) DeleteOlderThan(d time.Duration) (deleted int, err error) {
+	return c.c.DeleteOlderThan(d)
+}
+
+// ForEach calls fn for each key that starts with the given prefix.
+// If fn returns an error, the iteration stops and the error is returned.
+func (c Client) ForEach(prefix string, fn func(k string) error) error {
+	return c.c.ForEach(prefix, fn)
+}
+
+// Ping checks if the MySQL server is reachable.
+// It establishes a connection if necessary.
+func (c Client) Ping(ctx context.Context) error {
+	return c.c.Ping(ctx)
+}
+
+// Kind returns gokv.KindMySQL.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindMySQL
+}
+
+// checkKeyLength returns an error if the key is longer than the key column,
+// which MySQL would otherwise either reject with a less clear error or, without strict mode, truncate.
+func (c Client) checkKeyLength(k string) error {
+	if utf8.RuneCountInString(k) > c.keyLength {
+		return fmt.Errorf("The key must not exceed %v characters, which is the configured KeyColumnLength", c.keyLength)
+	}
+	return nil
+}
+
 // Close closes the client.
 // It must be called to return all open connections to the connection pool and to release any open resources.
+// If the DB was passed via the options, only the prepared statements are closed,
+// because the DB is owned by the package user.
 func (c Client) Close() error {
+	if c.tlsConfigName != "" {
+		gosqldriver.DeregisterTLSConfig(c.tlsConfigName)
+	}
 	return c.c.Close()
 }
 
@@ -83,7 +148,38 @@ type Options struct {
 	// Optional ("root@/gokv" by default, which will connect to "127.0.0.1:3306"
 	// and requires the server to be configured with MYSQL_ALLOW_EMPTY_PASSWORD=true,
 	// which should only be done in local test environments, if at all).
+	// Must not be set together with the structured connection options below
+	// (Host, Port, User, Password, Database, TLSConfig and TLSMode),
+	// which are an alternative for building the connection string from separate values.
 	DataSourceName string
+	// Host of the MySQL server.
+	// Optional ("127.0.0.1" by default).
+	Host string
+	// Port of the MySQL server.
+	// Optional (3306 by default).
+	Port int
+	// User for connecting to the MySQL server.
+	// Optional ("root" by default).
+	User string
+	// Password of the user.
+	// Optional ("" by default).
+	Password string
+	// Name of the database.
+	// If it doesn't exist yet, an attempt will be made to create it.
+	// Optional ("gokv" by default).
+	Database string
+	// TLS configuration for the connection, for example with the RootCAs for a server with a self-signed certificate
+	// or with Certificates for client certificate authentication.
+	// It's registered with the driver under a unique name, which is deregistered again when the client is closed.
+	// Must not be set together with TLSMode.
+	// Optional (nil by default).
+	TLSConfig *tls.Config
+	// TLS mode for the connection when no TLSConfig is set.
+	// "true" for TLS with verification of the server certificate,
+	// "skip-verify" for TLS without verification,
+	// "preferred" for TLS only if the server supports it, and "false" for no TLS.
+	// Optional ("" by default, which leads to the driver's default, which is "false").
+	TLSMode string
 	// Name of the table in which the key-value pairs are stored.
 	// Optional ("Item" by default).
 	TableName string
@@ -91,17 +187,41 @@ type Options struct {
 	// -1 for no limit. 0 will lead to the default value (100) being set.
 	// Optional (100 by default).
 	MaxOpenConnections int
+	// An already opened DB, for example to share one connection pool between gokv and other code.
+	// The database must exist, only the table is created if it doesn't exist yet.
+	// The client doesn't close the DB in this case, that's up to the caller.
+	// DataSourceName and MaxOpenConnections are ignored if DB is set.
+	// Optional (nil by default).
+	DB *gosql.DB
+	// Maintain "created_at" and "updated_at" columns, which are set by the server on insert and update.
+	// Required for DeleteOlderThan().
+	// The columns are only added when the table is created,
+	// so an existing table without them leads to an error.
+	// Optional (false by default).
+	Timestamps bool
+	// Maximum length of the keys in characters, which is the length of the VARCHAR column for the keys.
+	// The column is the primary key, so its maximum length in bytes is limited by InnoDB's index key prefix limit:
+	// 3072 bytes with the DYNAMIC or COMPRESSED row format (the default since MySQL 5.7.9)
+	// and 767 bytes with the REDUNDANT or COMPACT row format.
+	// With the utf8mb4 character set (the default since MySQL 8.0) each character takes up to 4 bytes,
+	// so the maximum is 768 characters, and 191 characters with the older row formats.
+	// With a longer column the table creation fails.
+	// The length is only used when the table is created, so it must match the column of an existing table.
+	// Optional (255 by default).
+	KeyColumnLength int
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// DataSourceName: "root@/gokv", TableName: "Item", MaxOpenConnections: 100, Codec: encoding.JSON
+// DataSourceName: "root@/gokv", Host: "", Port: 0, User: "", Password: "", Database: "", TLSConfig: nil, TLSMode: "",
+// TableName: "Item", MaxOpenConnections: 100, DB: nil, KeyColumnLength: 255, Codec: encoding.JSON
 var DefaultOptions = Options{
 	DataSourceName:     "root@/" + defaultDBname,
 	TableName:          "Item",
 	MaxOpenConnections: 100,
+	KeyColumnLength:    255,
 	Codec:              encoding.JSON,
 }
 
@@ -111,8 +231,33 @@ var DefaultOptions = Options{
 func NewClient(options Options) (Client, error) {
 	result := Client{}
 
+	// Precondition check
+	if options.KeyColumnLength < 0 {
+		return result, errors.New("The KeyColumnLength must not be negative")
+	}
+	structured := options.Host != "" || options.Port != 0 || options.User != "" || options.Password != "" ||
+		options.Database != "" || options.TLSConfig != nil || options.TLSMode != ""
+	if structured && options.DataSourceName != "" {
+		return result, errors.New("The DataSourceName must not be set together with the structured connection options")
+	}
+	if options.TLSConfig != nil && options.TLSMode != "" {
+		return result, errors.New("The TLSConfig and TLSMode must not be set together")
+	}
+	if options.Port < 0 {
+		return result, errors.New("The Port must not be negative")
+	}
+
 	// Set default values
-	if options.DataSourceName == "" {
+	if structured && options.DB == nil {
+		if options.TLSConfig != nil {
+			result.tlsConfigName = "gokv-" + strconv.FormatUint(atomic.AddUint64(&tlsConfigCounter, 1), 10)
+			err := gosqldriver.RegisterTLSConfig(result.tlsConfigName, options.TLSConfig)
+			if err != nil {
+				return result, err
+			}
+		}
+		options.DataSourceName = dataSourceName(options, result.tlsConfigName)
+	} else if options.DataSourceName == "" {
 		options.DataSourceName = DefaultOptions.DataSourceName
 	}
 	if options.TableName == "" {
@@ -123,19 +268,133 @@ func NewClient(options Options) (Client, error) {
 	} else if options.MaxOpenConnections == -1 {
 		options.MaxOpenConnections = 0 // 0 actually leads to the MySQL driver using no connection limit.
 	}
+	if options.KeyColumnLength == 0 {
+		options.KeyColumnLength = DefaultOptions.KeyColumnLength
+	}
 	if options.Codec == nil {
 		options.Codec = DefaultOptions.Codec
 	}
 
-	db, err := gosql.Open("mysql", options.DataSourceName)
+	db := options.DB
+	if db == nil {
+		var err error
+		db, err = openDB(options)
+		if err != nil {
+			if result.tlsConfigName != "" {
+				gosqldriver.DeregisterTLSConfig(result.tlsConfigName)
+			}
+			return result, err
+		}
+	}
+
+	// Create table if it doesn't exist yet.
+	//
+	// TEXT can't be used as primary key, so VARCHAR is used.
+	// There's no hard character limit for the primary key, but a byte limit,
+	// which depends on the row format and character set. See the KeyColumnLength option.
+	columns := "k VARCHAR(" + strconv.Itoa(options.KeyColumnLength) + ") PRIMARY KEY, v BLOB NOT NULL"
+	if options.Timestamps {
+		columns += ", created_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6), updated_at TIMESTAMP(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6)"
+	}
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + options.TableName + " (" + columns + ")")
 	if err != nil {
 		return result, err
 	}
 
-	cfg, err := gosqldriver.ParseDSN(options.DataSourceName)
+	// Create prepared statements that will be reused for every Set()/Get() operation.
+	// Note: Prepared statements are handled differently from other programming languages in Go,
+	// see: http://go-database-sql.org/prepared.html.
+	// TODO: Prepared statements might prevent the use of other databases that are compatible with the MySQL protocol.
+	upsertQuery := "INSERT INTO " + options.TableName + " (k, v) VALUES (?, ?) ON DUPLICATE KEY UPDATE v = VALUES(v)"
+	if options.Timestamps {
+		upsertQuery += ", updated_at = CURRENT_TIMESTAMP(6)"
+	}
+	upsertStmt, err := db.Prepare(upsertQuery)
 	if err != nil {
 		return result, err
 	}
+	getStmt, err := db.Prepare("SELECT v FROM " + options.TableName + " WHERE k = ?")
+	if err != nil {
+		return result, err
+	}
+	deleteStmt, err := db.Prepare("DELETE FROM " + options.TableName + " where k = ?")
+	if err != nil {
+		return result, err
+	}
+	keysStmt, err := db.Prepare("SELECT k FROM " + options.TableName + " WHERE k LIKE ? ESCAPE '!'")
+	if err != nil {
+		return result, err
+	}
+	var deleteOlderThanStmt *gosql.Stmt
+	if options.Timestamps {
+		deleteOlderThanStmt, err = db.Prepare("DELETE FROM " + options.TableName + " WHERE updated_at < CURRENT_TIMESTAMP(6) - INTERVAL ? MICROSECOND")
+		if err != nil {
+			return result, err
+		}
+	}
+
+	c := sql.Client{
+		C:                   db,
+		UpsertStmt:          upsertStmt,
+		GetStmt:             getStmt,
+		DeleteStmt:          deleteStmt,
+		DeleteOlderThanStmt: deleteOlderThanStmt,
+		KeysStmt:            keysStmt,
+		Codec:               options.Codec,
+		SharedDB:            options.DB != nil,
+	}
+
+	result.c = &c
+	result.tableName = options.TableName
+	result.timestamps = options.Timestamps
+	result.keyLength = options.KeyColumnLength
+
+	return result, nil
+}
+
+// dataSourceName builds the DataSourceName from the structured connection options.
+// tlsConfigName is the name under which options.TLSConfig was registered with the driver,
+// "" if it's not set.
+func dataSourceName(options Options, tlsConfigName string) string {
+	host := options.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	port := options.Port
+	if port == 0 {
+		port = 3306
+	}
+	user := options.User
+	if user == "" {
+		user = "root"
+	}
+
+	cfg := gosqldriver.NewConfig()
+	cfg.User = user
+	cfg.Passwd = options.Password
+	cfg.Net = "tcp"
+	cfg.Addr = net.JoinHostPort(host, strconv.Itoa(port))
+	cfg.DBName = options.Database
+	if tlsConfigName != "" {
+		cfg.TLSConfig = tlsConfigName
+	} else {
+		cfg.TLSConfig = options.TLSMode
+	}
+	return cfg.FormatDSN()
+}
+
+// openDB opens the DB with the DataSourceName
+// and creates the database if it doesn't exist yet.
+func openDB(options Options) (*gosql.DB, error) {
+	db, err := gosql.Open("mysql", options.DataSourceName)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := gosqldriver.ParseDSN(options.DataSourceName)
+	if err != nil {
+		return nil, err
+	}
 
 	err = db.Ping()
 	if err != nil {
@@ -145,20 +404,20 @@ func NewClient(options Options) (Client, error) {
 			if driverErr.Number == errDBnotFound {
 				err = createDB(cfg, db)
 				if err != nil {
-					return result, nil
+					return nil, err
 				}
 			} else {
-				return result, err
+				return nil, err
 			}
 		} else {
-			return result, err
+			return nil, err
 		}
 	} else if cfg.DBName == "" {
 		// Ping() was successful, but in case the package user didn't include a database name
 		// in the DataSourceName, we must now attempt to create the database.
 		newDB, err := createDefaultDB(db, cfg)
 		if err != nil {
-			return result, err
+			return nil, err
 		}
 		// Also, we must replace the current value that the db pointer points to by the new db,
 		// because calling "USE" on a database only works for the single connection that's used
@@ -171,48 +430,7 @@ func NewClient(options Options) (Client, error) {
 	// This prevents "Error 1040: Too many connections", which otherwise occurs for example with 500 concurrent goroutines.
 	db.SetMaxOpenConns(options.MaxOpenConnections)
 
-	// Create table if it doesn't exist yet.
-	//
-	// TEXT can't be used as primary key. VARCHAR default length is 255.
-	// VARCHAR with more characters are converted for example to SMALLTEXT,
-	// which again can't be used as primary key.
-	// TODO: Maybe the 255 character limit only applies to MySQL < 5.x?
-	// If yes, allow the user to define a key length via the options.
-	// Also: There's no hard character limit, but byte limit.
-	// So the 255 characters come from 255 utf8mb3 characters.
-	_, err = db.Exec("CREATE TABLE IF NOT EXISTS " + options.TableName + " (k VARCHAR(" + keyLength + ") PRIMARY KEY, v BLOB NOT NULL)")
-	if err != nil {
-		return result, err
-	}
-
-	// Create prepared statements that will be reused for every Set()/Get() operation.
-	// Note: Prepared statements are handled differently from other programming languages in Go,
-	// see: http://go-database-sql.org/prepared.html.
-	// TODO: Prepared statements might prevent the use of other databases that are compatible with the MySQL protocol.
-	upsertStmt, err := db.Prepare("INSERT INTO " + options.TableName + " (k, v) VALUES (?, ?) ON DUPLICATE KEY UPDATE v = VALUES(v)")
-	if err != nil {
-		return result, err
-	}
-	getStmt, err := db.Prepare("SELECT v FROM " + options.TableName + " WHERE k = ?")
-	if err != nil {
-		return result, err
-	}
-	deleteStmt, err := db.Prepare("DELETE FROM " + options.TableName + " where k = ?")
-	if err != nil {
-		return result, err
-	}
-
-	c := sql.Client{
-		C:          db,
-		UpsertStmt: upsertStmt,
-		GetStmt:    getStmt,
-		DeleteStmt: deleteStmt,
-		Codec:      options.Codec,
-	}
-
-	result.c = &c
-
-	return result, nil
+	return db, nil
 }
 
 // createDB creates a DB when the DB name is given in the config.
@@ -1,10 +1,13 @@
 package mysql_test
 
 import (
+	"crypto/tls"
 	"database/sql"
 	"log"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 
@@ -58,6 +61,28 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// For some reason this test fails in GitHub Actions, but not locally.
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		t.Skip("Skipping test in GitHub Actions. Run this locally before a release!")
+	}
+
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the MySQL client.
 func TestClientConcurrent(t *testing.T) {
 	// For some reason this test fails in GitHub Actions, but not locally.
@@ -193,6 +218,13 @@ func TestDBcreation(t *testing.T) {
 	}
 }
 
+// TestPing tests if the server is reachable via Ping.
+func TestPing(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestPinger(client, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	// For some reason this test fails in GitHub Actions, but not locally.
@@ -237,6 +269,288 @@ func TestDefaultMaxOpenConnections(t *testing.T) {
 	}
 }
 
+// TestBatch tests if the gokv.BatchStore methods work properly.
+func TestBatch(t *testing.T) {
+	// For some reason this test fails in GitHub Actions, but not locally.
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		t.Skip("Skipping test in GitHub Actions. Run this locally before a release!")
+	}
+
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestBatchStore(client, t)
+}
+
+// TestIterator tests if listing the keys works properly.
+func TestIterator(t *testing.T) {
+	// For some reason this test fails in GitHub Actions, but not locally.
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		t.Skip("Skipping test in GitHub Actions. Run this locally before a release!")
+	}
+
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestIterator(client, t)
+}
+
+// TestKeyColumnLength tests that keys up to the configured length can be used
+// and that longer keys are rejected with an error before they reach the server.
+func TestKeyColumnLength(t *testing.T) {
+	// For some reason the MySQL tests fail in GitHub Actions, but not locally.
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		t.Skip("Skipping test in GitHub Actions. Run this locally before a release!")
+	}
+
+	options := mysql.Options{
+		TableName:       "ItemWithLongKeys",
+		KeyColumnLength: 500,
+	}
+	client, err := mysql.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	// Multi-byte characters count as one character each
+	key := strings.Repeat("ä", 500)
+	err = client.Set(key, "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := ""
+	found, err := client.Get(key, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual != "bar" {
+		t.Errorf("Expected %q to be found, but found was %v and the value %q", "bar", found, actual)
+	}
+	err = client.Delete(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tooLongKey := key + "a"
+	err = client.Set(tooLongKey, "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = client.Get(tooLongKey, &actual)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = client.Delete(tooLongKey)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = client.SetMany(map[string]any{tooLongKey: "bar"})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// The default length is 255 characters
+	defaultClient := createClient(t, encoding.JSON)
+	defer defaultClient.Close()
+	err = defaultClient.Set(strings.Repeat("a", 256), "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	_, err = mysql.NewClient(mysql.Options{KeyColumnLength: -1})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestSharedDB tests if a DB that's passed via the options can be used
+// and is still usable after the client is closed.
+func TestSharedDB(t *testing.T) {
+	// For some reason the MySQL tests fail in GitHub Actions, but not locally.
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		t.Skip("Skipping test in GitHub Actions. Run this locally before a release!")
+	}
+
+	db, err := sql.Open("mysql", mysql.DefaultOptions.DataSourceName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	client, err := mysql.NewClient(mysql.Options{
+		TableName: "ItemSharedDB",
+		DB:        db,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	test.TestStore(client, t)
+
+	err = client.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Ping()
+	if err != nil {
+		t.Errorf("Expected the DB to still be usable after closing the client, but got: %v", err)
+	}
+}
+
+// TestStructuredOptions tests if a client can be created from the structured connection options
+// instead of a DataSourceName.
+func TestStructuredOptions(t *testing.T) {
+	// For some reason the MySQL tests fail in GitHub Actions, but not locally.
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		t.Skip("Skipping test in GitHub Actions. Run this locally before a release!")
+	}
+
+	t.Run("plain", func(t *testing.T) {
+		client, err := mysql.NewClient(mysql.Options{
+			Host:      "localhost",
+			Port:      3306,
+			User:      "root",
+			Database:  "gokv_structured",
+			TableName: "ItemStructured",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		test.TestStore(client, t)
+	})
+
+	t.Run("TLS", func(t *testing.T) {
+		if !checkTLSConnection() {
+			t.Skip("No connection to a MySQL server with TLS could be established. Probably not running in a proper test environment.")
+		}
+
+		client, err := mysql.NewClient(mysql.Options{
+			Host: "localhost",
+			// The official Docker image uses a self-signed certificate
+			TLSConfig: &tls.Config{InsecureSkipVerify: true},
+			TableName: "ItemTLS",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer client.Close()
+		test.TestStore(client, t)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := mysql.NewClient(mysql.Options{
+			DataSourceName: mysql.DefaultOptions.DataSourceName,
+			Host:           "localhost",
+		})
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		_, err = mysql.NewClient(mysql.Options{
+			TLSConfig: &tls.Config{},
+			TLSMode:   "skip-verify",
+		})
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		_, err = mysql.NewClient(mysql.Options{
+			Port: -1,
+		})
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	})
+}
+
+// TestTimestamps tests that the updated_at column changes on a repeated Set()
+// and that DeleteOlderThan() only deletes aged key-value pairs.
+func TestTimestamps(t *testing.T) {
+	// For some reason this test fails in GitHub Actions, but not locally.
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		t.Skip("Skipping test in GitHub Actions. Run this locally before a release!")
+	}
+
+	options := mysql.Options{
+		TableName:  "ItemWithTimestamps",
+		Timestamps: true,
+	}
+	client, err := mysql.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	db, err := sql.Open("mysql", mysql.DefaultOptions.DataSourceName+"?parseTime=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	getUpdatedAt := func(k string) time.Time {
+		var updatedAt time.Time
+		err := db.QueryRow("SELECT updated_at FROM "+options.TableName+" WHERE k = ?", k).Scan(&updatedAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return updatedAt
+	}
+
+	// Clean up from previous test runs
+	for _, k := range []string{"old", "new"} {
+		err = client.Delete(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = client.Set("old", "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = client.Set("new", "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := getUpdatedAt("new")
+	time.Sleep(100 * time.Millisecond)
+	err = client.Set("new", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	after := getUpdatedAt("new")
+	if !after.After(before) {
+		t.Errorf("Expected updated_at to change after a repeated Set(), but it was %v before and %v after", before, after)
+	}
+
+	// Only "old" hasn't been updated for the last 50ms
+	deleted, err := client.DeleteOlderThan(50 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 deleted key-value pair, but was %v", deleted)
+	}
+	found, err := client.Get("old", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+	found, err = client.Get("new", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+
+	// Without timestamp columns DeleteOlderThan() must fail
+	client2 := createClient(t, encoding.JSON)
+	defer client2.Close()
+	_, err = client2.DeleteOlderThan(time.Second)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
 // checkConnection returns true if a connection could be made, false otherwise.
 func checkConnection() bool {
 	db, err := sql.Open("mysql", "root@/")
@@ -255,6 +569,24 @@ func checkConnection() bool {
 	return true
 }
 
+// checkTLSConnection returns true if a connection with TLS could be made, false otherwise.
+func checkTLSConnection() bool {
+	db, err := sql.Open("mysql", "root@tcp(localhost:3306)/?tls=skip-verify")
+	if err != nil {
+		log.Printf("An error occurred during testing the TLS connection to the server: %v\n", err)
+		return false
+	}
+	defer db.Close()
+
+	err = db.Ping()
+	if err != nil {
+		log.Printf("An error occurred during testing the TLS connection to the server: %v\n", err)
+		return false
+	}
+
+	return true
+}
+
 func createClient(t *testing.T, codec encoding.Codec) mysql.Client {
 	options := mysql.Options{
 		Codec: codec,
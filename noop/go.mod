@@ -6,3 +6,5 @@ require (
 	github.com/philippgille/gokv v0.7.0
 	github.com/philippgille/gokv/util v0.7.0
 )
+
+replace github.com/philippgille/gokv => ../
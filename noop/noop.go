@@ -1,12 +1,31 @@
 package noop
 
-import "github.com/philippgille/gokv/util"
+import (
+	"sync/atomic"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
 
 // Store is a gokv.Store implementation that does nothing except validate the arguments if applicable.
-type Store struct{}
+// A store that's created with NewRecordingStore() additionally counts the calls,
+// which makes it useful as a test double.
+type Store struct {
+	// nil if the store doesn't record the calls
+	counts *counts
+}
+
+type counts struct {
+	set    atomic.Int64
+	get    atomic.Int64
+	delete atomic.Int64
+}
 
 // Set pretends if stores the key. Always return nil error unless the key or value are invalid.
 func (s Store) Set(k string, v any) error {
+	if s.counts != nil {
+		s.counts.set.Add(1)
+	}
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return err
 	}
@@ -16,6 +35,9 @@ func (s Store) Set(k string, v any) error {
 
 // Get pretends it fetches the key. Always return not found and nil error unless the key or value are invalid.
 func (s Store) Get(k string, v any) (found bool, err error) {
+	if s.counts != nil {
+		s.counts.get.Add(1)
+	}
 	if err := util.CheckKeyAndValue(k, v); err != nil {
 		return false, err
 	}
@@ -25,6 +47,9 @@ func (s Store) Get(k string, v any) (found bool, err error) {
 
 // Delete pretends it deletes the key. Always return nil error unless the key is invalid.
 func (s Store) Delete(k string) error {
+	if s.counts != nil {
+		s.counts.delete.Add(1)
+	}
 	if err := util.CheckKey(k); err != nil {
 		return err
 	}
@@ -32,6 +57,44 @@ func (s Store) Delete(k string) error {
 	return nil
 }
 
+// SetCount returns the number of Set calls, including the ones with invalid arguments.
+// It's always 0 unless the store was created with NewRecordingStore().
+func (s Store) SetCount() int64 {
+	if s.counts == nil {
+		return 0
+	}
+	return s.counts.set.Load()
+}
+
+// GetCount returns the number of Get calls, including the ones with invalid arguments.
+// It's always 0 unless the store was created with NewRecordingStore().
+func (s Store) GetCount() int64 {
+	if s.counts == nil {
+		return 0
+	}
+	return s.counts.get.Load()
+}
+
+// DeleteCount returns the number of Delete calls, including the ones with invalid arguments.
+// It's always 0 unless the store was created with NewRecordingStore().
+func (s Store) DeleteCount() int64 {
+	if s.counts == nil {
+		return 0
+	}
+	return s.counts.delete.Load()
+}
+
+// ForEach never calls fn, because the store doesn't keep any keys,
+// just like Get never finds a value. It always returns nil.
+func (s Store) ForEach(prefix string, fn func(k string) error) error {
+	return nil
+}
+
+// Kind returns gokv.KindNoop.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindNoop
+}
+
 // Close pretends it closes the store. Always return nil error.
 func (s Store) Close() error {
 	return nil
@@ -41,3 +104,12 @@ func (s Store) Close() error {
 func NewStore() Store {
 	return Store{}
 }
+
+// NewRecordingStore creates a new noop Store that counts the calls of Set, Get and Delete,
+// so that tests can assert how often the code under test called them.
+// The counts are shared between copies of the store.
+func NewRecordingStore() Store {
+	return Store{
+		counts: new(counts),
+	}
+}
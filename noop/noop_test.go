@@ -35,6 +35,61 @@ func TestNop(t *testing.T) {
 	}
 }
 
+func TestRecording(t *testing.T) {
+	t.Parallel()
+
+	s := noop.NewRecordingStore()
+
+	for i := 0; i < 3; i++ {
+		if err := s.Set("foo", 1); err != nil {
+			t.Error(err)
+		}
+	}
+	var v int
+	if _, err := s.Get("foo", &v); err != nil {
+		t.Error(err)
+	}
+	// Calls with invalid arguments are counted as well
+	_ = s.Delete("")
+	_ = s.Delete("foo")
+
+	if s.SetCount() != 3 {
+		t.Errorf("Expected 3 Set calls, but was %v", s.SetCount())
+	}
+	if s.GetCount() != 1 {
+		t.Errorf("Expected 1 Get call, but was %v", s.GetCount())
+	}
+	if s.DeleteCount() != 2 {
+		t.Errorf("Expected 2 Delete calls, but was %v", s.DeleteCount())
+	}
+
+	// The default store doesn't count
+	d := noop.NewStore()
+	if err := d.Set("foo", 1); err != nil {
+		t.Error(err)
+	}
+	if d.SetCount() != 0 {
+		t.Errorf("Expected 0 Set calls, but was %v", d.SetCount())
+	}
+}
+
+func TestIterator(t *testing.T) {
+	t.Parallel()
+
+	var s gokv.Store = noop.NewStore()
+	if err := s.Set("foo", 1); err != nil {
+		t.Error(err)
+	}
+
+	keys, err := gokv.Keys(s, "")
+	if err != nil {
+		t.Error(err)
+	}
+	if len(keys) != 0 {
+		t.Errorf("Expected no keys, but got %v", keys)
+	}
+}
+
 func TestInputValidation(t *testing.T) {
 	t.Parallel()
 
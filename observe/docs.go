@@ -0,0 +1,47 @@
+/*
+Package observe contains a `gokv.Store` wrapper that measures the number, errors and duration of the store's operations.
+
+The time that's spent in the codec can be measured separately from the time that's spent in the backend.
+For that the codec that's passed to the inner store must be wrapped with the Metrics object that's also passed to the observe store:
+
+	metrics := observe.NewMetrics()
+	inner, err := redis.NewClient(redis.Options{
+		Codec: metrics.WrapCodec(encoding.JSON),
+	})
+	// ...
+	store, err := observe.NewStore(inner, observe.Options{
+		Metrics: metrics,
+	})
+	// ...
+	getStats := store.Stats()[observe.OpGet]
+	fmt.Println(getStats.CodecDuration, getStats.BackendDuration())
+
+When an application uses multiple stores, their stats can be combined in a StatsRegistry.
+Stores with a Name register themselves when the registry is passed in the options:
+
+	registry := observe.NewStatsRegistry()
+	users, err := observe.NewStore(usersInner, observe.Options{
+		Name:     "users",
+		Registry: registry,
+	})
+	// ...
+	for name, stats := range registry.Snapshot() {
+		fmt.Println(name, stats[observe.OpGet].Count)
+	}
+
+The stats can be exposed for scraping in the Prometheus text exposition format without depending on the Prometheus client library:
+
+	http.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", observe.ContentType)
+		_ = registry.WriteMetrics(w)
+	})
+
+For quick introspection without any setup, the stats and the last error can be published via the standard library's expvar package,
+which makes them visible at /debug/vars when the expvar handler is served:
+
+	store, err := observe.NewStore(inner, observe.Options{
+		Name:          "users",
+		PublishExpvar: true,
+	})
+*/
+package observe
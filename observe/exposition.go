@@ -0,0 +1,107 @@
+package observe
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the HTTP Content-Type of the text that WriteMetrics writes,
+// which is the Prometheus text exposition format that's also accepted by OpenMetrics scrapers.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// metricFamily is one metric that's written per store and operation.
+type metricFamily struct {
+	name  string
+	help  string
+	value func(OperationStats) string
+}
+
+var metricFamilies = []metricFamily{
+	{
+		name:  "gokv_operations_total",
+		help:  "Number of calls of the store operation.",
+		value: func(s OperationStats) string { return strconv.FormatInt(s.Count, 10) },
+	},
+	{
+		name:  "gokv_operation_errors_total",
+		help:  "Number of calls of the store operation that returned an error.",
+		value: func(s OperationStats) string { return strconv.FormatInt(s.Errors, 10) },
+	},
+	{
+		name:  "gokv_operation_duration_seconds_total",
+		help:  "Total duration of all calls of the store operation.",
+		value: func(s OperationStats) string { return formatSeconds(s.Duration) },
+	},
+	{
+		name:  "gokv_operation_codec_duration_seconds_total",
+		help:  "Part of the total duration of the store operation that was spent in the codec.",
+		value: func(s OperationStats) string { return formatSeconds(s.CodecDuration) },
+	},
+}
+
+// WriteMetrics writes the collected stats in the Prometheus text exposition format,
+// so they can be scraped without depending on the Prometheus client library.
+// Each stat is a counter with an "operation" label.
+func (m *Metrics) WriteMetrics(w io.Writer) error {
+	return writeMetrics(w, map[string]map[string]OperationStats{"": m.Stats()})
+}
+
+// WriteMetrics writes the collected stats in the Prometheus text exposition format,
+// so they can be scraped without depending on the Prometheus client library.
+// Each stat is a counter with an "operation" label, plus a "store" label if the store has a Name.
+func (s Store) WriteMetrics(w io.Writer) error {
+	return writeMetrics(w, map[string]map[string]OperationStats{s.name: s.metrics.Stats()})
+}
+
+// WriteMetrics writes the stats of all registered stores in the Prometheus text exposition format,
+// so they can be scraped without depending on the Prometheus client library.
+// Each stat is a counter with a "store" and an "operation" label.
+func (r *StatsRegistry) WriteMetrics(w io.Writer) error {
+	return writeMetrics(w, r.Snapshot())
+}
+
+// writeMetrics writes the stats per store name and operation name.
+// The store label is left out for the store name "".
+func writeMetrics(w io.Writer, snapshot map[string]map[string]OperationStats) error {
+	names := make([]string, 0, len(snapshot))
+	for name := range snapshot {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	for _, family := range metricFamilies {
+		sb.WriteString("# HELP " + family.name + " " + family.help + "\n")
+		sb.WriteString("# TYPE " + family.name + " counter\n")
+		for _, name := range names {
+			stats := snapshot[name]
+			ops := make([]string, 0, len(stats))
+			for op := range stats {
+				ops = append(ops, op)
+			}
+			sort.Strings(ops)
+			for _, op := range ops {
+				sb.WriteString(family.name + "{")
+				if name != "" {
+					sb.WriteString(`store="` + escapeLabelValue(name) + `",`)
+				}
+				sb.WriteString(`operation="` + escapeLabelValue(op) + `"} `)
+				sb.WriteString(family.value(stats[op]) + "\n")
+			}
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
+
+// escapeLabelValue escapes backslashes, double quotes and line feeds, as required by the exposition format.
+func escapeLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,90 @@
+package observe
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"sync"
+	"sync/atomic"
+)
+
+// The variables that were published by observe stores, by name.
+// expvar doesn't allow removing or replacing a variable,
+// so a new store with the name of a closed store takes over its variable.
+var (
+	expvarLock sync.Mutex
+	expvarVars = make(map[string]*expvarVar)
+)
+
+// expvarVar is an expvar.Var that shows the stats of the store that currently owns the name.
+type expvarVar struct {
+	// nil when the store was closed
+	metrics atomic.Pointer[Metrics]
+}
+
+// String returns the stats as JSON, or an empty JSON object when the store was closed.
+func (v *expvarVar) String() string {
+	m := v.metrics.Load()
+	if m == nil {
+		return "{}"
+	}
+	return m.String()
+}
+
+// expvarStats is the JSON representation of the stats for expvar.
+type expvarStats struct {
+	Operations map[string]expvarOperationStats `json:"operations"`
+	LastError  string                          `json:"last_error,omitempty"`
+}
+
+type expvarOperationStats struct {
+	Count                int64   `json:"count"`
+	Errors               int64   `json:"errors"`
+	DurationSeconds      float64 `json:"duration_seconds"`
+	CodecDurationSeconds float64 `json:"codec_duration_seconds"`
+}
+
+// String returns the collected stats and the last error as JSON, so Metrics implements expvar.Var
+// and can be published with expvar.Publish. For example:
+//
+//	{"operations":{"get":{"count":2,"errors":0,"duration_seconds":0.0012,"codec_duration_seconds":0}},"last_error":"set: ..."}
+func (m *Metrics) String() string {
+	result := expvarStats{
+		Operations: make(map[string]expvarOperationStats),
+	}
+	for op, stats := range m.Stats() {
+		result.Operations[op] = expvarOperationStats{
+			Count:                stats.Count,
+			Errors:               stats.Errors,
+			DurationSeconds:      stats.Duration.Seconds(),
+			CodecDurationSeconds: stats.CodecDuration.Seconds(),
+		}
+	}
+	if err := m.LastError(); err != nil {
+		result.LastError = err.Error()
+	}
+	// Marshalling maps of structs with numbers and strings can't fail
+	data, _ := json.Marshal(result)
+	return string(data)
+}
+
+// publishExpvar publishes the metrics under the given name,
+// or lets an existing variable of a closed store show them.
+func publishExpvar(name string, m *Metrics) (*expvarVar, error) {
+	expvarLock.Lock()
+	defer expvarLock.Unlock()
+	if v, ok := expvarVars[name]; ok {
+		if !v.metrics.CompareAndSwap(nil, m) {
+			return nil, errors.New("The expvar variable " + name + " is already published by another open store")
+		}
+		return v, nil
+	}
+	if expvar.Get(name) != nil {
+		return nil, errors.New("An expvar variable with the name " + name + " already exists")
+	}
+	v := &expvarVar{}
+	v.metrics.Store(m)
+	expvar.Publish(name, v)
+	expvarVars[name] = v
+	return v, nil
+}
@@ -0,0 +1,136 @@
+package observe
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/philippgille/gokv/encoding"
+)
+
+// Operation names that are used as keys in the stats.
+const (
+	OpSet    = "set"
+	OpGet    = "get"
+	OpDelete = "delete"
+)
+
+// OperationStats are the collected stats of one kind of operation.
+type OperationStats struct {
+	// Number of calls.
+	Count int64
+	// Number of calls that returned an error.
+	// A Get that doesn't find a value isn't an error.
+	Errors int64
+	// Total duration of all calls.
+	Duration time.Duration
+	// Part of Duration that was spent in the codec.
+	// Only measured when the inner store uses a codec that was wrapped with Metrics.WrapCodec.
+	CodecDuration time.Duration
+}
+
+// BackendDuration returns the part of Duration that was not spent in the codec,
+// which is mostly the time that was spent in the backend, including network I/O.
+func (s OperationStats) BackendDuration() time.Duration {
+	// The codec duration is recorded before the operation ends,
+	// so for a snapshot during an operation it can be ahead of the total duration.
+	if s.CodecDuration > s.Duration {
+		return 0
+	}
+	return s.Duration - s.CodecDuration
+}
+
+// Metrics collects the stats of the operations of a store.
+// Create it with NewMetrics.
+type Metrics struct {
+	lock  sync.Mutex
+	stats map[string]OperationStats
+	// The last error of any operation and the name of the operation.
+	lastErr   error
+	lastErrOp string
+}
+
+// NewMetrics creates a new Metrics object.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		stats: make(map[string]OperationStats),
+	}
+}
+
+// WrapCodec returns a codec that forwards all calls to the given codec and measures their duration.
+// Pass it to the inner store, so that the time spent in the codec is reported separately.
+// Marshal durations are attributed to OpSet and Unmarshal durations to OpGet.
+func (m *Metrics) WrapCodec(codec encoding.Codec) encoding.Codec {
+	return timedCodec{
+		inner:   codec,
+		metrics: m,
+	}
+}
+
+// Stats returns a snapshot of the collected stats per operation name.
+func (m *Metrics) Stats() map[string]OperationStats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	result := make(map[string]OperationStats, len(m.stats))
+	for op, stats := range m.stats {
+		result[op] = stats
+	}
+	return result
+}
+
+// LastError returns the last error that an operation returned, prefixed with the operation name,
+// or nil if no operation returned an error yet.
+func (m *Metrics) LastError() error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.lastErr == nil {
+		return nil
+	}
+	return fmt.Errorf("%v: %w", m.lastErrOp, m.lastErr)
+}
+
+// record adds a call of the operation to the stats.
+func (m *Metrics) record(op string, duration time.Duration, err error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stats := m.stats[op]
+	stats.Count++
+	if err != nil {
+		stats.Errors++
+		m.lastErr = err
+		m.lastErrOp = op
+	}
+	stats.Duration += duration
+	m.stats[op] = stats
+}
+
+// recordCodec adds time that was spent in the codec during the operation to the stats.
+func (m *Metrics) recordCodec(op string, duration time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	stats := m.stats[op]
+	stats.CodecDuration += duration
+	m.stats[op] = stats
+}
+
+// timedCodec is an encoding.Codec that measures the duration of another codec's calls.
+type timedCodec struct {
+	inner   encoding.Codec
+	metrics *Metrics
+}
+
+// Marshal encodes a Go value with the inner codec.
+func (c timedCodec) Marshal(v any) ([]byte, error) {
+	start := time.Now()
+	data, err := c.inner.Marshal(v)
+	c.metrics.recordCodec(OpSet, time.Since(start))
+	return data, err
+}
+
+// Unmarshal decodes a value with the inner codec.
+func (c timedCodec) Unmarshal(data []byte, v any) error {
+	start := time.Now()
+	err := c.inner.Unmarshal(data, v)
+	c.metrics.recordCodec(OpGet, time.Since(start))
+	return err
+}
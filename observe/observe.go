@@ -0,0 +1,149 @@
+package observe
+
+import (
+	"errors"
+	"time"
+
+	"github.com/philippgille/gokv"
+)
+
+// Store is a gokv.Store implementation that forwards all calls to another store
+// and measures the number, errors and duration of the calls.
+type Store struct {
+	inner   gokv.Store
+	metrics *Metrics
+	name    string
+	// nil if the store isn't registered
+	registry *StatsRegistry
+	// nil if the stats aren't published via expvar
+	expvarVar *expvarVar
+}
+
+// Set stores the given value for the given key in the inner store.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	start := time.Now()
+	err := s.inner.Set(k, v)
+	s.metrics.record(OpSet, time.Since(start), err)
+	return err
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	start := time.Now()
+	found, err = s.inner.Get(k, v)
+	s.metrics.record(OpGet, time.Since(start), err)
+	return found, err
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	start := time.Now()
+	err := s.inner.Delete(k)
+	s.metrics.record(OpDelete, time.Since(start), err)
+	return err
+}
+
+// Close closes the inner store and removes the store from the registry, if it was registered.
+// If the stats were published via expvar, the variable is kept, because expvar doesn't allow removing it,
+// but it shows an empty object until another store with the same name is created.
+// It's not measured.
+func (s Store) Close() error {
+	if s.registry != nil {
+		s.registry.Unregister(s.name)
+	}
+	if s.expvarVar != nil {
+		s.expvarVar.metrics.CompareAndSwap(s.metrics, nil)
+	}
+	return s.inner.Close()
+}
+
+// Stats returns a snapshot of the collected stats per operation name (OpSet, OpGet and OpDelete).
+// Operations that weren't called yet are missing.
+func (s Store) Stats() map[string]OperationStats {
+	return s.metrics.Stats()
+}
+
+// Metrics returns the Metrics object that collects the stats of the store.
+func (s Store) Metrics() *Metrics {
+	return s.metrics
+}
+
+// Name returns the name of the store, as set in Options.Name.
+func (s Store) Name() string {
+	return s.name
+}
+
+// Options are the options for the observe store.
+type Options struct {
+	// Collects the stats of the store.
+	// Pass the same object that was used to wrap the codec of the inner store,
+	// so that the time spent in the codec is reported separately.
+	// Optional (a new Metrics object by default).
+	Metrics *Metrics
+	// Name of the store, for example in a StatsRegistry.
+	// Optional ("" by default), but required when Registry is set or PublishExpvar is true.
+	Name string
+	// Registry that the store registers itself with under its Name.
+	// Optional (nil by default).
+	Registry *StatsRegistry
+	// Publish the stats and the last error via the standard library's expvar package under the Name,
+	// so they're visible at /debug/vars when the expvar handler is served, for example via http.DefaultServeMux.
+	// The Name must not be used by another expvar variable, except for one of a closed observe store.
+	// Optional (false by default).
+	PublishExpvar bool
+}
+
+// DefaultOptions is an Options object with default values.
+// Metrics: nil (a new Metrics object is created), Name: "", Registry: nil, PublishExpvar: false
+var DefaultOptions = Options{
+	// No need to set Metrics, Name, Registry or PublishExpvar because their zero values are fine.
+}
+
+// NewStore creates a new observe store that wraps the given store.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, options Options) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if inner == nil {
+		return result, errors.New("The inner store must not be nil")
+	}
+	if options.PublishExpvar && options.Name == "" {
+		return result, errors.New("The Name must be set when publishing the stats via expvar")
+	}
+
+	// Set default values
+	if options.Metrics == nil {
+		options.Metrics = NewMetrics()
+	}
+
+	result.inner = inner
+	result.metrics = options.Metrics
+	result.name = options.Name
+
+	if options.Registry != nil {
+		if err := options.Registry.Register(result); err != nil {
+			return Store{}, err
+		}
+		result.registry = options.Registry
+	}
+
+	if options.PublishExpvar {
+		v, err := publishExpvar(options.Name, result.metrics)
+		if err != nil {
+			if result.registry != nil {
+				result.registry.Unregister(result.name)
+			}
+			return Store{}, err
+		}
+		result.expvarVar = v
+	}
+
+	return result, nil
+}
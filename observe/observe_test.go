@@ -0,0 +1,455 @@
+package observe_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/observe"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+func TestStore(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.DefaultOptions)
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.DefaultOptions)
+	test.TestTypes(store, t)
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.DefaultOptions)
+
+	goroutineCount := 1000
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+
+}
+
+// TestStats tests that the number of calls and errors are counted per operation.
+func TestStats(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.DefaultOptions)
+
+	err := store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Not finding a value isn't an error
+	_, err = store.Get("missing", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("", "bar")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	stats := store.Stats()
+	if stats[observe.OpSet].Count != 2 || stats[observe.OpSet].Errors != 1 {
+		t.Errorf("Expected 2 set operations with 1 error, but was %+v", stats[observe.OpSet])
+	}
+	if stats[observe.OpGet].Count != 2 || stats[observe.OpGet].Errors != 0 {
+		t.Errorf("Expected 2 get operations without errors, but was %+v", stats[observe.OpGet])
+	}
+	if _, ok := stats[observe.OpDelete]; ok {
+		t.Error("Expected no stats for the delete operation, because it wasn't called")
+	}
+	if stats[observe.OpSet].Duration <= 0 {
+		t.Error("Expected the duration of the set operations to be measured")
+	}
+}
+
+// TestCodecDuration tests that the time spent in the codec is reported separately from the time spent in the backend.
+func TestCodecDuration(t *testing.T) {
+	delay := 20 * time.Millisecond
+
+	t.Run("slow codec", func(t *testing.T) {
+		metrics := observe.NewMetrics()
+		codec := slowCodec{Codec: encoding.JSON, delay: delay}
+		inner := gomap.NewStore(gomap.Options{Codec: metrics.WrapCodec(codec)})
+		store := createStore(t, inner, observe.Options{Metrics: metrics})
+		setAndGet(t, store)
+
+		for _, op := range []string{observe.OpSet, observe.OpGet} {
+			stats := store.Stats()[op]
+			if stats.CodecDuration < delay {
+				t.Errorf("Expected a codec duration of at least %v for %v, but was %v", delay, op, stats.CodecDuration)
+			}
+			if stats.BackendDuration() >= stats.CodecDuration {
+				t.Errorf("Expected the codec duration to dominate for %v, but the backend duration was %v and the codec duration %v", op, stats.BackendDuration(), stats.CodecDuration)
+			}
+		}
+	})
+
+	t.Run("slow backend", func(t *testing.T) {
+		metrics := observe.NewMetrics()
+		inner := slowStore{Store: gomap.NewStore(gomap.Options{Codec: metrics.WrapCodec(encoding.JSON)}), delay: delay}
+		store := createStore(t, inner, observe.Options{Metrics: metrics})
+		setAndGet(t, store)
+
+		for _, op := range []string{observe.OpSet, observe.OpGet} {
+			stats := store.Stats()[op]
+			if stats.BackendDuration() < delay {
+				t.Errorf("Expected a backend duration of at least %v for %v, but was %v", delay, op, stats.BackendDuration())
+			}
+			if stats.CodecDuration >= stats.BackendDuration() {
+				t.Errorf("Expected the backend duration to dominate for %v, but the backend duration was %v and the codec duration %v", op, stats.BackendDuration(), stats.CodecDuration)
+			}
+		}
+	})
+}
+
+// TestStatsRegistry tests that multiple stores contribute to the registry's snapshot under their names.
+func TestStatsRegistry(t *testing.T) {
+	registry := observe.NewStatsRegistry()
+	users := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.Options{Name: "users", Registry: registry})
+	sessions := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.Options{Name: "sessions", Registry: registry})
+
+	err := users.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = sessions.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = sessions.Get("bar", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot := registry.Snapshot()
+	if len(snapshot) != 2 {
+		t.Fatalf("Expected 2 stores in the snapshot, but was %v", len(snapshot))
+	}
+	if snapshot["users"][observe.OpSet].Count != 1 {
+		t.Errorf("Expected 1 set operation for users, but was %+v", snapshot["users"][observe.OpSet])
+	}
+	if _, ok := snapshot["users"][observe.OpGet]; ok {
+		t.Error("Expected no stats for the get operation of users, because it wasn't called")
+	}
+	if snapshot["sessions"][observe.OpGet].Count != 2 {
+		t.Errorf("Expected 2 get operations for sessions, but was %+v", snapshot["sessions"][observe.OpGet])
+	}
+
+	// Names must be unique
+	_, err = observe.NewStore(gomap.NewStore(gomap.DefaultOptions), observe.Options{Name: "users", Registry: registry})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	// A registered store needs a name
+	_, err = observe.NewStore(gomap.NewStore(gomap.DefaultOptions), observe.Options{Registry: registry})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Closing a store removes it from the registry
+	err = users.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot = registry.Snapshot()
+	if _, ok := snapshot["users"]; ok || len(snapshot) != 1 {
+		t.Errorf("Expected only sessions in the snapshot, but was %v", snapshot)
+	}
+}
+
+// TestWriteMetrics tests that the stats are written as valid lines in the Prometheus text exposition format.
+func TestWriteMetrics(t *testing.T) {
+	registry := observe.NewStatsRegistry()
+	users := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.Options{Name: "users", Registry: registry})
+	sessions := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.Options{Name: "sessions", Registry: registry})
+
+	err := users.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = users.Set("", "bar")
+	_, err = sessions.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	err = users.WriteMetrics(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := checkExpositionFormat(t, buf.String())
+	expectLines(t, lines,
+		`gokv_operations_total{store="users",operation="set"} 2`,
+		`gokv_operation_errors_total{store="users",operation="set"} 1`,
+	)
+
+	buf.Reset()
+	err = registry.WriteMetrics(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines = checkExpositionFormat(t, buf.String())
+	expectLines(t, lines,
+		`# TYPE gokv_operations_total counter`,
+		`gokv_operations_total{store="users",operation="set"} 2`,
+		`gokv_operations_total{store="sessions",operation="get"} 1`,
+		`gokv_operation_errors_total{store="sessions",operation="get"} 0`,
+	)
+	if strings.Count(buf.String(), "# TYPE gokv_operations_total") != 1 {
+		t.Error("Expected the metric to be declared only once for all stores")
+	}
+
+	// Without a name there's no store label
+	buf.Reset()
+	metrics := observe.NewMetrics()
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.Options{Metrics: metrics})
+	err = store.Delete("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = metrics.WriteMetrics(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines = checkExpositionFormat(t, buf.String())
+	expectLines(t, lines, `gokv_operations_total{operation="delete"} 1`)
+}
+
+// TestExpvar tests that the stats and the last error are published via expvar
+// and that a new store can take over the variable of a closed store.
+func TestExpvar(t *testing.T) {
+	inner := failingStore{Store: gomap.NewStore(gomap.DefaultOptions)}
+	store := createStore(t, inner, observe.Options{Name: "expvar-test", PublishExpvar: true})
+
+	err := store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = store.Get("bar", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Delete("foo")
+
+	published := readExpvar(t, "expvar-test")
+	if published.Operations["set"].Count != 1 || published.Operations["get"].Count != 2 {
+		t.Errorf("Expected 1 set and 2 get operations, but was %+v", published.Operations)
+	}
+	if published.Operations["delete"].Errors != 1 {
+		t.Errorf("Expected 1 delete error, but was %+v", published.Operations["delete"])
+	}
+	if published.LastError != "delete: failing" {
+		t.Errorf("Expected the last error %q, but was %q", "delete: failing", published.LastError)
+	}
+
+	// The name can't be used by two open stores
+	_, err = observe.NewStore(gomap.NewStore(gomap.DefaultOptions), observe.Options{Name: "expvar-test", PublishExpvar: true})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	// A name is required
+	_, err = observe.NewStore(gomap.NewStore(gomap.DefaultOptions), observe.Options{PublishExpvar: true})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// After closing the store, a new store takes over the variable
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := expvar.Get("expvar-test").String(); s != "{}" {
+		t.Errorf("Expected an empty object after closing the store, but was %v", s)
+	}
+	store = createStore(t, gomap.NewStore(gomap.DefaultOptions), observe.Options{Name: "expvar-test", PublishExpvar: true})
+	defer store.Close()
+	err = store.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	published = readExpvar(t, "expvar-test")
+	if len(published.Operations) != 1 || published.Operations["set"].Count != 1 || published.LastError != "" {
+		t.Errorf("Expected only the stats of the new store, but was %+v", published)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	_, err := observe.NewStore(nil, observe.DefaultOptions)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	inner := failingStore{Store: gomap.NewStore(gomap.DefaultOptions)}
+	store := createStore(t, inner, observe.DefaultOptions)
+	err = store.Delete("foo")
+	if !errors.Is(err, errFailing) {
+		t.Errorf("Expected the error of the inner store, but was %v", err)
+	}
+	if store.Stats()[observe.OpDelete].Errors != 1 {
+		t.Errorf("Expected 1 error, but was %v", store.Stats()[observe.OpDelete].Errors)
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store, err := observe.NewStore(gomap.NewStore(gomap.DefaultOptions), observe.DefaultOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func setAndGet(t *testing.T, store gokv.Store) {
+	t.Helper()
+	err := store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := store.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+}
+
+// slowCodec is an encoding.Codec that sleeps before each call.
+type slowCodec struct {
+	encoding.Codec
+	delay time.Duration
+}
+
+func (c slowCodec) Marshal(v any) ([]byte, error) {
+	time.Sleep(c.delay)
+	return c.Codec.Marshal(v)
+}
+
+func (c slowCodec) Unmarshal(data []byte, v any) error {
+	time.Sleep(c.delay)
+	return c.Codec.Unmarshal(data, v)
+}
+
+type expvarStats struct {
+	Operations map[string]struct {
+		Count  int64
+		Errors int64
+	} `json:"operations"`
+	LastError string `json:"last_error"`
+}
+
+// readExpvar reads the published JSON of the variable with the given name.
+func readExpvar(t *testing.T, name string) expvarStats {
+	t.Helper()
+
+	v := expvar.Get(name)
+	if v == nil {
+		t.Fatalf("No expvar variable with the name %v was published", name)
+	}
+	result := expvarStats{}
+	err := json.Unmarshal([]byte(v.String()), &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// slowStore is a gokv.Store that sleeps before each Set and Get.
+type slowStore struct {
+	gokv.Store
+	delay time.Duration
+}
+
+func (s slowStore) Set(k string, v any) error {
+	time.Sleep(s.delay)
+	return s.Store.Set(k, v)
+}
+
+func (s slowStore) Get(k string, v any) (bool, error) {
+	time.Sleep(s.delay)
+	return s.Store.Get(k, v)
+}
+
+var errFailing = errors.New("failing")
+
+// failingStore is a gokv.Store whose Delete always fails.
+type failingStore struct {
+	gokv.Store
+}
+
+func (s failingStore) Delete(k string) error {
+	return errFailing
+}
+
+var sampleLine = regexp.MustCompile(`^gokv_[a-z_]+\{(store="[^"]*",)?operation="[a-z]+"\} [0-9]+(\.[0-9]+)?(e-?[0-9]+)?$`)
+
+// checkExpositionFormat checks that each line is either a HELP or TYPE comment or a sample
+// and returns the lines.
+func checkExpositionFormat(t *testing.T, text string) []string {
+	t.Helper()
+	if !strings.HasSuffix(text, "\n") {
+		t.Error("Expected the text to end with a line feed")
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for _, line := range lines {
+		if strings.HasPrefix(line, "# HELP gokv_") || strings.HasPrefix(line, "# TYPE gokv_") {
+			continue
+		}
+		if !sampleLine.MatchString(line) {
+			t.Errorf("Invalid line: %q", line)
+		}
+	}
+	return lines
+}
+
+func expectLines(t *testing.T, lines []string, expected ...string) {
+	t.Helper()
+	for _, e := range expected {
+		found := false
+		for _, line := range lines {
+			if line == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected the line %q in %v", e, lines)
+		}
+	}
+}
+
+func createStore(t *testing.T, inner gokv.Store, options observe.Options) observe.Store {
+	store, err := observe.NewStore(inner, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	return store
+}
@@ -0,0 +1,75 @@
+package observe
+
+import (
+	"errors"
+	"sync"
+)
+
+// Named is implemented by stores that have a name,
+// for example the observe store when Options.Name is set.
+type Named interface {
+	// Name returns the name of the store.
+	Name() string
+}
+
+// statsSource is implemented by stores that collect stats, like the observe store.
+type statsSource interface {
+	Named
+	Stats() map[string]OperationStats
+}
+
+// StatsRegistry combines the stats of multiple named stores,
+// so that they can be read or exported in one place.
+// Create it with NewStatsRegistry.
+type StatsRegistry struct {
+	lock   sync.RWMutex
+	stores map[string]statsSource
+}
+
+// NewStatsRegistry creates a new StatsRegistry.
+func NewStatsRegistry() *StatsRegistry {
+	return &StatsRegistry{
+		stores: make(map[string]statsSource),
+	}
+}
+
+// Register adds the store to the registry under its name.
+// The store must also have a `Stats() map[string]OperationStats` method, like the observe store.
+// The name must not be "" and must not be registered already.
+func (r *StatsRegistry) Register(store Named) error {
+	source, ok := store.(statsSource)
+	if !ok {
+		return errors.New("The store doesn't collect stats")
+	}
+	name := store.Name()
+	if name == "" {
+		return errors.New("The name of the store must not be empty")
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.stores[name]; ok {
+		return errors.New("A store with the name " + name + " is already registered")
+	}
+	r.stores[name] = source
+	return nil
+}
+
+// Unregister removes the store with the given name from the registry.
+// Unregistering a name that isn't registered does NOT lead to an error.
+func (r *StatsRegistry) Unregister(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.stores, name)
+}
+
+// Snapshot returns a snapshot of the collected stats per store name and operation name.
+func (r *StatsRegistry) Snapshot() map[string]map[string]OperationStats {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	result := make(map[string]map[string]OperationStats, len(r.stores))
+	for name, store := range r.stores {
+		result[name] = store.Stats()
+	}
+	return result
+}
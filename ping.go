@@ -0,0 +1,32 @@
+package gokv
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrPingUnsupported is returned by Ping when the store doesn't implement the Pinger interface.
+var ErrPingUnsupported = errors.New("The store doesn't support pinging")
+
+// Pinger is an optional interface for stores that can check if their backend is reachable,
+// for example for a readiness probe, without writing or reading a dummy key-value pair.
+// Check for it with a type assertion or use the Ping function.
+//
+// It's implemented by the cockroachdb, etcd, file, gomap, memcached, mongodb, mysql, postgresql, redis and syncmap implementations.
+type Pinger interface {
+	// Ping checks if the backend of the store is reachable.
+	// In-memory stores always return nil.
+	// When the context is canceled or its deadline is exceeded, the check is aborted
+	// and an error is returned.
+	Ping(ctx context.Context) error
+}
+
+// Ping checks if the backend of the store is reachable via the store if it implements the Pinger interface.
+// Otherwise ErrPingUnsupported is returned.
+func Ping(ctx context.Context, store Store) error {
+	pinger, ok := store.(Pinger)
+	if !ok {
+		return ErrPingUnsupported
+	}
+	return pinger.Ping(ctx)
+}
@@ -2,11 +2,16 @@ package postgresql
 
 import (
 	gosql "database/sql"
+	"errors"
+	"net"
+	"net/url"
+	"strconv"
 
 	// Usually a blank import is enough as it calls the package's init() function and loads the driver,
 	// but we'll use the package's ParseDNS() function so we make this an actual import.
 	_ "github.com/lib/pq"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/sql"
 )
@@ -18,6 +23,11 @@ type Client struct {
 	*sql.Client
 }
 
+// Kind returns gokv.KindPostgreSQL.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindPostgreSQL
+}
+
 // Options are the options for the PostgreSQL client.
 type Options struct {
 	// Connection URL.
@@ -32,7 +42,42 @@ type Options struct {
 	// When running the official PostgreSQL Docker container
 	// and accessing it from outside the container,
 	// this is NOT the "same host" (except when running with `--network host`)).
+	// Must not be set together with the structured connection options below
+	// (Host, Port, User, Password, Database, SSLMode, SSLRootCert, SSLCert and SSLKey),
+	// which are an alternative for building the connection URL from separate values.
 	ConnectionURL string
+	// Host of the PostgreSQL server.
+	// Optional ("localhost" by default).
+	Host string
+	// Port of the PostgreSQL server.
+	// Optional (5432 by default).
+	Port int
+	// User for connecting to the PostgreSQL server.
+	// Optional ("postgres" by default).
+	User string
+	// Password of the user.
+	// Optional ("" by default).
+	Password string
+	// Name of the database, which must already exist.
+	// Optional ("gokv" by default).
+	Database string
+	// SSL mode for the connection.
+	// Valid values: "disable", "require" (TLS without verification),
+	// "verify-ca" (verification of the server certificate)
+	// and "verify-full" (verification of the server certificate and host name).
+	// The driver only supports TLS settings via the connection URL,
+	// which is why there's no option for a *tls.Config, but for the paths of the certificate files instead.
+	// Optional ("" by default, which leads to the driver's default, which is "require").
+	SSLMode string
+	// Path of the file with the root certificate(s) for verifying the server certificate.
+	// Optional ("" by default, which leads to the system's root certificates being used).
+	SSLRootCert string
+	// Path of the file with the client certificate for client certificate authentication.
+	// Optional ("" by default).
+	SSLCert string
+	// Path of the file with the private key of the client certificate.
+	// Optional ("" by default).
+	SSLKey string
 	// Name of the table in which the key-value pairs are stored.
 	// Optional ("Item" by default).
 	TableName string
@@ -40,13 +85,27 @@ type Options struct {
 	// -1 for no limit. 0 will lead to the default value (100) being set.
 	// Optional (100 by default).
 	MaxOpenConnections int
+	// An already opened DB, for example to share one connection pool between gokv and other code.
+	// The table is created if it doesn't exist yet.
+	// The client doesn't close the DB in this case, that's up to the caller.
+	// ConnectionURL and MaxOpenConnections are ignored if DB is set.
+	// Optional (nil by default).
+	DB *gosql.DB
+	// Maintain "created_at" and "updated_at" columns, which are set by the server on insert and update.
+	// Required for DeleteOlderThan().
+	// The columns are only added when the table is created,
+	// so an existing table without them leads to an error.
+	// Optional (false by default).
+	Timestamps bool
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// ConnectionURL: "postgres://postgres@/gokv?sslmode=disable", TableName: "Item", MaxOpenConnections: 100, Codec: encoding.JSON
+// ConnectionURL: "postgres://postgres@/gokv?sslmode=disable",
+// Host: "", Port: 0, User: "", Password: "", Database: "", SSLMode: "", SSLRootCert: "", SSLCert: "", SSLKey: "",
+// TableName: "Item", MaxOpenConnections: 100, DB: nil, Codec: encoding.JSON
 var DefaultOptions = Options{
 	ConnectionURL:      "postgres://postgres@/" + defaultDBname + "?sslmode=disable",
 	TableName:          "Item",
@@ -60,8 +119,20 @@ var DefaultOptions = Options{
 func NewClient(options Options) (Client, error) {
 	result := Client{}
 
+	// Precondition check
+	structured := options.Host != "" || options.Port != 0 || options.User != "" || options.Password != "" ||
+		options.Database != "" || options.SSLMode != "" || options.SSLRootCert != "" || options.SSLCert != "" || options.SSLKey != ""
+	if structured && options.ConnectionURL != "" {
+		return result, errors.New("The ConnectionURL must not be set together with the structured connection options")
+	}
+	if options.Port < 0 {
+		return result, errors.New("The Port must not be negative")
+	}
+
 	// Set default values
-	if options.ConnectionURL == "" {
+	if structured {
+		options.ConnectionURL = connectionURL(options)
+	} else if options.ConnectionURL == "" {
 		options.ConnectionURL = DefaultOptions.ConnectionURL
 	}
 	if options.TableName == "" {
@@ -76,22 +147,30 @@ func NewClient(options Options) (Client, error) {
 		options.Codec = DefaultOptions.Codec
 	}
 
-	db, err := gosql.Open("postgres", options.ConnectionURL)
-	if err != nil {
-		return result, err
-	}
+	db := options.DB
+	if db == nil {
+		var err error
+		db, err = gosql.Open("postgres", options.ConnectionURL)
+		if err != nil {
+			return result, err
+		}
 
-	err = db.Ping()
-	if err != nil {
-		return result, err
-	}
+		err = db.Ping()
+		if err != nil {
+			return result, err
+		}
 
-	// Limit number of concurrent connections. Typical max connections on a PostgreSQL server is 100.
-	// This prevents "Error 1040: Too many connections", which otherwise occurs for example with 500 concurrent goroutines.
-	db.SetMaxOpenConns(options.MaxOpenConnections)
+		// Limit number of concurrent connections. Typical max connections on a PostgreSQL server is 100.
+		// This prevents "Error 1040: Too many connections", which otherwise occurs for example with 500 concurrent goroutines.
+		db.SetMaxOpenConns(options.MaxOpenConnections)
+	}
 
 	// Create table if it doesn't exist yet.
-	_, err = db.Exec("CREATE TABLE IF NOT EXISTS " + options.TableName + " (k TEXT PRIMARY KEY, v BYTEA NOT NULL)")
+	columns := "k TEXT PRIMARY KEY, v BYTEA NOT NULL"
+	if options.Timestamps {
+		columns += ", created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(), updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()"
+	}
+	_, err := db.Exec("CREATE TABLE IF NOT EXISTS " + options.TableName + " (" + columns + ")")
 	if err != nil {
 		return result, err
 	}
@@ -100,7 +179,11 @@ func NewClient(options Options) (Client, error) {
 	// Note: Prepared statements are handled differently from other programming languages in Go,
 	// see: http://go-database-sql.org/prepared.html.
 	// TODO: Prepared statements might prevent the use of other databases that are compatible with the PostgreSQL protocol.
-	upsertStmt, err := db.Prepare("INSERT INTO " + options.TableName + " (k, v) VALUES ($1, $2) ON CONFLICT (k) DO UPDATE SET v = $2")
+	upsertQuery := "INSERT INTO " + options.TableName + " (k, v) VALUES ($1, $2) ON CONFLICT (k) DO UPDATE SET v = $2"
+	if options.Timestamps {
+		upsertQuery += ", updated_at = NOW()"
+	}
+	upsertStmt, err := db.Prepare(upsertQuery)
 	if err != nil {
 		return result, err
 	}
@@ -112,16 +195,75 @@ func NewClient(options Options) (Client, error) {
 	if err != nil {
 		return result, err
 	}
+	keysStmt, err := db.Prepare("SELECT k FROM " + options.TableName + " WHERE k LIKE $1 ESCAPE '!'")
+	if err != nil {
+		return result, err
+	}
+	var deleteOlderThanStmt *gosql.Stmt
+	if options.Timestamps {
+		deleteOlderThanStmt, err = db.Prepare("DELETE FROM " + options.TableName + " WHERE updated_at < NOW() - $1 * INTERVAL '1 microsecond'")
+		if err != nil {
+			return result, err
+		}
+	}
 
 	c := sql.Client{
-		C:          db,
-		UpsertStmt: upsertStmt,
-		GetStmt:    getStmt,
-		DeleteStmt: deleteStmt,
-		Codec:      options.Codec,
+		C:                   db,
+		UpsertStmt:          upsertStmt,
+		GetStmt:             getStmt,
+		DeleteStmt:          deleteStmt,
+		DeleteOlderThanStmt: deleteOlderThanStmt,
+		KeysStmt:            keysStmt,
+		Codec:               options.Codec,
+		SharedDB:            options.DB != nil,
 	}
 
 	result.Client = &c
 
 	return result, nil
 }
+
+// connectionURL builds the ConnectionURL from the structured connection options.
+func connectionURL(options Options) string {
+	host := options.Host
+	if host == "" {
+		host = "localhost"
+	}
+	port := options.Port
+	if port == 0 {
+		port = 5432
+	}
+	user := options.User
+	if user == "" {
+		user = "postgres"
+	}
+	database := options.Database
+	if database == "" {
+		database = defaultDBname
+	}
+
+	u := url.URL{
+		Scheme: "postgres",
+		User:   url.User(user),
+		Host:   net.JoinHostPort(host, strconv.Itoa(port)),
+		Path:   "/" + database,
+	}
+	if options.Password != "" {
+		u.User = url.UserPassword(user, options.Password)
+	}
+	query := url.Values{}
+	if options.SSLMode != "" {
+		query.Set("sslmode", options.SSLMode)
+	}
+	if options.SSLRootCert != "" {
+		query.Set("sslrootcert", options.SSLRootCert)
+	}
+	if options.SSLCert != "" {
+		query.Set("sslcert", options.SSLCert)
+	}
+	if options.SSLKey != "" {
+		query.Set("sslkey", options.SSLKey)
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
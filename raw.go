@@ -0,0 +1,65 @@
+package gokv
+
+// RawStore is an optional interface for stores that can work with the raw, already marshalled values,
+// for example for decoding one value multiple times or for copying values between stores
+// without unmarshalling and marshalling them again.
+// Check for it with a type assertion.
+type RawStore interface {
+	Store
+	// GetRaw retrieves the stored bytes for the given key, without unmarshalling them.
+	// If no value is found it returns (nil, false, nil).
+	// The key must not be "".
+	GetRaw(k string) (data []byte, found bool, err error)
+	// SetRaw stores the given bytes for the given key, without marshalling them.
+	// The bytes must have been marshalled with the same format as the store uses,
+	// otherwise a following Get fails.
+	// The key must not be "" and the data must not be nil.
+	SetRaw(k string, data []byte) error
+	// Unmarshal unmarshals the bytes that were returned by GetRaw
+	// with the same format (JSON, gob etc.) as Get does.
+	Unmarshal(data []byte, v any) error
+}
+
+// GetMulti retrieves the stored value for the given key and unmarshals it into each of the destinations,
+// for example into a struct and a generic map.
+// If the store implements RawStore, the value is only retrieved once.
+// Otherwise Get is called for each destination.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the destinations must be non-nil pointers.
+func GetMulti(store Store, k string, dests ...any) (found bool, err error) {
+	rawStore, ok := store.(RawStore)
+	if !ok {
+		for _, dest := range dests {
+			found, err = store.Get(k, dest)
+			if err != nil || !found {
+				return found, err
+			}
+		}
+		return found, nil
+	}
+
+	data, found, err := rawStore.GetRaw(k)
+	if err != nil || !found {
+		return found, err
+	}
+	for _, dest := range dests {
+		if err = rawStore.Unmarshal(data, dest); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// GetRawAndDecode retrieves the stored bytes for the given key and unmarshals them into v,
+// like Get does, but additionally returns the stored bytes.
+// This is useful for debugging, because when unmarshalling fails,
+// the returned bytes are the exact stored content and can be inspected or logged.
+// If no value is found it returns (nil, false, nil).
+// The key must not be "" and v must be a non-nil pointer.
+func GetRawAndDecode(store RawStore, k string, v any) (raw []byte, found bool, err error) {
+	raw, found, err = store.GetRaw(k)
+	if err != nil || !found {
+		return raw, found, err
+	}
+	return raw, true, store.Unmarshal(raw, v)
+}
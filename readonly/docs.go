@@ -0,0 +1,4 @@
+/*
+Package readonly contains a `gokv.Store` wrapper that allows reading from another store, but not changing it.
+*/
+package readonly
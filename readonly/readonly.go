@@ -0,0 +1,55 @@
+package readonly
+
+import (
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// Store is a gokv.Store implementation that forwards Get and Close to another store,
+// but rejects Set and Delete.
+type Store struct {
+	inner gokv.Store
+}
+
+// Set always returns gokv.ErrReadOnly unless the key or value are invalid.
+// The inner store isn't called.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	return gokv.ErrReadOnly
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	return s.inner.Get(k, v)
+}
+
+// Delete always returns gokv.ErrReadOnly unless the key is invalid.
+// The inner store isn't called.
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	return gokv.ErrReadOnly
+}
+
+// Close closes the inner store.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// NewStore creates a new readonly store that wraps the given store.
+// It can for example be passed to code that must be able to read the data, but never change it.
+// Only the wrapper is read-only, the inner store can still be changed directly.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store) Store {
+	return Store{
+		inner: inner,
+	}
+}
@@ -0,0 +1,87 @@
+package readonly_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/readonly"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestReadOnly tests that values of the inner store can be read, but not changed.
+func TestReadOnly(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	err := inner.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := readonly.NewStore(inner)
+
+	actual := test.Foo{}
+	found, err := store.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+	found, err = store.Get("bar", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+
+	err = store.Set("foo", test.Foo{Bar: "qux"})
+	if !errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected gokv.ErrReadOnly, but was %v", err)
+	}
+	err = store.Delete("foo")
+	if !errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected gokv.ErrReadOnly, but was %v", err)
+	}
+
+	// The inner store is unchanged
+	found, err = inner.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	store := readonly.NewStore(gomap.NewStore(gomap.DefaultOptions))
+
+	// Invalid arguments are reported as such instead of gokv.ErrReadOnly
+	err := store.Set("", "bar")
+	if err == nil || errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected an error about the key, but was %v", err)
+	}
+	err = store.Set("foo", nil)
+	if err == nil || errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected an error about the value, but was %v", err)
+	}
+	_, err = store.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil || errors.Is(err, gokv.ErrReadOnly) {
+		t.Errorf("Expected an error about the key, but was %v", err)
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store := readonly.NewStore(gomap.NewStore(gomap.DefaultOptions))
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
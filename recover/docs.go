@@ -0,0 +1,5 @@
+/*
+Package recover contains a `gokv.Store` wrapper that converts panics in the operations of another store,
+for example in a custom codec, into errors.
+*/
+package recover
@@ -0,0 +1,92 @@
+package recover
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"github.com/philippgille/gokv"
+)
+
+// ErrPanic is the error that's returned when an operation of the inner store panicked.
+type ErrPanic struct {
+	// Name of the operation that panicked, for example "Set".
+	Op string
+	// Value that was passed to panic().
+	Value any
+	// Stack trace of the goroutine at the time of the panic.
+	Stack []byte
+}
+
+// Error returns the operation and the recovered value, but not the stack trace.
+func (e *ErrPanic) Error() string {
+	return fmt.Sprintf("Panic during %v: %v", e.Op, e.Value)
+}
+
+// Unwrap returns the recovered value if it's an error, so that errors.Is() and errors.As() can inspect it.
+func (e *ErrPanic) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// Store is a gokv.Store implementation that forwards all calls to another store
+// and returns an *ErrPanic instead of panicking when the inner store panics.
+type Store struct {
+	inner gokv.Store
+}
+
+// Set stores the given value for the given key in the inner store.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) (err error) {
+	defer recoverPanic("Set", &err)
+
+	return s.inner.Set(k, v)
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	defer recoverPanic("Get", &err)
+
+	return s.inner.Get(k, v)
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) (err error) {
+	defer recoverPanic("Delete", &err)
+
+	return s.inner.Delete(k)
+}
+
+// Close closes the inner store.
+func (s Store) Close() (err error) {
+	defer recoverPanic("Close", &err)
+
+	return s.inner.Close()
+}
+
+// recoverPanic must be deferred directly, otherwise recover() doesn't stop the panic.
+func recoverPanic(op string, err *error) {
+	if r := recover(); r != nil {
+		*err = &ErrPanic{
+			Op:    op,
+			Value: r,
+			Stack: debug.Stack(),
+		}
+	}
+}
+
+// NewStore creates a new recover store that wraps the given store.
+// This allows running third-party or custom codecs and drivers without
+// a panic in one of them taking down the calling goroutine.
+// Only panics in the calling goroutine are recovered,
+// not the ones in goroutines that the inner store starts.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store) Store {
+	return Store{
+		inner: inner,
+	}
+}
@@ -0,0 +1,110 @@
+package recover_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/recover"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+func TestStore(t *testing.T) {
+	store := recover.NewStore(gomap.NewStore(gomap.DefaultOptions))
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := recover.NewStore(gomap.NewStore(gomap.DefaultOptions))
+	test.TestTypes(store, t)
+}
+
+// TestPanic tests that a panicking codec leads to an *ErrPanic instead of a crashed goroutine.
+func TestPanic(t *testing.T) {
+	codec := &panicCodec{Codec: encoding.JSON}
+	store := recover.NewStore(gomap.NewStore(gomap.Options{
+		Codec: codec,
+	}))
+
+	codec.panicOnMarshal = true
+	err := store.Set("foo", test.Foo{Bar: "baz"})
+	expectPanicErr(t, err, "Set", "marshal")
+
+	codec.panicOnMarshal = false
+	err = store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The panic value is an error, which can be inspected via errors.Is
+	codec.panicOnUnmarshal = true
+	_, err = store.Get("foo", new(test.Foo))
+	expectPanicErr(t, err, "Get", "unmarshal")
+	if !errors.Is(err, errUnmarshal) {
+		t.Errorf("Expected the error to wrap %v, but was %v", errUnmarshal, err)
+	}
+
+	codec.panicOnUnmarshal = false
+	actual := test.Foo{}
+	found, err := store.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store := recover.NewStore(gomap.NewStore(gomap.DefaultOptions))
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func expectPanicErr(t *testing.T, err error, op, value string) {
+	t.Helper()
+
+	var panicErr *recover.ErrPanic
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("Expected an *ErrPanic, but was %v", err)
+	}
+	if panicErr.Op != op {
+		t.Errorf("Expected the operation %v, but was %v", op, panicErr.Op)
+	}
+	if !strings.Contains(panicErr.Error(), value) {
+		t.Errorf("Expected the error to contain %q, but was %q", value, panicErr.Error())
+	}
+	if len(panicErr.Stack) == 0 {
+		t.Error("Expected a stack trace")
+	}
+}
+
+var errUnmarshal = errors.New("unmarshal")
+
+// panicCodec is an encoding.Codec that panics when configured to do so.
+type panicCodec struct {
+	encoding.Codec
+	panicOnMarshal   bool
+	panicOnUnmarshal bool
+}
+
+func (c *panicCodec) Marshal(v any) ([]byte, error) {
+	if c.panicOnMarshal {
+		panic("marshal")
+	}
+	return c.Codec.Marshal(v)
+}
+
+func (c *panicCodec) Unmarshal(data []byte, v any) error {
+	if c.panicOnUnmarshal {
+		panic(errUnmarshal)
+	}
+	return c.Codec.Unmarshal(data, v)
+}
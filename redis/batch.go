@@ -0,0 +1,125 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// SetMany stores the given values for the given keys.
+// All commands are sent in one pipeline, so it's only one round trip to the server.
+// The operation is not atomic.
+// The keys must not be "" and the values must not be nil.
+func (c Client) SetMany(values map[string]any) error {
+	if err := util.CheckKeysAndValues(values); err != nil {
+		return err
+	}
+
+	data := make(map[string][]byte, len(values))
+	for k, v := range values {
+		d, err := c.codec.Marshal(v)
+		if err != nil {
+			return err
+		}
+		data[k] = d
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	_, err := c.c.Pipelined(tctx, func(pipe redis.Pipeliner) error {
+		for k, d := range data {
+			pipe.Set(tctx, k, string(d), 0)
+		}
+		return nil
+	})
+	if c.cache != nil {
+		for k := range data {
+			c.cache.invalidate(k)
+		}
+	}
+	return err
+}
+
+// GetMany retrieves the stored values for the given keys.
+// All commands are sent in one pipeline, so it's only one round trip to the server.
+// values must be a slice with the same length as keys, for example a []any or []*Foo,
+// with each element being a non-nil pointer that the value of the key at the same index is unmarshalled into.
+// found contains whether a value was found for the key at the same index.
+// The keys must not be "".
+func (c Client) GetMany(keys []string, values any) (found []bool, err error) {
+	pointers, err := util.BatchPointers(keys, values)
+	if err != nil {
+		return nil, err
+	}
+	if len(keys) == 0 {
+		return []bool{}, nil
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	cmds := make([]*redis.StringCmd, len(keys))
+	_, err = c.c.Pipelined(tctx, func(pipe redis.Pipeliner) error {
+		for i, k := range keys {
+			cmds[i] = pipe.Get(tctx, k)
+		}
+		return nil
+	})
+	// A missing key leads to redis.Nil, which is checked for each command
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	found = make([]bool, len(keys))
+	for i, cmd := range cmds {
+		dataString, err := cmd.Result()
+		if err == redis.Nil {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		found[i] = true
+		if err = c.codec.Unmarshal([]byte(dataString), pointers[i]); err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+// DeleteMany deletes the stored values for the given keys with a single DEL command.
+// With a Redis Cluster the keys can belong to different nodes,
+// so there's one DEL command per key, sent in one pipeline.
+// Deleting non-existing key-value pairs does NOT lead to an error.
+// The keys must not be "".
+func (c Client) DeleteMany(keys []string) error {
+	if err := util.CheckKeys(keys); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	var err error
+	if _, ok := c.c.(*redis.ClusterClient); ok {
+		_, err = c.c.Pipelined(tctx, func(pipe redis.Pipeliner) error {
+			for _, k := range keys {
+				pipe.Del(tctx, k)
+			}
+			return nil
+		})
+	} else {
+		err = c.c.Del(tctx, keys...).Err()
+	}
+	if c.cache != nil {
+		for _, k := range keys {
+			c.cache.invalidate(k)
+		}
+	}
+	return err
+}
@@ -0,0 +1,184 @@
+package redis
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// invalidationChannel is the Pub/Sub channel on which Redis sends invalidation messages
+// to a client that's the target of a "CLIENT TRACKING on REDIRECT <id>".
+const invalidationChannel = "__redis__:invalidate"
+
+// localCache is a size bounded LRU cache for values read from Redis.
+// Its entries are removed when Redis sends invalidation messages for their keys.
+type localCache struct {
+	lock    *sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	order   *list.List
+	// epoch is incremented on every invalidation.
+	// A value that was read from Redis is only added to the cache if no invalidation
+	// happened in the meantime, because otherwise the invalidation message for that value
+	// could have been processed before the value was added.
+	epoch uint64
+	// disabled is set when the invalidation connection was lost.
+	disabled bool
+	// redirectID is the client ID of the connection that receives the invalidation messages.
+	redirectID int64
+}
+
+type localCacheEntry struct {
+	key  string
+	data []byte
+}
+
+func newLocalCache(size int) *localCache {
+	return &localCache{
+		lock:    &sync.Mutex{},
+		size:    size,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the cached data for the given key, if present.
+func (c *localCache) get(k string) ([]byte, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.disabled {
+		return nil, false
+	}
+	elem, ok := c.entries[k]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(elem)
+	return elem.Value.(localCacheEntry).data, true
+}
+
+// begin returns the current epoch, which must be passed to put()
+// after the value was read from Redis.
+func (c *localCache) begin() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.epoch
+}
+
+// put adds the data for the given key to the cache,
+// but only if there was no invalidation since the given epoch.
+func (c *localCache) put(k string, data []byte, epoch uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.disabled || epoch != c.epoch {
+		return
+	}
+	if elem, ok := c.entries[k]; ok {
+		elem.Value = localCacheEntry{key: k, data: data}
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[k] = c.order.PushFront(localCacheEntry{key: k, data: data})
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(localCacheEntry).key)
+	}
+}
+
+// invalidate removes the given keys from the cache.
+func (c *localCache) invalidate(keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.epoch++
+	for _, k := range keys {
+		if elem, ok := c.entries[k]; ok {
+			c.order.Remove(elem)
+			delete(c.entries, k)
+		}
+	}
+}
+
+// flush removes all entries from the cache.
+func (c *localCache) flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.epoch++
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// onInvalidationConnect is called for each new connection of the invalidation client.
+// The first connection's ID is used as redirect target for the tracking.
+// If it's a reconnect, invalidation messages could have been missed,
+// and the connections of the main client still redirect to the old connection,
+// so the cache is flushed and disabled.
+func (c *localCache) onInvalidationConnect(ctx context.Context, cn *redis.Conn) error {
+	id, err := cn.ClientID(ctx).Result()
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.redirectID == 0 {
+		c.redirectID = id
+		return nil
+	}
+	c.epoch++
+	c.disabled = true
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+	return nil
+}
+
+// onConnect is called for each new connection of the main client
+// and enables the tracking of the keys that are read via the connection.
+func (c *localCache) onConnect(ctx context.Context, cn *redis.Conn) error {
+	c.lock.Lock()
+	redirectID := c.redirectID
+	c.lock.Unlock()
+
+	cmd := redis.NewStatusCmd(ctx, "client", "tracking", "on", "redirect", redirectID)
+	_ = cn.Process(ctx, cmd)
+	return cmd.Err()
+}
+
+// listen processes the invalidation messages until the Pub/Sub is closed.
+func (c *localCache) listen(pubSub *redis.PubSub, done <-chan struct{}) {
+	for {
+		msg, err := pubSub.Receive(context.Background())
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if err != nil {
+			// The message for a flush of the whole DB has a nil payload,
+			// which go-redis can't parse. As any other error could also mean
+			// that a message was missed, flushing is the only safe option.
+			c.flush()
+			if err == redis.ErrClosed {
+				return
+			}
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		if msg, ok := msg.(*redis.Message); ok && msg.Channel == invalidationChannel {
+			if msg.PayloadSlice != nil {
+				c.invalidate(msg.PayloadSlice...)
+			} else {
+				c.invalidate(msg.Payload)
+			}
+		}
+	}
+}
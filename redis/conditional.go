@@ -0,0 +1,107 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// The comparison and deletion are done in one script, so they're atomic.
+var deleteIfScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+
+// The comparison and replacement are done in one script, so they're atomic.
+// KEEPTTL isn't used because it requires Redis 6, so an expiry is removed like with a regular Set.
+var compareAndSwapScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then
+	redis.call("set", KEYS[1], ARGV[2])
+	return 1
+end
+return 0`)
+
+// DeleteIf deletes the stored value for the given key if it's equal to the expected value.
+// The values are compared in their marshalled form.
+// If the key doesn't exist or holds a different value, it returns (false, nil).
+// The key must not be "" and the expected value must not be nil.
+func (c Client) DeleteIf(k string, expected any) (deleted bool, err error) {
+	if err := util.CheckKeyAndValue(k, expected); err != nil {
+		return false, err
+	}
+
+	data, err := c.codec.Marshal(expected)
+	if err != nil {
+		return false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	res, err := deleteIfScript.Run(tctx, c.c, []string{k}, data).Int()
+	if c.cache != nil {
+		c.cache.invalidate(k)
+	}
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+// CompareAndSwap stores the new value for the given key if the stored value is equal to the old value.
+// The values are compared in their marshalled form.
+// If the key doesn't exist or holds a different value, it returns (false, nil).
+// The key must not be "" and the values must not be nil.
+func (c Client) CompareAndSwap(k string, old, new any) (swapped bool, err error) {
+	if err := util.CheckKeyAndValue(k, old); err != nil {
+		return false, err
+	}
+	if err := util.CheckVal(new); err != nil {
+		return false, err
+	}
+
+	oldData, err := c.codec.Marshal(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.codec.Marshal(new)
+	if err != nil {
+		return false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	res, err := compareAndSwapScript.Run(tctx, c.c, []string{k}, oldData, newData).Int()
+	if c.cache != nil {
+		c.cache.invalidate(k)
+	}
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+// SetIfAbsent stores the given value for the given key if the key doesn't exist yet (SET with NX).
+// If the key already exists, it returns (false, nil).
+// The key must not be "" and the value must not be nil.
+func (c Client) SetIfAbsent(k string, v any) (set bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	set, err = c.c.SetNX(tctx, k, string(data), 0).Result()
+	if c.cache != nil && set {
+		c.cache.invalidate(k)
+	}
+	return set, err
+}
@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// The increment and the expiry are done in one script, so a counter can't be left without expiry
+// when the client fails between the two commands.
+// The expiry is only set when the counter doesn't have one yet, so it's not extended by later increments.
+var incrementWithTTLScript = redis.NewScript(`local count = redis.call("incrby", KEYS[1], ARGV[1])
+if redis.call("pttl", KEYS[1]) == -1 then
+	redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return count`)
+
+// Increment adds delta to the counter that's stored for the given key and returns the new count.
+// It uses INCRBY, so the counter is stored as decimal string and can be read with Get into an int64 when using encoding.JSON.
+// A non-existing counter starts at 0. delta can be negative to decrement the counter.
+// If the key holds a value that isn't an integer, gokv.ErrNotACounter is returned.
+// The key must not be "".
+func (c Client) Increment(k string, delta int64) (int64, error) {
+	if err := util.CheckKey(k); err != nil {
+		return 0, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	count, err := c.c.IncrBy(tctx, k, delta).Result()
+	if c.cache != nil {
+		// Redis also sends an invalidation message, but that arrives asynchronously.
+		c.cache.invalidate(k)
+	}
+	if err != nil {
+		// Redis replies with "ERR value is not an integer or out of range"
+		if strings.Contains(err.Error(), "not an integer") {
+			return 0, gokv.ErrNotACounter
+		}
+		return 0, err
+	}
+	return count, nil
+}
+
+// IncrementWithTTL adds delta to the counter that's stored for the given key, like Increment,
+// and lets the counter expire after the given TTL if it doesn't have an expiry yet.
+// So a new counter expires ttl after its first increment, which is useful for fixed window rate limiting.
+// It uses a Lua script (EVALSHA, with EVAL as fallback), so the increment and the expiry are atomic.
+// If the key holds a value that isn't an integer, gokv.ErrNotACounter is returned.
+// The key must not be "" and the TTL must be positive.
+func (c Client) IncrementWithTTL(k string, delta int64, ttl time.Duration) (int64, error) {
+	if err := util.CheckKey(k); err != nil {
+		return 0, err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return 0, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	count, err := incrementWithTTLScript.Run(tctx, c.c, []string{k}, delta, ttl.Milliseconds()).Int64()
+	if c.cache != nil {
+		c.cache.invalidate(k)
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "not an integer") {
+			return 0, gokv.ErrNotACounter
+		}
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,79 @@
+package redis
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Number of keys that Redis should look at per SCAN call.
+const scanCount = 100
+
+// globEscaper escapes the characters that have a special meaning in glob-style patterns.
+var globEscaper = strings.NewReplacer(`\`, `\\`, "*", `\*`, "?", `\?`, "[", `\[`, "]", `\]`)
+
+// ForEach calls fn for each key that starts with the given prefix.
+// It uses SCAN, so the server isn't blocked like with KEYS,
+// and each call is subject to the configured timeout.
+// With a Redis Cluster the keys of all master nodes are scanned, one node after the other.
+// SCAN can return a key multiple times, so the keys that were already passed to fn are remembered
+// to pass each key only once.
+// If fn returns an error, the iteration stops and the error is returned.
+func (c Client) ForEach(prefix string, fn func(k string) error) error {
+	match := globEscaper.Replace(prefix) + "*"
+	seen := make(map[string]struct{})
+
+	clusterClient, ok := c.c.(*redis.ClusterClient)
+	if !ok {
+		return c.scan(c.c, match, seen, fn)
+	}
+
+	// ForEachMaster calls the function concurrently, but fn must be called sequentially.
+	var nodes []*redis.Client
+	var lock sync.Mutex
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	err := clusterClient.ForEachMaster(tctx, func(ctx context.Context, node *redis.Client) error {
+		lock.Lock()
+		defer lock.Unlock()
+		nodes = append(nodes, node)
+		return nil
+	})
+	cancel()
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if err = c.scan(node, match, seen, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scan calls fn for each key of the given client that matches the pattern and isn't in seen yet.
+func (c Client) scan(client redis.UniversalClient, match string, seen map[string]struct{}, fn func(k string) error) error {
+	var cursor uint64
+	for {
+		tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+		keys, nextCursor, err := client.Scan(tctx, cursor, match, scanCount).Result()
+		cancel()
+		if err != nil {
+			return err
+		}
+		for _, k := range keys {
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if err = fn(k); err != nil {
+				return err
+			}
+		}
+		if nextCursor == 0 {
+			return nil
+		}
+		cursor = nextCursor
+	}
+}
@@ -0,0 +1,120 @@
+package redis
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// The scripts only modify the lock if it's still held with the same token,
+// so a lock that expired and was acquired by someone else isn't released or extended.
+var (
+	releaseScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("del", KEYS[1])
+end
+return 0`)
+	refreshScript = redis.NewScript(`if redis.call("get", KEYS[1]) == ARGV[1] then
+	return redis.call("pexpire", KEYS[1], ARGV[2])
+end
+return 0`)
+)
+
+// AcquireLock acquires the lock with the given key via SET NX PX.
+// If the lock is currently held by someone else, gokv.ErrLockHeld is returned.
+// The lock automatically expires after the TTL, unless it's refreshed before.
+// The key must not be "" and the TTL must be positive.
+func (c Client) AcquireLock(k string, ttl time.Duration) (gokv.Lock, error) {
+	if err := util.CheckKey(k); err != nil {
+		return nil, err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return nil, err
+	}
+
+	token, err := newLockToken()
+	if err != nil {
+		return nil, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	ok, err := c.c.SetNX(tctx, k, token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, gokv.ErrLockHeld
+	}
+	return lock{
+		c:       c.c,
+		key:     k,
+		token:   token,
+		timeOut: c.timeOut,
+	}, nil
+}
+
+// lock is a gokv.Lock implementation for Redis.
+type lock struct {
+	c       redis.UniversalClient
+	key     string
+	token   string
+	timeOut time.Duration
+}
+
+// Release releases the lock.
+// If the lock expired in the meantime, gokv.ErrLockLost is returned.
+func (l lock) Release() error {
+	tctx, cancel := context.WithTimeout(context.Background(), l.timeOut)
+	defer cancel()
+
+	n, err := releaseScript.Run(tctx, l.c, []string{l.key}, l.token).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return gokv.ErrLockLost
+	}
+	return nil
+}
+
+// Refresh extends the lock's expiry to the given TTL from now.
+// If the lock expired in the meantime, gokv.ErrLockLost is returned.
+// The TTL must be positive. Like in AcquireLock, a TTL below one millisecond is rounded up to one millisecond.
+func (l lock) Refresh(ttl time.Duration) error {
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+	// PEXPIRE with 0 would delete the lock, so round up like go-redis does for SET PX.
+	ttlMillis := ttl.Milliseconds()
+	if ttlMillis < 1 {
+		ttlMillis = 1
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), l.timeOut)
+	defer cancel()
+
+	n, err := refreshScript.Run(tctx, l.c, []string{l.key}, l.token, ttlMillis).Int()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return gokv.ErrLockLost
+	}
+	return nil
+}
+
+// newLockToken returns a random token that identifies the holder of a lock.
+func newLockToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -2,21 +2,32 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
 var defaultTimeout = 2 * time.Second
 
+var defaultClientSideCacheSize = 1000
+
 // Client is a gokv.Store implementation for Redis.
 type Client struct {
-	c       *redis.Client
+	// *redis.Client for a single node or via Sentinel, *redis.ClusterClient for a cluster
+	c       redis.UniversalClient
 	timeOut time.Duration
 	codec   encoding.Codec
+	// Only set when client-side caching is enabled.
+	cache              *localCache
+	invalidationClient *redis.Client
+	pubSub             *redis.PubSub
+	done               chan struct{}
 }
 
 // Set stores the given value for the given key.
@@ -40,6 +51,10 @@ func (c Client) Set(k string, v any) error {
 	defer cancel()
 
 	err = c.c.Set(tctx, k, string(data), 0).Err()
+	if c.cache != nil {
+		// Redis also sends an invalidation message, but that arrives asynchronously.
+		c.cache.invalidate(k)
+	}
 	if err != nil {
 		return err
 	}
@@ -57,6 +72,14 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 		return false, err
 	}
 
+	var epoch uint64
+	if c.cache != nil {
+		if data, ok := c.cache.get(k); ok {
+			return true, c.codec.Unmarshal(data, v)
+		}
+		epoch = c.cache.begin()
+	}
+
 	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
 	defer cancel()
 
@@ -68,7 +91,12 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 		return false, err
 	}
 
-	return true, c.codec.Unmarshal([]byte(dataString), v)
+	data := []byte(dataString)
+	if c.cache != nil {
+		c.cache.put(k, data, epoch)
+	}
+
+	return true, c.codec.Unmarshal(data, v)
 }
 
 // Delete deletes the stored value for the given key.
@@ -83,49 +111,131 @@ func (c Client) Delete(k string) error {
 	defer cancel()
 
 	_, err := c.c.Del(tctx, k).Result()
+	if c.cache != nil {
+		c.cache.invalidate(k)
+	}
 	return err
 }
 
+// Ping checks if the Redis server is reachable with the PING command.
+func (c Client) Ping(ctx context.Context) error {
+	return c.c.Ping(ctx).Err()
+}
+
+// Kind returns gokv.KindRedis.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindRedis
+}
+
 // Close closes the client.
 // It must be called to release any open resources.
 func (c Client) Close() error {
+	if c.cache != nil {
+		close(c.done)
+		_ = c.pubSub.Close()
+		_ = c.invalidationClient.Close()
+	}
 	return c.c.Close()
 }
 
 // Options are the options for the Redis client.
 type Options struct {
 	// Address of the Redis server, including the port.
+	// Not used when ClusterAddresses or SentinelAddresses are set.
 	// Optional ("localhost:6379" by default).
 	Address string
+	// Addresses of the nodes of a Redis Cluster, including the ports.
+	// When set, a cluster client is used, which determines the node of each key and follows redirections.
+	// Not all nodes need to be listed, the others are discovered.
+	// DB must be 0 and ClientSideCache isn't supported with a cluster.
+	// Optional (nil by default).
+	ClusterAddresses []string
+	// Addresses of the Redis Sentinel servers, including the ports.
+	// When set, the client asks the Sentinels for the address of the current master
+	// and switches to the new master after a failover.
+	// MasterName is required then.
+	// Optional (nil by default).
+	SentinelAddresses []string
+	// Name of the master that the Sentinels monitor.
+	// Only used when SentinelAddresses are set.
+	// Optional ("" by default).
+	MasterName string
 	// Password for the Redis server.
 	// Optional ("" by default).
 	Password string
 	// DB to use.
 	// Optional (0 by default).
 	DB int
+	// TLS configuration for the connection to the Redis server,
+	// for example for managed Redis services that require in-transit encryption.
+	// When set, connections are only made via TLS.
+	// Optional (nil by default, which means no TLS is used).
+	TLSConfig *tls.Config
 	// The timeout for operations.
 	// Optional (2 * time.Second by default).
 	Timeout *time.Duration
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
+	// Enables client-side caching, which requires Redis 6 or newer.
+	// Values that were read are cached locally, so repeated reads of unchanged keys
+	// don't lead to a round-trip to the server. Redis keeps track of the keys
+	// that were read ("CLIENT TRACKING") and sends an invalidation message
+	// when they are changed by any client, upon which the local copy is removed.
+	// The invalidation messages arrive asynchronously, so after another client changed a value,
+	// this client can still read the old value for a short time (usually sub-millisecond).
+	// Changes made via this client itself are visible immediately.
+	// go-redis doesn't expose RESP3 push messages, so the invalidation messages are received
+	// via a separate Pub/Sub connection (tracking redirect mode).
+	// If that connection is lost, the local cache is flushed and disabled,
+	// so no stale values are read.
+	// Optional (false by default).
+	ClientSideCache bool
+	// Maximum number of values that are cached locally.
+	// When the cache is full, the least recently used value is evicted.
+	// Only used when ClientSideCache is true.
+	// Optional (1000 by default).
+	ClientSideCacheSize int
 }
 
 // DefaultOptions is an Options object with default values.
-// Address: "localhost:6379", Password: "", DB: 0, Timeout: 2 * time.Second, Codec: encoding.JSON
+// Address: "localhost:6379", ClusterAddresses: nil, SentinelAddresses: nil, MasterName: "",
+// Password: "", DB: 0, TLSConfig: nil, Timeout: 2 * time.Second, Codec: encoding.JSON,
+// ClientSideCache: false, ClientSideCacheSize: 1000
 var DefaultOptions = Options{
-	Address: "localhost:6379",
-	Timeout: &defaultTimeout,
-	Codec:   encoding.JSON,
-	// No need to set Password or DB because their Go zero values are fine for that.
+	Address:             "localhost:6379",
+	Timeout:             &defaultTimeout,
+	Codec:               encoding.JSON,
+	ClientSideCacheSize: defaultClientSideCacheSize,
+	// No need to set ClusterAddresses, SentinelAddresses, MasterName,
+	// Password, DB, TLSConfig or ClientSideCache because their Go zero values are fine for that.
 }
 
 // NewClient creates a new Redis client.
+// It connects to a single Redis server by default,
+// to a Redis Cluster if ClusterAddresses are set
+// or to the master of a Sentinel setup if SentinelAddresses are set.
 //
 // You must call the Close() method on the client when you're done working with it.
 func NewClient(options Options) (Client, error) {
 	result := Client{}
 
+	// Precondition check
+	if len(options.ClusterAddresses) > 0 {
+		if len(options.SentinelAddresses) > 0 {
+			return result, errors.New("ClusterAddresses and SentinelAddresses must not be set both")
+		}
+		if options.DB != 0 {
+			return result, errors.New("A Redis Cluster only supports DB 0")
+		}
+		if options.ClientSideCache {
+			return result, errors.New("ClientSideCache isn't supported with a Redis Cluster")
+		}
+	}
+	if len(options.SentinelAddresses) > 0 && options.MasterName == "" {
+		return result, errors.New("The MasterName must be set when SentinelAddresses are set")
+	}
+
 	// Set default values
 	if options.Address == "" {
 		options.Address = DefaultOptions.Address
@@ -136,18 +246,52 @@ func NewClient(options Options) (Client, error) {
 	if options.Codec == nil {
 		options.Codec = DefaultOptions.Codec
 	}
-
-	client := redis.NewClient(&redis.Options{
-		Addr:     options.Address,
-		Password: options.Password,
-		DB:       options.DB,
-	})
+	if options.ClientSideCacheSize <= 0 {
+		options.ClientSideCacheSize = DefaultOptions.ClientSideCacheSize
+	}
 
 	tctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 	defer cancel()
 
+	if options.ClientSideCache {
+		cache := newLocalCache(options.ClientSideCacheSize)
+		// The invalidation messages are only sent as Pub/Sub messages with RESP2.
+		invalidationClient := newNodeClient(options, 2, cache.onInvalidationConnect)
+		pubSub := invalidationClient.Subscribe(tctx, invalidationChannel)
+		// Wait for the subscription confirmation, so that the redirect ID is known.
+		_, err := pubSub.Receive(tctx)
+		if err != nil {
+			_ = pubSub.Close()
+			_ = invalidationClient.Close()
+			return result, err
+		}
+		result.cache = cache
+		result.invalidationClient = invalidationClient
+		result.pubSub = pubSub
+		result.done = make(chan struct{})
+		go cache.listen(pubSub, result.done)
+	}
+
+	var client redis.UniversalClient
+	if len(options.ClusterAddresses) > 0 {
+		client = redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:     options.ClusterAddresses,
+			Password:  options.Password,
+			TLSConfig: options.TLSConfig,
+		})
+	} else if result.cache != nil {
+		client = newNodeClient(options, 0, result.cache.onConnect)
+	} else {
+		client = newNodeClient(options, 0, nil)
+	}
+
 	err := client.Ping(tctx).Err()
 	if err != nil {
+		if result.cache != nil {
+			close(result.done)
+			_ = result.pubSub.Close()
+			_ = result.invalidationClient.Close()
+		}
 		return result, err
 	}
 
@@ -157,3 +301,28 @@ func NewClient(options Options) (Client, error) {
 
 	return result, nil
 }
+
+// newNodeClient creates a client for a single Redis server,
+// which is either the configured address or the master that the Sentinels report.
+// A protocol of 0 means the go-redis default.
+func newNodeClient(options Options, protocol int, onConnect func(context.Context, *redis.Conn) error) *redis.Client {
+	if len(options.SentinelAddresses) > 0 {
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:    options.MasterName,
+			SentinelAddrs: options.SentinelAddresses,
+			Password:      options.Password,
+			DB:            options.DB,
+			TLSConfig:     options.TLSConfig,
+			Protocol:      protocol,
+			OnConnect:     onConnect,
+		})
+	}
+	return redis.NewClient(&redis.Options{
+		Addr:      options.Address,
+		Password:  options.Password,
+		DB:        options.DB,
+		TLSConfig: options.TLSConfig,
+		Protocol:  protocol,
+		OnConnect: onConnect,
+	})
+}
@@ -2,11 +2,17 @@ package redis_test
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"log"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	goredis "github.com/redis/go-redis/v9"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/redis"
 	"github.com/philippgille/gokv/test"
@@ -16,6 +22,16 @@ import (
 // which could lead to valuable data being deleted when a developer accidentally runs the test with valuable data in DB 0.
 var testDbNumber = 15 // 16 DBs by default (unchanged config), starting with 0
 
+// Addresses of a Redis Cluster and a Redis Sentinel setup.
+var (
+	clusterTestAddresses   = []string{"localhost:7000"}
+	sentinelTestAddresses  = []string{"localhost:26379"}
+	sentinelTestMasterName = "mymaster"
+)
+
+// Address of a Redis server with TLS enabled, for example started with "--tls-port 6380 --port 0".
+var tlsTestAddress = "localhost:6380"
+
 // TestClient tests if reading from, writing to and deleting from the store works properly.
 // A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
 func TestClient(t *testing.T) {
@@ -51,6 +67,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the Redis client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -78,6 +111,20 @@ func TestErrors(t *testing.T) {
 	if err == nil {
 		t.Error("Expected an error")
 	}
+
+	// Test invalid options
+	invalidOptions := []redis.Options{
+		{ClusterAddresses: clusterTestAddresses, SentinelAddresses: sentinelTestAddresses, MasterName: sentinelTestMasterName},
+		{ClusterAddresses: clusterTestAddresses, DB: testDbNumber},
+		{ClusterAddresses: clusterTestAddresses, ClientSideCache: true},
+		{SentinelAddresses: sentinelTestAddresses},
+	}
+	for _, options := range invalidOptions {
+		_, err = redis.NewClient(options)
+		if err == nil {
+			t.Errorf("Expected an error for options %+v", options)
+		}
+	}
 }
 
 // TestNil tests the behaviour when passing nil or pointers to nil values to some methods.
@@ -137,6 +184,13 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestPing tests if the server is reachable via Ping.
+func TestPing(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestPinger(client, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -146,6 +200,411 @@ func TestClose(t *testing.T) {
 	}
 }
 
+// TestLock tests that two clients can't hold the same lock simultaneously
+// and that a lock automatically expires after its TTL, unless it's refreshed by gokv.WithLock.
+func TestLock(t *testing.T) {
+	clientA := createClient(t, encoding.JSON)
+	defer clientA.Close()
+	clientB := createClient(t, encoding.JSON)
+	defer clientB.Close()
+
+	err := clientA.Delete("lock")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	test.TestLocker(t, clientA, clientB, "lock", time.Second)
+	test.TestWithLock(t, clientA, clientB, "lock", time.Second)
+}
+
+// TestDeleteIf tests that a key-value pair is only deleted when it holds the expected value.
+func TestDeleteIf(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestConditionalDeleter(client, t)
+}
+
+// TestCompareAndSwap tests that a value is only replaced when it holds the expected value.
+func TestCompareAndSwap(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestCASStore(client, t)
+}
+
+// TestSetIfAbsent tests that a value is only stored when the key doesn't exist yet.
+func TestSetIfAbsent(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestConditionalSetter(client, t)
+}
+
+// TestUpdateFunc tests if concurrent updates via gokv.UpdateFunc don't get lost.
+func TestUpdateFunc(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestUpdateFunc(client, t)
+}
+
+// TestCounter tests if concurrent increments of a counter are atomic.
+func TestCounter(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestCounterStore(client, t)
+}
+
+// TestBatch tests if the gokv.BatchStore methods work properly.
+func TestBatch(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestBatchStore(client, t)
+}
+
+// TestIterator tests if listing the keys via SCAN works properly.
+func TestIterator(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestIterator(client, t)
+}
+
+// TestTTL tests if key-value pairs expire after their TTL.
+func TestTTL(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestTTLStore(client, t)
+}
+
+// TestSetIfAbsentWithTTL tests that a value is only stored if the key doesn't exist yet
+// and that it expires after its TTL.
+func TestSetIfAbsentWithTTL(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+
+	err := client.Delete("absent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, found, err := client.TTL("absent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A TTL was found, but no key was expected")
+	}
+
+	ttl := time.Second
+	set, err := client.SetIfAbsentWithTTL("absent", "first", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !set {
+		t.Error("Expected the value to be set")
+	}
+	set, err = client.SetIfAbsentWithTTL("absent", "second", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set {
+		t.Error("Expected the value not to be set again")
+	}
+
+	actual := ""
+	found, err = client.Get("absent", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual != "first" {
+		t.Errorf("Expected %q to be found, but found was %v and the value %q", "first", found, actual)
+	}
+	remaining, found, err := client.TTL("absent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || remaining <= 0 || remaining > ttl {
+		t.Errorf("Expected a TTL between 0 and %v, but was %v (found: %v)", ttl, remaining, found)
+	}
+
+	time.Sleep(ttl + 200*time.Millisecond)
+	set, err = client.SetIfAbsentWithTTL("absent", "third", ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !set {
+		t.Error("Expected the value to be set after the previous one expired")
+	}
+
+	// A key without expiry has a TTL of 0
+	err = client.Set("absent", "forever")
+	if err != nil {
+		t.Fatal(err)
+	}
+	remaining, found, err = client.TTL("absent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || remaining != 0 {
+		t.Errorf("Expected a TTL of 0, but was %v (found: %v)", remaining, found)
+	}
+}
+
+// TestIncrementWithTTL tests that the expiry of a counter is set by the first increment
+// and not extended by later increments.
+func TestIncrementWithTTL(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+
+	err := client.Delete("window")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ttl := time.Second
+	count, err := client.IncrementWithTTL("window", 1, ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Errorf("Expected 1, but was %v", count)
+	}
+	time.Sleep(500 * time.Millisecond)
+	count, err = client.IncrementWithTTL("window", 2, ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("Expected 3, but was %v", count)
+	}
+	remaining, found, err := client.TTL("window")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || remaining <= 0 || remaining > ttl/2 {
+		t.Errorf("Expected a TTL between 0 and %v, but was %v (found: %v)", ttl/2, remaining, found)
+	}
+
+	time.Sleep(ttl)
+	found, err = client.Get("window", new(int64))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but the counter should have expired")
+	}
+
+	err = client.Set("window", "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.IncrementWithTTL("window", 1, ttl)
+	if !errors.Is(err, gokv.ErrNotACounter) {
+		t.Errorf("Expected %v, but was %v", gokv.ErrNotACounter, err)
+	}
+}
+
+// TestKind tests if the client reports gokv.KindRedis via the gokv.KindReporter interface.
+func TestKind(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	var store gokv.Store = client
+	if kind := gokv.KindOf(store); kind != gokv.KindRedis {
+		t.Errorf("Expected %q, but was %q", gokv.KindRedis, kind)
+	}
+}
+
+// TestClientSideCache tests that repeated reads of an unchanged key are served from the local cache
+// and that changes invalidate the local copy.
+//
+// Note: This test is only executed if the Redis server supports client-side caching (Redis 6 or newer).
+func TestClientSideCache(t *testing.T) {
+	external := goredis.NewClient(&goredis.Options{
+		Addr: redis.DefaultOptions.Address,
+		DB:   testDbNumber,
+	})
+	defer external.Close()
+	if !checkClientSideCacheSupport(external) {
+		t.Skip("The Redis server doesn't support client-side caching. Redis 6 or newer is required.")
+	}
+
+	client, err := redis.NewClient(redis.Options{
+		DB:              testDbNumber,
+		ClientSideCache: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	err = client.Set("foo", "bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Populates the local cache
+	expectValue(t, client, "foo", "bar")
+
+	// Repeated reads must not lead to GET commands on the server
+	err = external.ConfigResetStat(context.Background()).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		expectValue(t, client, "foo", "bar")
+	}
+	stats, err := external.Info(context.Background(), "commandstats").Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(stats, "cmdstat_get:") {
+		t.Errorf("Expected no GET commands on the server, but got: %v", stats)
+	}
+
+	// A change via another client must invalidate the local copy
+	err = external.Set(context.Background(), "foo", `"baz"`, 0).Err()
+	if err != nil {
+		t.Fatal(err)
+	}
+	invalidated := false
+	for i := 0; i < 100; i++ {
+		var actual string
+		_, err = client.Get("foo", &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if actual == "baz" {
+			invalidated = true
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !invalidated {
+		t.Error("The local copy wasn't invalidated after a change via another client")
+	}
+
+	// A change via the client itself must be visible immediately
+	err = client.Set("foo", "qux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValue(t, client, "foo", "qux")
+	err = client.Delete("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := client.Get("foo", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+}
+
+func expectValue(t *testing.T, client redis.Client, k, expected string) {
+	t.Helper()
+
+	var actual string
+	found, err := client.Get(k, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if actual != expected {
+		t.Errorf("Expected %q, but was %q", expected, actual)
+	}
+}
+
+// checkClientSideCacheSupport returns true if the Redis server is reachable
+// and supports client-side caching, false otherwise.
+func checkClientSideCacheSupport(client *goredis.Client) bool {
+	info, err := client.Info(context.Background(), "server").Result()
+	if err != nil {
+		log.Printf("An error occurred during testing the connection to the server: %v\n", err)
+		return false
+	}
+	for _, line := range strings.Split(info, "\n") {
+		if version, ok := strings.CutPrefix(line, "redis_version:"); ok {
+			major, err := strconv.Atoi(strings.Split(version, ".")[0])
+			return err == nil && major >= 6
+		}
+	}
+	return false
+}
+
+// TestTLS tests if the client works with a Redis server that only accepts TLS connections.
+//
+// Note: This test is only executed if a Redis server with TLS enabled is listening on port 6380.
+// The server's certificate isn't verified, so it can be self-signed.
+func TestTLS(t *testing.T) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: true,
+	}
+	if !checkTLSConnection(tlsTestAddress, tlsConfig) {
+		t.Skip("No connection could be made to a Redis server with TLS enabled on " + tlsTestAddress)
+	}
+
+	client, err := redis.NewClient(redis.Options{
+		Address:   tlsTestAddress,
+		DB:        testDbNumber,
+		TLSConfig: tlsConfig,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	test.TestStore(client, t)
+
+	// Without TLS the server must not accept the connection
+	_, err = redis.NewClient(redis.Options{
+		Address: tlsTestAddress,
+		DB:      testDbNumber,
+	})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestCluster tests if the client works with a Redis Cluster,
+// including batch operations and iteration with keys on different nodes.
+//
+// Note: This test is only executed if a Redis Cluster is reachable via port 7000.
+func TestCluster(t *testing.T) {
+	if !checkClusterConnection() {
+		t.Skip("No connection could be made to a Redis Cluster on " + clusterTestAddresses[0])
+	}
+
+	client, err := redis.NewClient(redis.Options{
+		ClusterAddresses: clusterTestAddresses,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	test.TestStore(client, t)
+	test.TestBatchStore(client, t)
+	test.TestIterator(client, t)
+}
+
+// TestSentinel tests if the client works with the master of a Redis Sentinel setup.
+//
+// Note: This test is only executed if a Redis Sentinel is reachable via port 26379
+// and monitors a master with the name "mymaster".
+func TestSentinel(t *testing.T) {
+	if !checkSentinelConnection() {
+		t.Skip("No connection could be made to a Redis Sentinel on " + sentinelTestAddresses[0])
+	}
+
+	client, err := redis.NewClient(redis.Options{
+		SentinelAddresses: sentinelTestAddresses,
+		MasterName:        sentinelTestMasterName,
+		DB:                testDbNumber,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	test.TestStore(client, t)
+}
+
 // checkConnection returns true if a connection could be made, false otherwise.
 func checkConnection(number int) bool {
 	client := goredis.NewClient(&goredis.Options{
@@ -162,6 +621,52 @@ func checkConnection(number int) bool {
 	return true
 }
 
+// checkTLSConnection returns true if a TLS connection could be made, false otherwise.
+func checkTLSConnection(address string, tlsConfig *tls.Config) bool {
+	client := goredis.NewClient(&goredis.Options{
+		Addr:      address,
+		DB:        testDbNumber,
+		TLSConfig: tlsConfig,
+	})
+	defer client.Close()
+	err := client.Ping(context.Background()).Err()
+	if err != nil {
+		log.Printf("An error occurred during testing the TLS connection to the server: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// checkClusterConnection returns true if a connection to a Redis Cluster could be made, false otherwise.
+func checkClusterConnection() bool {
+	client := goredis.NewClusterClient(&goredis.ClusterOptions{
+		Addrs: clusterTestAddresses,
+	})
+	defer client.Close()
+	err := client.Ping(context.Background()).Err()
+	if err != nil {
+		log.Printf("An error occurred during testing the connection to the cluster: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// checkSentinelConnection returns true if a connection to the master of a Sentinel setup could be made, false otherwise.
+func checkSentinelConnection() bool {
+	client := goredis.NewFailoverClient(&goredis.FailoverOptions{
+		MasterName:    sentinelTestMasterName,
+		SentinelAddrs: sentinelTestAddresses,
+		DB:            testDbNumber,
+	})
+	defer client.Close()
+	err := client.Ping(context.Background()).Err()
+	if err != nil {
+		log.Printf("An error occurred during testing the connection to the Sentinel master: %v\n", err)
+		return false
+	}
+	return true
+}
+
 func createClient(t *testing.T, codec encoding.Codec) redis.Client {
 	options := redis.Options{
 		DB:    testDbNumber,
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"time"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// SetWithTTL stores the given value for the given key, like Set,
+// but with an expiry that Redis enforces itself (SET with EX or PX).
+// After the TTL, Get returns (false, nil) for the key.
+// The key must not be "", the value must not be nil and the TTL must be positive.
+func (c Client) SetWithTTL(k string, v any, ttl time.Duration) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return err
+	}
+
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	err = c.c.Set(tctx, k, string(data), ttl).Err()
+	if c.cache != nil {
+		// Redis also sends an invalidation message, but that arrives asynchronously.
+		c.cache.invalidate(k)
+	}
+	return err
+}
+
+// SetIfAbsentWithTTL stores the given value for the given key with an expiry,
+// but only if the key doesn't exist yet (SET with NX and PX, which is atomic without a script).
+// It returns whether the value was stored, so for example only one of several concurrent callers
+// acquires a key for deduplication or for a time window.
+// The key must not be "", the value must not be nil and the TTL must be positive.
+func (c Client) SetIfAbsentWithTTL(k string, v any, ttl time.Duration) (set bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+	if err := util.CheckTTL(ttl); err != nil {
+		return false, err
+	}
+
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	set, err = c.c.SetNX(tctx, k, string(data), ttl).Result()
+	if c.cache != nil && set {
+		c.cache.invalidate(k)
+	}
+	return set, err
+}
+
+// TTL returns the remaining time until the key-value pair for the given key expires (PTTL).
+// If the key-value pair doesn't expire, the TTL is 0.
+// If no value is found it returns (0, false, nil).
+// The key must not be "".
+func (c Client) TTL(k string) (ttl time.Duration, found bool, err error) {
+	if err := util.CheckKey(k); err != nil {
+		return 0, false, err
+	}
+
+	tctx, cancel := context.WithTimeout(context.Background(), c.timeOut)
+	defer cancel()
+
+	ttl, err = c.c.PTTL(tctx, k).Result()
+	if err != nil {
+		return 0, false, err
+	}
+	// Redis replies with -2 for a non-existing key and with -1 for a key without expiry
+	switch ttl {
+	case -2:
+		return 0, false, nil
+	case -1:
+		return 0, true, nil
+	}
+	return ttl, true, nil
+}
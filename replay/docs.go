@@ -0,0 +1,7 @@
+/*
+Package replay contains a `gokv.Store` wrapper that records all operations and their results,
+and a `gokv.Store` implementation that replays such a recording without a real backend.
+
+This is useful for reproducing a bug that occurred with a production store in a deterministic test.
+*/
+package replay
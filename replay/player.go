@@ -0,0 +1,161 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// ErrMismatch is returned by the Player when an operation doesn't match the next recorded operation for the key.
+var ErrMismatch = errors.New("The operation doesn't match the recording")
+
+// Player is a gokv.Store implementation that replays a recording of a Recorder.
+//
+// Each operation is matched against the next recorded operation for the same key,
+// so the order of operations for different keys doesn't matter,
+// which makes the replay work with recordings of concurrent operations.
+// The operation type must match, and for Set also the value (in its JSON form).
+// Get returns the recorded value, found and error, Set and Delete return the recorded error.
+// Operations that don't match or that exceed the recording lead to an error wrapping ErrMismatch.
+type Player struct {
+	lock *sync.Mutex
+	// Remaining records per key, in the recorded order.
+	records map[string][]record
+	// Error that occurred while reading the recording.
+	readErr error
+}
+
+// Set compares the given key and value with the next recorded operation for the key
+// and returns the recorded error.
+// The key must not be "" and the value must not be nil.
+func (p Player) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	rec, err := p.next(OpSet, k, data)
+	if err != nil {
+		return err
+	}
+	return recordedErr(rec)
+}
+
+// Get returns the recorded result of the next recorded operation for the key,
+// after unmarshalling the recorded value into v.
+// The key must not be "" and the pointer must not be nil.
+func (p Player) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	rec, err := p.next(OpGet, k, nil)
+	if err != nil {
+		return false, err
+	}
+	if rec.Value != nil {
+		if err = json.Unmarshal(rec.Value, v); err != nil {
+			return false, err
+		}
+	}
+	return rec.Found, recordedErr(rec)
+}
+
+// Delete returns the recorded error of the next recorded operation for the key.
+// The key must not be "".
+func (p Player) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	rec, err := p.next(OpDelete, k, nil)
+	if err != nil {
+		return err
+	}
+	return recordedErr(rec)
+}
+
+// Close does nothing, it only exists to implement the gokv.Store interface.
+func (p Player) Close() error {
+	return nil
+}
+
+// Remaining returns the number of recorded operations that haven't been replayed yet.
+// A test can use it to check that the replay covered the whole recording.
+func (p Player) Remaining() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	result := 0
+	for _, records := range p.records {
+		result += len(records)
+	}
+	return result
+}
+
+// next removes and returns the next record for the given key,
+// if it's for the given operation and, in case of OpSet, the given value.
+// A record that doesn't match is left in place.
+func (p Player) next(op, k string, value []byte) (record, error) {
+	if p.readErr != nil {
+		return record{}, p.readErr
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	records := p.records[k]
+	if len(records) == 0 {
+		return record{}, fmt.Errorf("%w: %v of key %q, but there are no more recorded operations for the key", ErrMismatch, op, k)
+	}
+	rec := records[0]
+	if rec.Op != op {
+		return record{}, fmt.Errorf("%w: %v of key %q, but the recorded operation is %v", ErrMismatch, op, k, rec.Op)
+	}
+	if op == OpSet && !bytes.Equal(value, rec.Value) {
+		return record{}, fmt.Errorf("%w: %v of key %q with value %s, but the recorded value is %s", ErrMismatch, op, k, value, rec.Value)
+	}
+	p.records[k] = records[1:]
+	return rec, nil
+}
+
+// recordedErr returns an error with the recorded error message, if any.
+func recordedErr(rec record) error {
+	if rec.Err == "" {
+		return nil
+	}
+	return errors.New(rec.Err)
+}
+
+// NewPlayer creates a new Player that replays the recording from the given reader.
+// The whole recording is read immediately.
+// If reading fails, all operations return the error.
+func NewPlayer(r io.Reader) Player {
+	result := Player{
+		lock:    new(sync.Mutex),
+		records: make(map[string][]record),
+	}
+
+	dec := json.NewDecoder(r)
+	for {
+		var rec record
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			result.readErr = fmt.Errorf("The recording couldn't be read: %w", err)
+			break
+		}
+		result.records[rec.Key] = append(result.records[rec.Key], rec)
+	}
+
+	return result
+}
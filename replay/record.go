@@ -0,0 +1,27 @@
+package replay
+
+import "encoding/json"
+
+// Operation names that are used in the recording.
+const (
+	OpSet    = "set"
+	OpGet    = "get"
+	OpDelete = "delete"
+)
+
+// record is one operation in the recording.
+// A recording consists of one JSON object per line, for example:
+//
+//	{"op":"set","key":"foo","value":{"Bar":"baz"}}
+//	{"op":"get","key":"foo","value":{"Bar":"baz"},"found":true}
+//	{"op":"get","key":"bar","err":"connection refused"}
+type record struct {
+	Op  string `json:"op"`
+	Key string `json:"key"`
+	// For OpSet the value that was passed, for OpGet the value that was retrieved, marshalled to JSON.
+	Value json.RawMessage `json:"value,omitempty"`
+	// Only for OpGet.
+	Found bool `json:"found,omitempty"`
+	// Error message of the inner store, if any.
+	Err string `json:"err,omitempty"`
+}
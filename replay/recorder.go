@@ -0,0 +1,133 @@
+package replay
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+// Recorder is a gokv.Store implementation that forwards all calls to another store
+// and records each operation and its result.
+// The recording can be replayed with a Player.
+type Recorder struct {
+	inner gokv.Store
+	// For writing one record at a time.
+	lock *sync.Mutex
+	enc  *json.Encoder
+	// The first error that occurred during recording.
+	recordErr *error
+}
+
+// Set stores the given value for the given key in the inner store and records the operation.
+// The key must not be "" and the value must not be nil.
+func (r Recorder) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	err := r.inner.Set(k, v)
+	r.record(OpSet, k, v, false, err)
+	return err
+}
+
+// Get retrieves the stored value for the given key from the inner store and records the operation,
+// including the retrieved value.
+// You need to pass a pointer to the value, so in case of a struct
+// the automatic unmarshalling can populate the fields of the object
+// that v points to with the values of the retrieved object's values.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (r Recorder) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	found, err = r.inner.Get(k, v)
+	var value any
+	if found && err == nil {
+		value = v
+	}
+	r.record(OpGet, k, value, found, err)
+	return found, err
+}
+
+// Delete deletes the stored value for the given key in the inner store and records the operation.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (r Recorder) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	err := r.inner.Delete(k)
+	r.record(OpDelete, k, nil, false, err)
+	return err
+}
+
+// Close closes the inner store.
+// Errors that occurred during recording don't affect the results of the other methods,
+// so they're returned here instead, to not silently end up with an incomplete recording.
+// Closing the writer is up to the caller.
+func (r Recorder) Close() error {
+	err := r.inner.Close()
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return errors.Join(err, *r.recordErr)
+}
+
+// record writes a record of the operation.
+// The operations are recorded in the order in which they finish.
+func (r Recorder) record(op, k string, v any, found bool, err error) {
+	rec := record{
+		Op:    op,
+		Key:   k,
+		Found: found,
+	}
+	if v != nil {
+		data, marshalErr := json.Marshal(v)
+		if marshalErr != nil {
+			r.setRecordErr(marshalErr)
+			return
+		}
+		rec.Value = data
+	}
+	if err != nil {
+		rec.Err = err.Error()
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if *r.recordErr != nil {
+		// Following records would be misleading with a gap in the recording
+		return
+	}
+	*r.recordErr = r.enc.Encode(rec)
+}
+
+func (r Recorder) setRecordErr(err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if *r.recordErr == nil {
+		*r.recordErr = err
+	}
+}
+
+// NewRecorder creates a new Recorder that wraps the given store
+// and writes the recording as JSON Lines to the given writer.
+// The values are marshalled to JSON for the recording, independent of the inner store's codec,
+// so they must be marshallable to JSON.
+//
+// You must call the Close() method on the recorder when you're done working with it.
+func NewRecorder(inner gokv.Store, w io.Writer) Recorder {
+	return Recorder{
+		inner:     inner,
+		lock:      new(sync.Mutex),
+		enc:       json.NewEncoder(w),
+		recordErr: new(error),
+	}
+}
@@ -0,0 +1,225 @@
+package replay_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/replay"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestStore(t *testing.T) {
+	store := replay.NewRecorder(gomap.NewStore(gomap.DefaultOptions), io.Discard)
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := replay.NewRecorder(gomap.NewStore(gomap.DefaultOptions), io.Discard)
+	test.TestTypes(store, t)
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	store := replay.NewRecorder(gomap.NewStore(gomap.DefaultOptions), io.Discard)
+
+	goroutineCount := 1000
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+}
+
+// TestReplay tests that a recorded session replays to identical results without the inner store.
+func TestReplay(t *testing.T) {
+	buf := new(bytes.Buffer)
+	inner := &failingStore{Store: gomap.NewStore(gomap.DefaultOptions)}
+	recorder := replay.NewRecorder(inner, buf)
+
+	// session runs the same operations against the recorder and the player
+	// and returns the results of the Get calls.
+	session := func(store gokv.Store, failing bool) []any {
+		var results []any
+		get := func(k string) {
+			actual := test.Foo{}
+			found, err := store.Get(k, &actual)
+			results = append(results, actual, found, err)
+		}
+		if err := store.Set("foo", test.Foo{Bar: "baz"}); err != nil {
+			t.Fatal(err)
+		}
+		get("foo")
+		get("missing")
+		if err := store.Set("foo", test.Foo{Bar: "qux"}); err != nil {
+			t.Fatal(err)
+		}
+		get("foo")
+		if err := store.Delete("foo"); err != nil {
+			t.Fatal(err)
+		}
+		get("foo")
+		if failing {
+			inner.err = errors.New("connection refused")
+		}
+		get("bar")
+		if err := store.Set("bar", test.Foo{Bar: "baz"}); err == nil || err.Error() != "connection refused" {
+			t.Errorf("Expected the recorded error, but was %v", err)
+		}
+		return results
+	}
+
+	expected := session(recorder, true)
+	err := recorder.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	player := replay.NewPlayer(bytes.NewReader(buf.Bytes()))
+	actual := session(player, false)
+	if diff := deep.Equal(actual, expected); diff != nil {
+		t.Error(diff)
+	}
+	if remaining := player.Remaining(); remaining != 0 {
+		t.Errorf("Expected all operations to be replayed, but %v remain", remaining)
+	}
+}
+
+// TestMismatch tests that operations that don't match the recording lead to an error.
+func TestMismatch(t *testing.T) {
+	buf := new(bytes.Buffer)
+	recorder := replay.NewRecorder(gomap.NewStore(gomap.DefaultOptions), buf)
+	err := recorder.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = recorder.Set("bar", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = recorder.Get("bar", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	player := replay.NewPlayer(bytes.NewReader(buf.Bytes()))
+	// Different value
+	err = player.Set("foo", test.Foo{Bar: "qux"})
+	if !errors.Is(err, replay.ErrMismatch) {
+		t.Errorf("Expected replay.ErrMismatch, but was %v", err)
+	}
+	// Different operation
+	err = player.Delete("foo")
+	if !errors.Is(err, replay.ErrMismatch) {
+		t.Errorf("Expected replay.ErrMismatch, but was %v", err)
+	}
+	// Operations for different keys can be replayed in a different order
+	_, err = player.Get("bar", new(test.Foo))
+	if !errors.Is(err, replay.ErrMismatch) {
+		t.Errorf("Expected replay.ErrMismatch, but was %v", err)
+	}
+	err = player.Set("bar", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Error(err)
+	}
+	_, err = player.Get("bar", new(test.Foo))
+	if err != nil {
+		t.Error(err)
+	}
+	// Exceeding the recording
+	_, err = player.Get("bar", new(test.Foo))
+	if !errors.Is(err, replay.ErrMismatch) {
+		t.Errorf("Expected replay.ErrMismatch, but was %v", err)
+	}
+	if remaining := player.Remaining(); remaining != 1 {
+		t.Errorf("Expected 1 remaining operation, but there were %v", remaining)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	// Test empty key
+	recorder := replay.NewRecorder(gomap.NewStore(gomap.DefaultOptions), io.Discard)
+	player := replay.NewPlayer(strings.NewReader(""))
+	for _, store := range []gokv.Store{recorder, player} {
+		err := store.Set("", "bar")
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		_, err = store.Get("", new(string))
+		if err == nil {
+			t.Error("Expected an error")
+		}
+		err = store.Delete("")
+		if err == nil {
+			t.Error("Expected an error")
+		}
+	}
+
+	// Values that can't be recorded (but stored with gob)
+	recorder = replay.NewRecorder(gomap.NewStore(gomap.Options{Codec: encoding.Gob}), io.Discard)
+	err := recorder.Set("foo", math.Inf(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = recorder.Close()
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Invalid recording
+	player = replay.NewPlayer(strings.NewReader("{\"op\":\"set\""))
+	_, err = player.Get("foo", new(string))
+	if err == nil || errors.Is(err, replay.ErrMismatch) {
+		t.Errorf("Expected an error about the invalid recording, but was %v", err)
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	recorder := replay.NewRecorder(gomap.NewStore(gomap.DefaultOptions), io.Discard)
+	err := recorder.Close()
+	if err != nil {
+		t.Error(err)
+	}
+	player := replay.NewPlayer(strings.NewReader(""))
+	err = player.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// failingStore is a gokv.Store that returns an error for all calls once err is set.
+type failingStore struct {
+	gokv.Store
+	err error
+}
+
+func (s *failingStore) Set(k string, v any) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.Store.Set(k, v)
+}
+
+func (s *failingStore) Get(k string, v any) (bool, error) {
+	if s.err != nil {
+		return false, s.err
+	}
+	return s.Store.Get(k, v)
+}
+
+func (s *failingStore) Delete(k string) error {
+	if s.err != nil {
+		return s.err
+	}
+	return s.Store.Delete(k)
+}
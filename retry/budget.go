@@ -0,0 +1,82 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+// Number of buckets the budget window is divided into.
+// More buckets make the window slide more smoothly.
+const budgetBuckets = 10
+
+type budgetBucket struct {
+	start    time.Time
+	requests int
+	retries  int
+}
+
+// budget limits the ratio of retries to requests within a sliding window,
+// similar to gRPC's retry throttling.
+// Without it, every failing request leads to multiple requests during an outage,
+// which can keep the backend from recovering.
+type budget struct {
+	lock       *sync.Mutex
+	ratio      float64
+	minRetries int
+	window     time.Duration
+	buckets    []budgetBucket
+}
+
+func newBudget(ratio float64, minRetries int, window time.Duration) *budget {
+	return &budget{
+		lock:       new(sync.Mutex),
+		ratio:      ratio,
+		minRetries: minRetries,
+		window:     window,
+		buckets:    make([]budgetBucket, budgetBuckets),
+	}
+}
+
+// request records a request, no matter if it's successful or not.
+func (b *budget) request() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.bucket(time.Now()).requests++
+}
+
+// tryRetry returns true and records the retry if the budget allows another retry.
+func (b *budget) tryRetry() bool {
+	now := time.Now()
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	requests, retries := 0, 0
+	for _, bucket := range b.buckets {
+		if now.Sub(bucket.start) < b.window {
+			requests += bucket.requests
+			retries += bucket.retries
+		}
+	}
+	if float64(retries) >= float64(b.minRetries)+b.ratio*float64(requests) {
+		return false
+	}
+	b.bucket(now).retries++
+	return true
+}
+
+// bucket returns the bucket for the given time, resetting it if it still contains counts from a previous window.
+// The caller must hold the lock.
+func (b *budget) bucket(now time.Time) *budgetBucket {
+	bucketDuration := b.window / budgetBuckets
+	if bucketDuration <= 0 {
+		bucketDuration = 1
+	}
+	start := now.Truncate(bucketDuration)
+	bucket := &b.buckets[(start.UnixNano()/int64(bucketDuration))%budgetBuckets]
+	if !bucket.start.Equal(start) {
+		*bucket = budgetBucket{start: start}
+	}
+	return bucket
+}
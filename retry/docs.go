@@ -0,0 +1,5 @@
+/*
+Package retry contains a `gokv.Store` wrapper that retries failed operations of another store,
+optionally limited by a retry budget to prevent retry storms during an outage.
+*/
+package retry
@@ -0,0 +1,253 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+var defaultBudgetMinRetries = 10
+
+// Store is a gokv.Store implementation that forwards all calls to another store
+// and retries failed calls with exponential backoff.
+// It also implements gokv.StoreCtx.
+type Store struct {
+	inner gokv.Store
+	// The inner store, or a wrapper that checks the context if the inner store isn't context-aware
+	innerCtx       gokv.StoreCtx
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         float64
+	retryable      func(err error) bool
+	// nil if no retry budget is configured
+	budget *budget
+}
+
+// Set stores the given value for the given key in the inner store.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	return s.SetCtx(context.Background(), k, v)
+}
+
+// SetCtx is like Set, but the context is passed to the inner store if it implements gokv.StoreCtx.
+// No more attempts are made when the context is canceled
+// or when its deadline would be exceeded while waiting for the next attempt.
+func (s Store) SetCtx(ctx context.Context, k string, v any) error {
+	// Invalid arguments would fail again, so they're not passed to the inner store.
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	return s.do(ctx, func() error {
+		return s.innerCtx.SetCtx(ctx, k, v)
+	})
+}
+
+// Get retrieves the stored value for the given key from the inner store.
+// If no value is found it returns (false, nil), which doesn't lead to a retry.
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	return s.GetCtx(context.Background(), k, v)
+}
+
+// GetCtx is like Get, but the context is passed to the inner store if it implements gokv.StoreCtx.
+// No more attempts are made when the context is canceled
+// or when its deadline would be exceeded while waiting for the next attempt.
+func (s Store) GetCtx(ctx context.Context, k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	err = s.do(ctx, func() error {
+		var err error
+		found, err = s.innerCtx.GetCtx(ctx, k, v)
+		return err
+	})
+	return found, err
+}
+
+// Delete deletes the stored value for the given key in the inner store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	return s.DeleteCtx(context.Background(), k)
+}
+
+// DeleteCtx is like Delete, but the context is passed to the inner store if it implements gokv.StoreCtx.
+// No more attempts are made when the context is canceled
+// or when its deadline would be exceeded while waiting for the next attempt.
+func (s Store) DeleteCtx(ctx context.Context, k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	return s.do(ctx, func() error {
+		return s.innerCtx.DeleteCtx(ctx, k)
+	})
+}
+
+// Close closes the inner store.
+// It's not retried.
+func (s Store) Close() error {
+	return s.inner.Close()
+}
+
+// do calls the operation until it succeeds, the maximum number of attempts is reached,
+// the error isn't retryable, the retry budget is exhausted or the context is done,
+// and returns the error of the last attempt.
+func (s Store) do(ctx context.Context, op func() error) error {
+	if s.budget != nil {
+		s.budget.request()
+	}
+
+	backoff := s.initialBackoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = op()
+		if err == nil || attempt >= s.maxAttempts {
+			return err
+		}
+		if s.retryable != nil && !s.retryable(err) {
+			return err
+		}
+		wait := s.withJitter(backoff)
+		// Don't wait for an attempt that can't be made anymore
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return err
+		}
+		// Fail fast when too many requests are retried already
+		if s.budget != nil && !s.budget.tryRetry() {
+			return err
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		backoff *= 2
+		if backoff > s.maxBackoff {
+			backoff = s.maxBackoff
+		}
+	}
+}
+
+// withJitter reduces the backoff by a random fraction of up to the configured jitter,
+// so that clients that failed at the same time don't retry at the same time.
+func (s Store) withJitter(backoff time.Duration) time.Duration {
+	if s.jitter == 0 {
+		return backoff
+	}
+	return backoff - time.Duration(s.jitter*rand.Float64()*float64(backoff))
+}
+
+// Options are the options for the retry store.
+type Options struct {
+	// Maximum number of attempts per operation, including the first one.
+	// Optional (3 by default).
+	MaxAttempts int
+	// Time to wait before the first retry.
+	// It's doubled for each following retry.
+	// Optional (10ms by default).
+	InitialBackoff time.Duration
+	// Maximum time to wait between two attempts.
+	// Optional (1s by default).
+	MaxBackoff time.Duration
+	// Maximum fraction by which each wait is randomly shortened, between 0 and 1.
+	// For example with 0.5 and a backoff of 100ms, the store waits between 50ms and 100ms.
+	// This spreads out the retries of many clients that failed at the same time,
+	// for example due to throttling, instead of all of them retrying at once.
+	// Optional (0 by default, meaning no jitter).
+	Jitter float64
+	// Decides if a failed operation is retried, based on its error.
+	// For example errors that would occur again, like authorization errors, shouldn't be retried.
+	// Invalid keys and values are never passed to the inner store, so they're not retried anyway.
+	// Optional (nil by default, meaning all errors are retried).
+	Retryable func(err error) bool
+	// Maximum ratio of retries to requests within the BudgetWindow, e.g. 0.1 for 10%.
+	// When the retry budget is exhausted, failed operations are not retried anymore
+	// until enough requests went through.
+	// This prevents retry storms from amplifying the load on the inner store during an outage.
+	// Optional (0 by default, meaning no retry budget).
+	BudgetRatio float64
+	// Number of retries that are allowed within the BudgetWindow in addition to the ratio,
+	// so that retries are possible when there are only a few requests.
+	// Set to 0 to only allow retries according to the ratio.
+	// Only used when BudgetRatio is > 0.
+	// Optional (10 by default).
+	BudgetMinRetries *int
+	// Duration of the sliding window in which requests and retries are counted for the retry budget.
+	// Only used when BudgetRatio is > 0.
+	// Optional (10s by default).
+	BudgetWindow time.Duration
+}
+
+// DefaultOptions is an Options object with default values.
+// MaxAttempts: 3, InitialBackoff: 10ms, MaxBackoff: 1s, Jitter: 0, Retryable: nil,
+// BudgetRatio: 0, BudgetMinRetries: 10, BudgetWindow: 10s
+var DefaultOptions = Options{
+	MaxAttempts:      3,
+	InitialBackoff:   10 * time.Millisecond,
+	MaxBackoff:       time.Second,
+	BudgetMinRetries: &defaultBudgetMinRetries,
+	BudgetWindow:     10 * time.Second,
+	// No need to set Jitter, Retryable and BudgetRatio because their zero values are fine.
+}
+
+// NewStore creates a new retry store that wraps the given store.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(inner gokv.Store, options Options) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if inner == nil {
+		return result, errors.New("The inner store must not be nil")
+	}
+	if options.Jitter < 0 || options.Jitter > 1 {
+		return result, errors.New("The Jitter must be between 0 and 1")
+	}
+	if options.BudgetRatio < 0 {
+		return result, errors.New("The BudgetRatio must not be negative")
+	}
+	if options.BudgetMinRetries != nil && *options.BudgetMinRetries < 0 {
+		return result, errors.New("The BudgetMinRetries must not be negative")
+	}
+
+	// Set default values
+	if options.MaxAttempts <= 0 {
+		options.MaxAttempts = DefaultOptions.MaxAttempts
+	}
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = DefaultOptions.InitialBackoff
+	}
+	if options.MaxBackoff <= 0 {
+		options.MaxBackoff = DefaultOptions.MaxBackoff
+	}
+	if options.BudgetMinRetries == nil {
+		options.BudgetMinRetries = DefaultOptions.BudgetMinRetries
+	}
+	if options.BudgetWindow <= 0 {
+		options.BudgetWindow = DefaultOptions.BudgetWindow
+	}
+
+	result.inner = inner
+	result.innerCtx = util.WrapStoreCtx(inner)
+	result.maxAttempts = options.MaxAttempts
+	result.initialBackoff = options.InitialBackoff
+	result.maxBackoff = options.MaxBackoff
+	result.jitter = options.Jitter
+	result.retryable = options.Retryable
+	if options.BudgetRatio > 0 {
+		result.budget = newBudget(options.BudgetRatio, *options.BudgetMinRetries, options.BudgetWindow)
+	}
+
+	return result, nil
+}
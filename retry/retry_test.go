@@ -0,0 +1,358 @@
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/retry"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestStore(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), retry.DefaultOptions)
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), retry.DefaultOptions)
+	test.TestTypes(store, t)
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	// With a retry budget, which is shared by all goroutines
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), retry.Options{
+		BudgetRatio: 0.1,
+	})
+
+	goroutineCount := 1000
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+}
+
+// TestRetry tests that failed calls are retried until the maximum number of attempts.
+func TestRetry(t *testing.T) {
+	inner := newFlakyStore()
+	store := createStore(t, inner, retry.Options{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+	})
+
+	// Two failures followed by a success
+	inner.failures.Store(2)
+	err := store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectCalls(t, inner, 3)
+
+	inner.failures.Store(2)
+	actual := test.Foo{}
+	found, err := store.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", test.Foo{Bar: "baz"}, found, actual)
+	}
+	expectCalls(t, inner, 6)
+
+	// Not finding a value is not a failure
+	found, err = store.Get("bar", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+	expectCalls(t, inner, 7)
+
+	// More failures than attempts
+	inner.failures.Store(3)
+	err = store.Delete("foo")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	expectCalls(t, inner, 10)
+}
+
+// TestRetryable tests that errors that aren't retryable according to the predicate are returned immediately.
+func TestRetryable(t *testing.T) {
+	inner := newFlakyStore()
+	inner.failAll = true
+	store := createStore(t, inner, retry.Options{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Jitter:         0.5,
+		Retryable: func(err error) bool {
+			return err.Error() != "unavailable"
+		},
+	})
+
+	err := store.Set("foo", "bar")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expectCalls(t, inner, 1)
+
+	// Other errors are retried
+	store = createStore(t, inner, retry.Options{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		Jitter:         0.5,
+		Retryable: func(err error) bool {
+			return err.Error() != "forbidden"
+		},
+	})
+	err = store.Set("foo", "bar")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expectCalls(t, inner, 4)
+}
+
+// TestContext tests that no more attempts are made when the context is done
+// or when its deadline would be exceeded before the next attempt.
+func TestContext(t *testing.T) {
+	inner := newFlakyStore()
+	inner.failAll = true
+	store := createStore(t, inner, retry.Options{
+		MaxAttempts:    5,
+		InitialBackoff: time.Second,
+	})
+
+	// The deadline is before the first retry
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := store.SetCtx(ctx, "foo", "bar")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected to return before the deadline, but it took %v", elapsed)
+	}
+	expectCalls(t, inner, 1)
+
+	// Canceling the context stops waiting for the next attempt
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		cancel()
+	}()
+	start = time.Now()
+	_, err = store.GetCtx(ctx, "foo", new(string))
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Expected to return when the context is canceled, but it took %v", elapsed)
+	}
+	expectCalls(t, inner, 2)
+
+	// An already canceled context isn't passed to the inner store
+	err = store.DeleteCtx(ctx, "foo")
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, but was %v", err)
+	}
+	expectCalls(t, inner, 2)
+}
+
+// TestBudget tests that under sustained failure the retry budget is exhausted
+// and following calls fail fast without retrying.
+func TestBudget(t *testing.T) {
+	inner := newFlakyStore()
+	inner.failAll = true
+	store := createStore(t, inner, retry.Options{
+		MaxAttempts:      4,
+		InitialBackoff:   time.Microsecond,
+		BudgetRatio:      0.1,
+		BudgetMinRetries: intPtr(5),
+		BudgetWindow:     time.Minute,
+	})
+
+	// The first call can use the retries that are always allowed
+	err := store.Set("foo", "bar")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expectCalls(t, inner, 4)
+
+	requests := 100
+	for i := 1; i < requests; i++ {
+		err = store.Set("foo", "bar")
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	}
+	// 100 requests plus at most 5 + 10% retries, instead of 400 calls without a budget.
+	calls := inner.calls.Load()
+	if calls > int64(requests+5+requests/10) {
+		t.Errorf("Expected the retry budget to limit the calls to the inner store, but there were %v calls", calls)
+	}
+
+	// With the budget exhausted, following calls fail fast.
+	// The budget only grows by 10% of the requests, so at most one of 10 calls can be retried.
+	for i := 0; i < 10; i++ {
+		err = store.Set("foo", "bar")
+		if err == nil {
+			t.Fatal("Expected an error")
+		}
+	}
+	if actual := inner.calls.Load(); actual > calls+11 {
+		t.Errorf("Expected at most %v calls to the inner store, but there were %v", calls+11, actual)
+	}
+}
+
+// TestBudgetWithoutMinRetries tests that with BudgetMinRetries set to 0 explicitly,
+// instead of the default being used, only the ratio allows retries.
+func TestBudgetWithoutMinRetries(t *testing.T) {
+	inner := newFlakyStore()
+	inner.failAll = true
+	store := createStore(t, inner, retry.Options{
+		MaxAttempts:      4,
+		InitialBackoff:   time.Microsecond,
+		BudgetRatio:      0.1,
+		BudgetMinRetries: intPtr(0),
+		BudgetWindow:     time.Minute,
+	})
+
+	// 10% of one request allow a single retry
+	err := store.Set("foo", "bar")
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	expectCalls(t, inner, 2)
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	inner := newFlakyStore()
+	store := createStore(t, inner, retry.DefaultOptions)
+
+	// Invalid arguments aren't retried and not even passed to the inner store
+	err := store.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Set("foo", nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	expectCalls(t, inner, 0)
+
+	// Invalid options
+	_, err = retry.NewStore(nil, retry.DefaultOptions)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = retry.NewStore(inner, retry.Options{BudgetRatio: -1})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = retry.NewStore(inner, retry.Options{BudgetRatio: 0.1, BudgetMinRetries: intPtr(-1)})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = retry.NewStore(inner, retry.Options{Jitter: 1.5})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestClose tests if the close method returns any errors.
+func TestClose(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), retry.DefaultOptions)
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// flakyStore is a gokv.Store that counts the calls of Set, Get and Delete
+// and lets them fail on demand.
+type flakyStore struct {
+	gokv.Store
+	calls *atomic.Int64
+	// Number of following calls that fail
+	failures *atomic.Int64
+	// If true, all calls fail
+	failAll bool
+}
+
+func newFlakyStore() *flakyStore {
+	return &flakyStore{
+		Store:    gomap.NewStore(gomap.DefaultOptions),
+		calls:    &atomic.Int64{},
+		failures: &atomic.Int64{},
+	}
+}
+
+func (s *flakyStore) fail() error {
+	s.calls.Add(1)
+	if s.failures.Add(-1) >= 0 {
+		return errors.New("unavailable")
+	}
+	s.failures.Store(0)
+	if s.failAll {
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func (s *flakyStore) Set(k string, v any) error {
+	if err := s.fail(); err != nil {
+		return err
+	}
+	return s.Store.Set(k, v)
+}
+
+func (s *flakyStore) Get(k string, v any) (bool, error) {
+	if err := s.fail(); err != nil {
+		return false, err
+	}
+	return s.Store.Get(k, v)
+}
+
+func (s *flakyStore) Delete(k string) error {
+	if err := s.fail(); err != nil {
+		return err
+	}
+	return s.Store.Delete(k)
+}
+
+func expectCalls(t *testing.T, inner *flakyStore, expected int64) {
+	t.Helper()
+
+	if actual := inner.calls.Load(); actual != expected {
+		t.Errorf("Expected %v calls to the inner store, but there were %v", expected, actual)
+	}
+}
+
+func createStore(t *testing.T, inner gokv.Store, options retry.Options) retry.Store {
+	store, err := retry.NewStore(inner, options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store
+}
+
+func intPtr(i int) *int {
+	return &i
+}
@@ -0,0 +1,5 @@
+/*
+Package rw contains a `gokv.Store` wrapper that routes writes and reads to different stores,
+for example to a primary and a read replica.
+*/
+package rw
@@ -0,0 +1,171 @@
+package rw
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/encoding"
+	"github.com/philippgille/gokv/util"
+)
+
+// recentWrite is a write that's kept for answering reads until the reader caught up.
+type recentWrite struct {
+	// nil for a deletion
+	data    []byte
+	expires time.Time
+}
+
+// Store is a gokv.Store implementation that sends Set and Delete to a writer store
+// and Get to a reader store.
+type Store struct {
+	writer gokv.Store
+	reader gokv.Store
+	window time.Duration
+	codec  encoding.Codec
+	// Only used when window > 0.
+	lock      *sync.Mutex
+	recent    map[string]recentWrite
+	lastSweep *time.Time
+}
+
+// Set stores the given value for the given key in the writer store.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	if s.window <= 0 {
+		return s.writer.Set(k, v)
+	}
+
+	data, err := s.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if err = s.writer.Set(k, v); err != nil {
+		return err
+	}
+	s.remember(k, data)
+	return nil
+}
+
+// Get retrieves the stored value for the given key from the reader store.
+// With read-your-writes enabled, values that were written via this store within the configured window
+// are returned without reading from the reader store.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	if s.window > 0 {
+		s.lock.Lock()
+		w, ok := s.recent[k]
+		if ok && time.Now().After(w.expires) {
+			delete(s.recent, k)
+			ok = false
+		}
+		s.lock.Unlock()
+		if ok {
+			if w.data == nil {
+				return false, nil
+			}
+			return true, s.codec.Unmarshal(w.data, v)
+		}
+	}
+
+	return s.reader.Get(k, v)
+}
+
+// Delete deletes the stored value for the given key in the writer store.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	if err := s.writer.Delete(k); err != nil {
+		return err
+	}
+	if s.window > 0 {
+		s.remember(k, nil)
+	}
+	return nil
+}
+
+// Close closes both the writer and the reader store.
+func (s Store) Close() error {
+	return errors.Join(s.writer.Close(), s.reader.Close())
+}
+
+// remember keeps the write for the configured window
+// and removes expired writes from time to time.
+func (s Store) remember(k string, data []byte) {
+	now := time.Now()
+
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.recent[k] = recentWrite{
+		data:    data,
+		expires: now.Add(s.window),
+	}
+	if now.Sub(*s.lastSweep) < s.window {
+		return
+	}
+	for key, w := range s.recent {
+		if now.After(w.expires) {
+			delete(s.recent, key)
+		}
+	}
+	*s.lastSweep = now
+}
+
+// Options are the options for the rw store.
+type Options struct {
+	// Duration for which values written via this store are returned by Get
+	// without reading from the reader store.
+	// This enables reading your own writes while the reader lags behind the writer,
+	// for example due to asynchronous replication.
+	// It only covers writes via the same Store object.
+	// Should be a bit longer than the usual replication lag.
+	// Optional (0 by default, meaning disabled).
+	ReadYourWritesWindow time.Duration
+	// Encoding format for keeping the recently written values.
+	// Only used when ReadYourWritesWindow is > 0.
+	// Optional (encoding.JSON by default).
+	Codec encoding.Codec
+}
+
+// DefaultOptions is an Options object with default values.
+// ReadYourWritesWindow: 0, Codec: encoding.JSON
+var DefaultOptions = Options{
+	Codec: encoding.JSON,
+	// No need to set ReadYourWritesWindow because its zero value is fine.
+}
+
+// NewStore creates a new rw store that sends Set and Delete to the writer
+// and Get to the reader, for example a primary and a read replica of the same database.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(writer, reader gokv.Store, options Options) Store {
+	// Set default values
+	if options.Codec == nil {
+		options.Codec = DefaultOptions.Codec
+	}
+
+	return Store{
+		writer:    writer,
+		reader:    reader,
+		window:    options.ReadYourWritesWindow,
+		codec:     options.Codec,
+		lock:      new(sync.Mutex),
+		recent:    make(map[string]recentWrite),
+		lastSweep: new(time.Time),
+	}
+}
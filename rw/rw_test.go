@@ -0,0 +1,168 @@
+package rw_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/rw"
+	"github.com/philippgille/gokv/test"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// The same store is used as writer and reader, like a database without replica.
+func TestStore(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	store := rw.NewStore(inner, inner, rw.DefaultOptions)
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	store := rw.NewStore(inner, inner, rw.Options{ReadYourWritesWindow: time.Minute})
+	test.TestTypes(store, t)
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	store := rw.NewStore(inner, inner, rw.Options{ReadYourWritesWindow: time.Minute})
+
+	goroutineCount := 1000
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+}
+
+// TestRouting tests that writes land in the writer and reads come from the reader.
+func TestRouting(t *testing.T) {
+	writer := gomap.NewStore(gomap.DefaultOptions)
+	reader := gomap.NewStore(gomap.DefaultOptions)
+	store := rw.NewStore(writer, reader, rw.DefaultOptions)
+
+	// Seed the reader independently
+	err := reader.Set("foo", test.Foo{Bar: "from reader"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Set("foo", test.Foo{Bar: "from writer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValue(t, writer, "foo", "from writer")
+	expectValue(t, reader, "foo", "from reader")
+	expectValue(t, store, "foo", "from reader")
+
+	err = store.Delete("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValue(t, store, "foo", "from reader")
+	found, err := writer.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found in the writer, but no value was expected")
+	}
+}
+
+// TestReadYourWrites tests that recent writes are returned until the window passed.
+func TestReadYourWrites(t *testing.T) {
+	writer := gomap.NewStore(gomap.DefaultOptions)
+	reader := gomap.NewStore(gomap.DefaultOptions)
+	store := rw.NewStore(writer, reader, rw.Options{ReadYourWritesWindow: 100 * time.Millisecond})
+
+	err := reader.Set("foo", test.Foo{Bar: "from reader"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = reader.Set("bar", test.Foo{Bar: "from reader"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = store.Set("foo", test.Foo{Bar: "from writer"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectValue(t, store, "foo", "from writer")
+	err = store.Delete("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := store.Get("bar", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	expectValue(t, store, "foo", "from reader")
+	expectValue(t, store, "bar", "from reader")
+}
+
+// TestClose tests that both the writer and the reader are closed.
+func TestClose(t *testing.T) {
+	writer := &closeRecorder{Store: gomap.NewStore(gomap.DefaultOptions)}
+	reader := &closeRecorder{Store: gomap.NewStore(gomap.DefaultOptions)}
+	store := rw.NewStore(writer, reader, rw.DefaultOptions)
+
+	err := store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+	if !writer.closed || !reader.closed {
+		t.Errorf("Expected both stores to be closed, but writer: %v, reader: %v", writer.closed, reader.closed)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	inner := gomap.NewStore(gomap.DefaultOptions)
+	store := rw.NewStore(inner, inner, rw.Options{ReadYourWritesWindow: time.Minute})
+
+	// Test empty key
+	err := store.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+type closeRecorder struct {
+	gokv.Store
+	closed bool
+}
+
+func (s *closeRecorder) Close() error {
+	s.closed = true
+	return s.Store.Close()
+}
+
+func expectValue(t *testing.T, store gokv.Store, k, expected string) {
+	t.Helper()
+
+	actual := test.Foo{}
+	found, err := store.Get(k, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if actual.Bar != expected {
+		t.Errorf("Expected %q, but was %q", expected, actual.Bar)
+	}
+}
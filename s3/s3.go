@@ -2,24 +2,32 @@ package s3
 
 import (
 	"bytes"
+	"context"
 	"errors"
-	"io/ioutil"
+	"io"
+	"strings"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/awserr"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/session"
-	awss3 "github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	awss3 "github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
 
 // Client is a gokv.Store implementation for S3.
 type Client struct {
-	c          *awss3.S3
+	c          *awss3.Client
 	bucketName string
 	codec      encoding.Codec
+	// Only set if configured in the options, so the defaults of S3 apply otherwise.
+	serverSideEncryption types.ServerSideEncryption
+	kmsKeyID             *string
+	storageClass         types.StorageClass
 }
 
 // Set stores the given value for the given key.
@@ -37,11 +45,14 @@ func (c Client) Set(k string, v any) error {
 	}
 
 	pubObjectInput := awss3.PutObjectInput{
-		Body:   bytes.NewReader(data),
-		Bucket: &c.bucketName,
-		Key:    &k,
+		Body:                 bytes.NewReader(data),
+		Bucket:               &c.bucketName,
+		Key:                  &k,
+		ServerSideEncryption: c.serverSideEncryption,
+		SSEKMSKeyId:          c.kmsKeyID,
+		StorageClass:         c.storageClass,
 	}
-	_, err = c.c.PutObject(&pubObjectInput)
+	_, err = c.c.PutObject(context.Background(), &pubObjectInput)
 	if err != nil {
 		return err
 	}
@@ -64,10 +75,10 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 		Bucket: &c.bucketName,
 		Key:    &k,
 	}
-	getObjectOutput, err := c.c.GetObject(&getObjectInput)
+	getObjectOutput, err := c.c.GetObject(context.Background(), &getObjectInput)
 	if err != nil {
-		aerr, ok := err.(awserr.Error)
-		if ok && aerr.Code() == awss3.ErrCodeNoSuchKey {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
 			return false, nil
 		}
 		return false, err
@@ -77,7 +88,8 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 		// TODO: Maybe return an error? Behaviour should be consistent across all implementations.
 		return false, nil
 	}
-	data, err := ioutil.ReadAll(getObjectOutput.Body)
+	defer getObjectOutput.Body.Close()
+	data, err := io.ReadAll(getObjectOutput.Body)
 	if err != nil {
 		return true, err
 	}
@@ -85,6 +97,36 @@ func (c Client) Get(k string, v any) (found bool, err error) {
 	return true, c.codec.Unmarshal(data, v)
 }
 
+// ModTime returns the time when the object of the given key was last modified.
+// It uses HeadObject, so the value isn't retrieved.
+// S3 only reports full seconds.
+// If no value is found it returns (time.Time{}, false, nil).
+// The key must not be "".
+func (c Client) ModTime(k string) (modTime time.Time, found bool, err error) {
+	if err := util.CheckKey(k); err != nil {
+		return time.Time{}, false, err
+	}
+
+	headObjectInput := awss3.HeadObjectInput{
+		Bucket: &c.bucketName,
+		Key:    &k,
+	}
+	headObjectOutput, err := c.c.HeadObject(context.Background(), &headObjectInput)
+	if err != nil {
+		// HeadObject responses don't have a body, so S3 can't report a NoSuchKey error
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return time.Time{}, false, nil
+		}
+		return time.Time{}, false, err
+	}
+	if headObjectOutput.LastModified == nil {
+		return time.Time{}, true, errors.New("S3 didn't report a modification time")
+	}
+
+	return *headObjectOutput.LastModified, true, nil
+}
+
 // Delete deletes the stored value for the given key.
 // Deleting a non-existing key-value pair does NOT lead to an error.
 // The key must not be "".
@@ -97,10 +139,15 @@ func (c Client) Delete(k string) error {
 		Bucket: &c.bucketName,
 		Key:    &k,
 	}
-	_, err := c.c.DeleteObject(&deleteObjectInput)
+	_, err := c.c.DeleteObject(context.Background(), &deleteObjectInput)
 	return err
 }
 
+// Kind returns gokv.KindS3.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindS3
+}
+
 // Close closes the client.
 // In the S3 implementation this doesn't have any effect.
 func (c Client) Close() error {
@@ -149,6 +196,19 @@ type Options struct {
 	// and some work only with the path style (especially self-hosted services like a Minio server running on localhost).
 	// Optional (false by default).
 	UsePathStyleAddressing bool
+	// Server-side encryption of the objects, either types.ServerSideEncryptionAes256 ("AES256") for SSE-S3
+	// or types.ServerSideEncryptionAwsKms ("aws:kms") for SSE-KMS.
+	// Reading encrypted objects doesn't require any options, S3 decrypts them transparently.
+	// Optional ("" by default, which leads to the default encryption of the bucket being used).
+	ServerSideEncryption types.ServerSideEncryption
+	// ID or ARN of the KMS key for SSE-KMS.
+	// Only allowed when ServerSideEncryption is "aws:kms".
+	// Optional ("" by default, which leads to the AWS managed key "aws/s3" being used).
+	KMSKeyID string
+	// Storage class of the objects, for example types.StorageClassStandardIa ("STANDARD_IA") for infrequently accessed data.
+	// Note that some storage classes like "GLACIER" don't allow immediate reads.
+	// Optional ("" by default, which leads to "STANDARD" being used).
+	StorageClass types.StorageClass
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
@@ -158,11 +218,12 @@ type Options struct {
 // Region: "" (use shared config file or environment variable),
 // AWSaccessKeyID: "" (use shared credentials file or environment variable),
 // AWSsecretAccessKey: "" (use shared credentials file or environment variable),
-// CustomEndpoint: "", UsePathStyleAddressing: false, Codec: encoding.JSON
+// CustomEndpoint: "", UsePathStyleAddressing: false,
+// ServerSideEncryption: "", KMSKeyID: "", StorageClass: "", Codec: encoding.JSON
 var DefaultOptions = Options{
 	Codec: encoding.JSON,
-	// No need to set Region, AWSaccessKeyID, AWSsecretAccessKey
-	// CustomEndpoint or UsePathStyleAddressing because their Go zero values are fine.
+	// No need to set Region, AWSaccessKeyID, AWSsecretAccessKey, CustomEndpoint, UsePathStyleAddressing,
+	// ServerSideEncryption, KMSKeyID or StorageClass because their Go zero values are fine.
 }
 
 // NewClient creates a new S3 client.
@@ -178,47 +239,41 @@ func NewClient(options Options) (Client, error) {
 	if options.BucketName == "" {
 		return result, errors.New("The BucketName in the options must not be empty")
 	}
+	if options.KMSKeyID != "" && options.ServerSideEncryption != types.ServerSideEncryptionAwsKms {
+		return result, errors.New("The KMSKeyID can only be used when ServerSideEncryption is \"aws:kms\"")
+	}
 
 	// Set default values
 	if options.Codec == nil {
 		options.Codec = DefaultOptions.Codec
 	}
 
+	// Use shared config file and environment variables...
+	var loadOptions []func(*config.LoadOptions) error
+	// ...but allow overwrite of region and credentials if they are set in the options.
+	if options.Region != "" {
+		loadOptions = append(loadOptions, config.WithRegion(options.Region))
+	}
 	// Set credentials only if set in the options.
 	// If not set, the SDK uses the shared credentials file or environment variables, which is the preferred way.
 	// Return an error if only one of the values is set.
-	var creds *credentials.Credentials
 	if (options.AWSaccessKeyID != "" && options.AWSsecretAccessKey == "") || (options.AWSaccessKeyID == "" && options.AWSsecretAccessKey != "") {
 		return result, errors.New("When passing credentials via options, you need to set BOTH AWSaccessKeyID AND AWSsecretAccessKey")
 	} else if options.AWSaccessKeyID != "" {
 		// Due to the previous check we can be sure that in this case AWSsecretAccessKey is not empty as well.
-		creds = credentials.NewStaticCredentials(options.AWSaccessKeyID, options.AWSsecretAccessKey, "")
-	}
-
-	config := aws.NewConfig()
-	if options.Region != "" {
-		config = config.WithRegion(options.Region)
-	}
-	if creds != nil {
-		config = config.WithCredentials(creds)
-	}
-	if options.CustomEndpoint != "" {
-		config = config.WithEndpoint(options.CustomEndpoint)
+		creds := credentials.NewStaticCredentialsProvider(options.AWSaccessKeyID, options.AWSsecretAccessKey, "")
+		loadOptions = append(loadOptions, config.WithCredentialsProvider(creds))
 	}
-	if options.UsePathStyleAddressing {
-		config = config.WithS3ForcePathStyle(true)
-	}
-	// Use shared config file...
-	sessionOpts := session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-	}
-	// ...but allow overwrite of region and credentials if they are set in the options.
-	sessionOpts.Config.MergeIn(config)
-	session, err := session.NewSessionWithOptions(sessionOpts)
+	cfg, err := config.LoadDefaultConfig(context.Background(), loadOptions...)
 	if err != nil {
 		return result, err
 	}
-	svc := awss3.New(session)
+	svc := awss3.NewFromConfig(cfg, func(o *awss3.Options) {
+		if options.CustomEndpoint != "" {
+			o.BaseEndpoint = aws.String(endpointURL(options.CustomEndpoint))
+		}
+		o.UsePathStyle = options.UsePathStyleAddressing
+	})
 
 	// Create the bucket if it doesn't exist yet.
 	createBucketInput := awss3.CreateBucketInput{
@@ -233,44 +288,58 @@ func NewClient(options Options) (Client, error) {
 	result.c = svc
 	result.bucketName = options.BucketName
 	result.codec = options.Codec
+	result.serverSideEncryption = options.ServerSideEncryption
+	if options.KMSKeyID != "" {
+		result.kmsKeyID = &options.KMSKeyID
+	}
+	result.storageClass = options.StorageClass
 
 	return result, nil
 }
 
+// endpointURL adds the "https://" scheme to the endpoint if it doesn't have a scheme yet,
+// because the SDK requires a URL while the options also allow a host.
+func endpointURL(endpoint string) string {
+	if strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	return "https://" + endpoint
+}
+
 // createBucket creates the bucket if it doesn't exist yet.
 //
 // When using actual Amazon S3, and trying to create a bucket you already own,
-// the API returns a proper ErrCodeBucketAlreadyOwnedByYou.
-// So we can just try to create it immediately and ignore the ErrCodeBucketAlreadyOwnedByYou error.
+// the API returns a proper BucketAlreadyOwnedByYou error.
+// So we can just try to create it immediately and ignore the BucketAlreadyOwnedByYou error.
 //
 // But Scaleway Object Storage for example just returns BucketAlreadyExists,
 // which could also mean that someone else owns it, which would be an error.
 // So in this case we must do it differently.
-func createBucket(origS3 bool, svc *awss3.S3, createBucketInput awss3.CreateBucketInput, bucketName string) error {
+func createBucket(origS3 bool, svc *awss3.Client, createBucketInput awss3.CreateBucketInput, bucketName string) error {
 	if origS3 {
-		_, err := svc.CreateBucket(&createBucketInput)
+		_, err := svc.CreateBucket(context.Background(), &createBucketInput)
 		if err != nil {
-			aerr, ok := err.(awserr.Error)
-			if !ok || aerr.Code() != awss3.ErrCodeBucketAlreadyOwnedByYou {
+			var alreadyOwned *types.BucketAlreadyOwnedByYou
+			if !errors.As(err, &alreadyOwned) {
 				return err
 			}
 		}
 	} else {
-		listBucketsOutput, err := svc.ListBuckets(&awss3.ListBucketsInput{})
+		listBucketsOutput, err := svc.ListBuckets(context.Background(), &awss3.ListBucketsInput{})
 		if err != nil {
 			return err
 		}
 		ownsBucket := false
 		if listBucketsOutput.Buckets != nil {
 			for _, bucket := range listBucketsOutput.Buckets {
-				if *bucket.Name == bucketName {
+				if aws.ToString(bucket.Name) == bucketName {
 					ownsBucket = true
 					break
 				}
 			}
 		}
 		if !ownsBucket {
-			_, err = svc.CreateBucket(&createBucketInput)
+			_, err = svc.CreateBucket(context.Background(), &createBucketInput)
 			if err != nil {
 				return err
 			}
@@ -1,7 +1,11 @@
 package sql
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
@@ -13,7 +17,14 @@ type Client struct {
 	UpsertStmt *sql.Stmt
 	GetStmt    *sql.Stmt
 	DeleteStmt *sql.Stmt
-	Codec      encoding.Codec
+	// Deletes all rows whose updated_at timestamp is older than the passed number of microseconds.
+	// Only set when the table has timestamp columns.
+	DeleteOlderThanStmt *sql.Stmt
+	// Selects all keys that match the passed LIKE pattern, with "!" as escape character.
+	KeysStmt *sql.Stmt
+	Codec    encoding.Codec
+	// True if the DB was passed by the package user, so it's not closed by the client.
+	SharedDB bool
 }
 
 // Set stores the given value for the given key.
@@ -75,10 +86,84 @@ func (c Client) Delete(k string) error {
 	return err
 }
 
+// DeleteOlderThan deletes all key-value pairs that haven't been updated for at least the given duration
+// and returns the number of deleted key-value pairs.
+// The age is determined by the database server's clock.
+// It returns an error if the table doesn't have timestamp columns.
+func (c Client) DeleteOlderThan(d time.Duration) (deleted int, err error) {
+	if c.DeleteOlderThanStmt == nil {
+		return 0, errors.New("The table doesn't have timestamp columns, so DeleteOlderThan() can't be used")
+	}
+
+	res, err := c.DeleteOlderThanStmt.Exec(d.Microseconds())
+	if err != nil {
+		return 0, err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(affected), nil
+}
+
+// ForEach calls fn for each key that starts with the given prefix.
+// The rows are read while fn is called, so one connection is in use until the iteration is done.
+// If fn returns an error, the iteration stops and the error is returned.
+func (c Client) ForEach(prefix string, fn func(k string) error) error {
+	if c.KeysStmt == nil {
+		return errors.New("The client has no statement for selecting keys, so ForEach() can't be used")
+	}
+
+	rows, err := c.KeysStmt.Query(likeEscaper.Replace(prefix) + "%")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var k string
+		if err = rows.Scan(&k); err != nil {
+			return err
+		}
+		// With a case-insensitive collation (the default in MySQL) LIKE also matches keys with a differently cased prefix
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+		if err = fn(k); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// likeEscaper escapes the characters that have a special meaning in LIKE patterns,
+// with "!" as escape character, because a backslash would need to be escaped differently in MySQL and PostgreSQL string literals.
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// Ping checks if the database server is reachable.
+// It establishes a connection if necessary.
+func (c Client) Ping(ctx context.Context) error {
+	return c.C.PingContext(ctx)
+}
+
 // Close closes the client.
 // It must be called to return all open connections to the connection pool and to release any open resources.
+// If the DB was passed via the options, only the prepared statements are closed,
+// because the DB is owned by the package user.
 func (c Client) Close() error {
-	return c.C.Close()
+	if !c.SharedDB {
+		return c.C.Close()
+	}
+	// Closing the DB would close the prepared statements as well
+	var result error
+	for _, stmt := range []*sql.Stmt{c.UpsertStmt, c.GetStmt, c.DeleteStmt, c.DeleteOlderThanStmt, c.KeysStmt} {
+		if stmt == nil {
+			continue
+		}
+		if err := stmt.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
 }
 
 // CreateDB creates a database with the given name.
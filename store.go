@@ -1,5 +1,11 @@
 package gokv
 
+import "errors"
+
+// ErrReadOnly is returned by Set and Delete of stores that are read-only,
+// like the readonly store wrapper, a bbolt store in read-only mode or a leveldb snapshot.
+var ErrReadOnly = errors.New("The store is read-only")
+
 // Store is an abstraction for different key-value store implementations.
 // A store must be able to store, retrieve and delete key-value pairs,
 // with the key being a string and the value being any Go interface{}.
@@ -17,6 +23,8 @@ type Store interface {
 	// In case of a struct the Get method will populate the fields of the object
 	// that the passed pointer points to with the values of the retrieved object's values.
 	// If no value is found it returns (false, nil).
+	// Empty values (for example an empty string, or a value that the codec marshals to zero bytes)
+	// must be found as well, so implementations must not use an empty value as marker for a missing one.
 	// The key must not be "" and the pointer must not be nil.
 	Get(k string, v any) (found bool, err error)
 	// Delete deletes the stored value for the given key.
@@ -0,0 +1,28 @@
+package gokv
+
+import "errors"
+
+// ErrSyncUnsupported is returned by Sync when the store doesn't implement the Syncer interface.
+var ErrSyncUnsupported = errors.New("The store doesn't support syncing")
+
+// Syncer is an optional interface for stores that write to local disk
+// and can buffer writes, for example when they're configured for asynchronous writes.
+// Sync lets an application decide when the writes must be durable, for example before a checkpoint.
+// Check for it with a type assertion or use the Sync function.
+//
+// It's implemented by the badgerdb, bbolt, file and leveldb implementations.
+type Syncer interface {
+	// Sync flushes all writes that were done so far to disk,
+	// so that they survive a crash of the process or the operating system.
+	Sync() error
+}
+
+// Sync flushes all writes that were done so far to disk via the store if it implements the Syncer interface.
+// Otherwise ErrSyncUnsupported is returned.
+func Sync(store Store) error {
+	syncer, ok := store.(Syncer)
+	if !ok {
+		return ErrSyncUnsupported
+	}
+	return syncer.Sync()
+}
@@ -1,8 +1,13 @@
 package syncmap
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
 	"sync"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -30,6 +35,24 @@ func (s Store) Set(k string, v any) error {
 	return nil
 }
 
+// SetRaw stores the given bytes for the given key, without marshalling them.
+// The bytes must have been marshalled with the store's codec.
+// They're copied, so the caller can modify the passed slice afterwards.
+// The key must not be "" and the data must not be nil.
+func (s Store) SetRaw(k string, data []byte) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+	// util.CheckVal doesn't detect a nil slice, because it's wrapped in a non-nil interface
+	if data == nil {
+		return errors.New("The passed value is nil, which is not allowed")
+	}
+
+	// Copy the data so that the caller can't modify the stored value afterwards
+	s.m.Store(k, bytes.Clone(data))
+	return nil
+}
+
 // Get retrieves the stored value for the given key.
 // You need to pass a pointer to the value, so in case of a struct
 // the automatic unmarshalling can populate the fields of the object
@@ -41,15 +64,42 @@ func (s Store) Get(k string, v any) (found bool, err error) {
 		return false, err
 	}
 
-	dataInterface, found := s.m.Load(k)
+	data, found := s.get(k)
 	if !found {
 		return false, nil
 	}
+
+	return true, s.codec.Unmarshal(data, v)
+}
+
+// GetRaw retrieves the stored bytes for the given key, without unmarshalling them.
+// The returned bytes are a copy, so the caller can modify them.
+// If no value is found it returns (nil, false, nil).
+// The key must not be "".
+func (s Store) GetRaw(k string) (data []byte, found bool, err error) {
+	if err := util.CheckKey(k); err != nil {
+		return nil, false, err
+	}
+
+	data, found = s.get(k)
+	// Copy the data so that the caller can't modify the stored value
+	return bytes.Clone(data), found, nil
+}
+
+// get returns the stored bytes without copying them, so they must not be modified.
+func (s Store) get(k string) (data []byte, found bool) {
+	dataInterface, found := s.m.Load(k)
+	if !found {
+		return nil, false
+	}
 	// No need to check "ok" return value in type assertion,
 	// because we control the map and we only put slices of bytes in the map.
-	data := dataInterface.([]byte)
+	return dataInterface.([]byte), true
+}
 
-	return true, s.codec.Unmarshal(data, v)
+// Unmarshal unmarshals the bytes that were returned by GetRaw with the store's codec.
+func (s Store) Unmarshal(data []byte, v any) error {
+	return s.codec.Unmarshal(data, v)
 }
 
 // Delete deletes the stored value for the given key.
@@ -64,6 +114,55 @@ func (s Store) Delete(k string) error {
 	return nil
 }
 
+// ForEach calls fn for each key that starts with the given prefix.
+// If fn returns an error, the iteration stops and the error is returned.
+func (s Store) ForEach(prefix string, fn func(k string) error) error {
+	var err error
+	s.m.Range(func(key, _ any) bool {
+		k := key.(string)
+		if !strings.HasPrefix(k, prefix) {
+			return true
+		}
+		err = fn(k)
+		return err == nil
+	})
+	return err
+}
+
+// Len returns the number of stored key-value pairs.
+// sync.Map doesn't keep a count, so the key-value pairs are counted,
+// and key-value pairs that are stored or deleted concurrently might or might not be included.
+// It's not part of the gokv.Store interface, but specific to this store.
+func (s Store) Len() int {
+	n := 0
+	s.m.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	return n
+}
+
+// Clear deletes all stored key-value pairs, for example between tests.
+// Key-value pairs that are stored concurrently might or might not be deleted.
+// It's not part of the gokv.Store interface, but specific to this store.
+func (s Store) Clear() error {
+	s.m.Range(func(key, _ any) bool {
+		s.m.Delete(key)
+		return true
+	})
+	return nil
+}
+
+// Ping always returns nil, because the map is in memory.
+func (s Store) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Kind returns gokv.KindSyncMap.
+func (s Store) Kind() gokv.Kind {
+	return gokv.KindSyncMap
+}
+
 // Close closes the store.
 // When called, the store's pointer to the internal Go map is set to nil,
 // leading to the map being free for garbage collection.
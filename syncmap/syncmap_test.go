@@ -38,6 +38,21 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, encoding.JSON)
+		test.TestEmptyValues(store, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		store := createStore(t, test.BytesCodec{})
+		test.TestEmptyValues(store, t)
+	})
+}
+
 // TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
 // The store is a sync.Map, so the concurrency should be supported by the used package.
 func TestStoreConcurrent(t *testing.T) {
@@ -48,6 +63,64 @@ func TestStoreConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, store)
 }
 
+// TestRawStore tests if the gokv.RawStore methods work properly.
+func TestRawStore(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		store := createStore(t, encoding.JSON)
+		test.TestRawStore(store, t)
+	})
+
+	// Test with gob
+	t.Run("gob", func(t *testing.T) {
+		store := createStore(t, encoding.Gob)
+		test.TestRawStore(store, t)
+	})
+}
+
+// TestIterator tests if listing the keys works properly.
+func TestIterator(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	test.TestIterator(store, t)
+}
+
+// TestLenAndClear tests if the number of key-value pairs is reported and if clearing the store works.
+func TestLenAndClear(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	if store.Len() != 0 {
+		t.Errorf("Expected 0 key-value pairs, but was %v", store.Len())
+	}
+	for _, k := range []string{"1", "2", "3"} {
+		err := store.Set(k, k)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Overwriting doesn't change the length
+	err := store.Set("1", "one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 3 {
+		t.Errorf("Expected 3 key-value pairs, but was %v", store.Len())
+	}
+
+	err = store.Clear()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if store.Len() != 0 {
+		t.Errorf("Expected 0 key-value pairs after clearing, but was %v", store.Len())
+	}
+	found, err := store.Get("1", new(string))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -120,6 +193,12 @@ func TestNil(t *testing.T) {
 	t.Run("get with nil / nil value parameter", createTest(encoding.Gob))
 }
 
+// TestPing tests if pinging works properly.
+func TestPing(t *testing.T) {
+	store := createStore(t, encoding.JSON)
+	test.TestPinger(store, t)
+}
+
 // TestClose tests if the close method returns any errors.
 func TestClose(t *testing.T) {
 	store := createStore(t, encoding.JSON)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Azure/azure-sdk-for-go/storage"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -127,6 +128,11 @@ func (c Client) Delete(k string) error {
 	return err
 }
 
+// Kind returns gokv.KindTableStorage.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindTableStorage
+}
+
 // Close closes the client.
 // In the Table Storage implementation this doesn't have any effect.
 func (c Client) Close() error {
@@ -7,6 +7,7 @@ import (
 
 	"github.com/aliyun/aliyun-tablestore-go-sdk/tablestore"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -130,6 +131,11 @@ func (c Client) Delete(k string) error {
 	return err
 }
 
+// Kind returns gokv.KindTableStore.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindTableStore
+}
+
 // Close closes the client.
 // In the Table Store implementation this doesn't have any effect.
 func (c Client) Close() error {
@@ -6,3 +6,5 @@ require (
 	github.com/go-test/deep v1.1.0
 	github.com/philippgille/gokv v0.7.0
 )
+
+replace github.com/philippgille/gokv => ../
@@ -1,10 +1,16 @@
 package test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"math/rand"
+	"sort"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-test/deep"
 
@@ -22,6 +28,35 @@ type privateFoo struct {
 	privateBar string
 }
 
+// BytesCodec is a codec that stores strings and slices of bytes as they are,
+// so that empty values are passed to the store as zero bytes.
+// Use it with TestEmptyValues to check that the store doesn't mistake an empty value for a missing one.
+type BytesCodec struct{}
+
+// Marshal returns the bytes of a string or slice of bytes.
+func (c BytesCodec) Marshal(v any) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return append([]byte{}, v...), nil
+	case []byte:
+		return append([]byte{}, v...), nil
+	}
+	return nil, fmt.Errorf("Only strings and slices of bytes are supported, but the value is of type %T", v)
+}
+
+// Unmarshal sets the value that v points to, which must be a *string or *[]byte.
+func (c BytesCodec) Unmarshal(data []byte, v any) error {
+	switch v := v.(type) {
+	case *string:
+		*v = string(data)
+	case *[]byte:
+		*v = append([]byte{}, data...)
+	default:
+		return fmt.Errorf("Only pointers to strings and slices of bytes are supported, but the value is of type %T", v)
+	}
+	return nil
+}
+
 // TestStore tests if reading from, writing to and deleting from the store works properly.
 // A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
 func TestStore(store gokv.Store, t *testing.T) {
@@ -296,6 +331,58 @@ func TestTypes(store gokv.Store, t *testing.T) {
 	}
 }
 
+// TestEmptyValues tests if empty values are found after they're stored,
+// which requires the store to distinguish them from missing values.
+// The tested values work with the JSON and gob codecs as well as with BytesCodec.
+func TestEmptyValues(store gokv.Store, t *testing.T) {
+	// Empty string
+	err := store.Set("empty-string", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	actualString := "not empty"
+	found, err := store.Get("empty-string", &actualString)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("The stored empty string wasn't found")
+	} else if actualString != "" {
+		t.Errorf("Expected an empty string, but was %q", actualString)
+	}
+
+	// Empty slice of bytes
+	err = store.Set("empty-bytes", []byte{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actualBytes := []byte("not empty")
+	found, err = store.Get("empty-bytes", &actualBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("The stored empty slice of bytes wasn't found")
+	} else if len(actualBytes) != 0 {
+		t.Errorf("Expected an empty slice of bytes, but was %q", actualBytes)
+	}
+
+	// Deleted empty values must not be found anymore
+	for _, k := range []string{"empty-string", "empty-bytes"} {
+		err = store.Delete(k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		found, err = store.Get(k, new(string))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if found {
+			t.Errorf("The deleted empty value for key %q was found", k)
+		}
+	}
+}
+
 func handleGetError(t *testing.T, err error, found bool) {
 	if err != nil {
 		t.Error(err)
@@ -369,3 +456,1066 @@ func InteractWithStore(store gokv.Store, key string, t *testing.T, waitGroup *sy
 		t.Error(err)
 	}
 }
+
+// TestLocker tests if two clients can't hold the same lock simultaneously
+// and that a lock automatically expires after its TTL.
+// The lockers must be two different clients for the same store.
+// Because some implementations check expiry lazily or with some delay,
+// the test waits up to three times the TTL for the lock to expire.
+func TestLocker(t *testing.T, lockerA, lockerB gokv.Locker, key string, ttl time.Duration) {
+	// A lock without expiry would be held forever by a crashed process
+	for _, invalidTTL := range []time.Duration{0, -time.Second} {
+		_, err := lockerA.AcquireLock(key, invalidTTL)
+		if err == nil {
+			t.Fatalf("An error should have occurred for the TTL %v, but didn't", invalidTTL)
+		}
+	}
+
+	lockA, err := lockerA.AcquireLock(key, ttl)
+	if err != nil {
+		t.Fatalf("Acquiring a free lock failed: %v", err)
+	}
+	_, err = lockerB.AcquireLock(key, ttl)
+	if !errors.Is(err, gokv.ErrLockHeld) {
+		t.Fatalf("Expected gokv.ErrLockHeld, but was: %v", err)
+	}
+	err = lockA.Refresh(ttl)
+	if err != nil {
+		t.Errorf("Refreshing the lock failed: %v", err)
+	}
+	err = lockA.Release()
+	if err != nil {
+		t.Errorf("Releasing the lock failed: %v", err)
+	}
+
+	// Now B can acquire it, but not let it expire
+	lockB, err := lockerB.AcquireLock(key, ttl)
+	if err != nil {
+		t.Fatalf("Acquiring a released lock failed: %v", err)
+	}
+	acquired := time.Now()
+	_, err = lockerA.AcquireLock(key, ttl)
+	if !errors.Is(err, gokv.ErrLockHeld) {
+		t.Fatalf("Expected gokv.ErrLockHeld, but was: %v", err)
+	}
+	deadline := acquired.Add(3 * ttl)
+	for {
+		lockA, err = lockerA.AcquireLock(key, ttl)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, gokv.ErrLockHeld) {
+			t.Fatalf("Expected gokv.ErrLockHeld, but was: %v", err)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("The lock didn't expire after its TTL")
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if elapsed := time.Since(acquired); elapsed < ttl/2 {
+		t.Errorf("The lock expired too early, after %v instead of %v", elapsed, ttl)
+	}
+
+	err = lockB.Release()
+	if !errors.Is(err, gokv.ErrLockLost) {
+		t.Errorf("Expected gokv.ErrLockLost, but was: %v", err)
+	}
+	err = lockB.Refresh(ttl)
+	if !errors.Is(err, gokv.ErrLockLost) {
+		t.Errorf("Expected gokv.ErrLockLost, but was: %v", err)
+	}
+	err = lockA.Release()
+	if err != nil {
+		t.Errorf("Releasing the lock failed: %v", err)
+	}
+}
+
+// TestWithLock tests if gokv.WithLock runs the function under the lock,
+// keeps the lock beyond its TTL while the function runs and releases it afterwards,
+// also when the function returns an error or panics.
+func TestWithLock(t *testing.T, lockerA, lockerB gokv.Locker, key string, ttl time.Duration) {
+	ran := false
+	err := gokv.WithLock(lockerA, key, ttl, func(ctx context.Context) error {
+		ran = true
+		_, err := lockerB.AcquireLock(key, ttl)
+		if !errors.Is(err, gokv.ErrLockHeld) {
+			t.Errorf("Expected gokv.ErrLockHeld, but was: %v", err)
+		}
+		err = gokv.WithLock(lockerB, key, ttl, func(ctx context.Context) error {
+			t.Error("The function was run, even though the lock is held")
+			return nil
+		})
+		if !errors.Is(err, gokv.ErrLockHeld) {
+			t.Errorf("Expected gokv.ErrLockHeld, but was: %v", err)
+		}
+
+		// The lock must be refreshed while the function runs
+		time.Sleep(2 * ttl)
+		_, err = lockerB.AcquireLock(key, ttl)
+		if !errors.Is(err, gokv.ErrLockHeld) {
+			t.Errorf("Expected the lock to still be held after twice its TTL, but the error was: %v", err)
+		}
+		if ctx.Err() != nil {
+			t.Errorf("Expected the context not to be canceled while the lock is held, but it was: %v", context.Cause(ctx))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithLock failed: %v", err)
+	}
+	if !ran {
+		t.Fatal("The function wasn't run")
+	}
+	expectReleased := func() {
+		t.Helper()
+		lock, err := lockerB.AcquireLock(key, ttl)
+		if err != nil {
+			t.Fatalf("Expected the lock to be released, but acquiring it failed: %v", err)
+		}
+		err = lock.Release()
+		if err != nil {
+			t.Fatalf("Releasing the lock failed: %v", err)
+		}
+	}
+	expectReleased()
+
+	// Error of the function
+	fnErr := errors.New("foo")
+	err = gokv.WithLock(lockerA, key, ttl, func(ctx context.Context) error {
+		return fnErr
+	})
+	if !errors.Is(err, fnErr) {
+		t.Errorf("Expected the error of the function, but was: %v", err)
+	}
+	expectReleased()
+
+	// Panic in the function
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("Expected the panic to be propagated")
+			}
+		}()
+		_ = gokv.WithLock(lockerA, key, ttl, func(ctx context.Context) error {
+			panic("foo")
+		})
+	}()
+	expectReleased()
+}
+
+// TestRawStore tests if the raw methods of the store work properly,
+// and if they're compatible with the regular methods.
+func TestRawStore(store gokv.RawStore, t *testing.T) {
+	// Set regular, get raw
+	err := store.Set("foo", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, found, err := store.GetRaw("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	actual := Foo{}
+	err = store.Unmarshal(data, &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	// Set raw, get regular
+	err = store.SetRaw("bar", data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual = Foo{}
+	found, err = store.Get("bar", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	// Modifying the returned bytes of "foo", which were also passed for "bar", must not change the stored values
+	for i := range data {
+		data[i] = 0
+	}
+	for _, k := range []string{"foo", "bar"} {
+		actual = Foo{}
+		found, err = store.Get(k, &actual)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !found {
+			t.Fatal("No value was found, but should have been")
+		}
+		if actual.Bar != "baz" {
+			t.Errorf("Expected %q after modifying the raw bytes, but was %q", "baz", actual.Bar)
+		}
+	}
+
+	// Missing value
+	err = store.Delete("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, found, err = store.GetRaw("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+
+	// Errors
+	_, _, err = store.GetRaw("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.SetRaw("", data)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.SetRaw("foo", nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestConditionalSetter tests if the gokv.ConditionalSetter implementation of the store works properly.
+func TestConditionalSetter(store gokv.Store, t *testing.T) {
+	setter, ok := store.(gokv.ConditionalSetter)
+	if !ok {
+		t.Fatal("The store doesn't implement gokv.ConditionalSetter")
+	}
+
+	err := store.Delete("setifabsent")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Missing key
+	set, err := setter.SetIfAbsent("setifabsent", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !set {
+		t.Error("The value wasn't stored, but the key didn't exist")
+	}
+
+	// Existing key
+	set, err = setter.SetIfAbsent("setifabsent", Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if set {
+		t.Error("The value was stored, but the key already existed")
+	}
+	actual := Foo{}
+	found, err := store.Get("setifabsent", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	// Only one of several concurrent callers stores the value
+	err = store.Delete("setifabsent")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goroutineCount := 20
+	var setCount int64
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		go func(i int) {
+			defer waitGroup.Done()
+			set, err := setter.SetIfAbsent("setifabsent", Foo{Bar: strconv.Itoa(i)})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if set {
+				atomic.AddInt64(&setCount, 1)
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+	if setCount != 1 {
+		t.Errorf("Expected exactly one caller to store the value, but %v did", setCount)
+	}
+
+	// Errors
+	_, err = setter.SetIfAbsent("", Foo{Bar: "baz"})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = setter.SetIfAbsent("setifabsent", nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestConditionalDeleter tests if the gokv.ConditionalDeleter implementation of the store works properly.
+func TestConditionalDeleter(store gokv.Store, t *testing.T) {
+	deleter, ok := store.(gokv.ConditionalDeleter)
+	if !ok {
+		t.Fatal("The store doesn't implement gokv.ConditionalDeleter")
+	}
+
+	err := store.Set("foo", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Different value
+	deleted, err := deleter.DeleteIf("foo", Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted {
+		t.Error("The key-value pair was deleted, but the value didn't match")
+	}
+	found, err := store.Get("foo", new(Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+
+	// Matching value
+	deleted, err = deleter.DeleteIf("foo", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !deleted {
+		t.Error("The key-value pair wasn't deleted, but the value matched")
+	}
+	found, err = store.Get("foo", new(Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+
+	// Missing key
+	deleted, err = deleter.DeleteIf("foo", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deleted {
+		t.Error("A non-existing key-value pair was reported as deleted")
+	}
+
+	// Errors
+	_, err = deleter.DeleteIf("", Foo{Bar: "baz"})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = deleter.DeleteIf("foo", nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestBatchStore tests if the gokv.BatchStore methods of the store work properly,
+// and if they're compatible with the regular methods.
+func TestBatchStore(store gokv.BatchStore, t *testing.T) {
+	// Set many, get regular
+	err := store.SetMany(map[string]any{
+		"batch1": Foo{Bar: "baz1"},
+		"batch2": Foo{Bar: "baz2"},
+		"batch3": Foo{Bar: "baz3"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := Foo{}
+	found, err := store.Get("batch2", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if actual.Bar != "baz2" {
+		t.Errorf("Expected %q, but was %q", "baz2", actual.Bar)
+	}
+
+	// Set regular, get many, including a missing key
+	err = store.Set("batch4", Foo{Bar: "baz4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []string{"batch1", "missing", "batch4", "batch3"}
+	values := []*Foo{{}, {}, {}, {}}
+	foundMany, err := store.GetMany(keys, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectedFound := []bool{true, false, true, true}
+	if diff := deep.Equal(foundMany, expectedFound); diff != nil {
+		t.Error(diff)
+	}
+	expectedValues := []*Foo{{Bar: "baz1"}, {}, {Bar: "baz4"}, {Bar: "baz3"}}
+	if diff := deep.Equal(values, expectedValues); diff != nil {
+		t.Error(diff)
+	}
+
+	// A []any works as well
+	anyValues := []any{new(Foo), new(Foo)}
+	foundMany, err = store.GetMany([]string{"batch1", "batch2"}, anyValues)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !foundMany[0] || !foundMany[1] || anyValues[1].(*Foo).Bar != "baz2" {
+		t.Errorf("Expected both values to be found, but found was %v and the values %v and %v", foundMany, anyValues[0], anyValues[1])
+	}
+
+	// Delete many
+	err = store.DeleteMany([]string{"batch1", "batch2", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	values = []*Foo{{}, {}, {}}
+	foundMany, err = store.GetMany([]string{"batch1", "batch2", "batch3"}, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(foundMany, []bool{false, false, true}); diff != nil {
+		t.Error(diff)
+	}
+	err = store.DeleteMany([]string{"batch3", "batch4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Empty batches
+	err = store.SetMany(map[string]any{})
+	if err != nil {
+		t.Error(err)
+	}
+	foundMany, err = store.GetMany(nil, []*Foo{})
+	if err != nil {
+		t.Error(err)
+	}
+	if len(foundMany) != 0 {
+		t.Errorf("Expected no results, but got %v", foundMany)
+	}
+	err = store.DeleteMany(nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	// Errors
+	err = store.SetMany(map[string]any{"": Foo{Bar: "baz"}})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.SetMany(map[string]any{"foo": nil})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.GetMany([]string{""}, []*Foo{{}})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.GetMany([]string{"foo", "bar"}, []*Foo{{}})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.GetMany([]string{"foo"}, []*Foo{nil})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.GetMany([]string{"foo"}, Foo{})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.DeleteMany([]string{""})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestIterator tests if the gokv.Iterator implementation of the store works properly.
+func TestIterator(store gokv.Store, t *testing.T) {
+	iterator, ok := store.(gokv.Iterator)
+	if !ok {
+		t.Fatal("The store doesn't implement gokv.Iterator")
+	}
+
+	// Some keys contain characters that have a special meaning in LIKE clauses and glob patterns
+	keys := []string{"user:1", "user:2", "users", "a%b", "axb", "a_c", "a*d", "a?e"}
+	for _, k := range keys {
+		err := store.Set(k, Foo{Bar: k})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func() {
+		for _, k := range keys {
+			_ = store.Delete(k)
+		}
+	}()
+
+	expectKeys := func(prefix string, expected []string) {
+		t.Helper()
+		actual, err := gokv.Keys(store, prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sort.Strings(actual)
+		sort.Strings(expected)
+		if diff := deep.Equal(actual, expected); diff != nil {
+			t.Errorf("Unexpected keys for prefix %q: %v", prefix, diff)
+		}
+	}
+	// Other tests might have left keys in the store, so only check that all keys are contained
+	allKeys, err := gokv.Keys(store, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range keys {
+		if !contains(allKeys, k) {
+			t.Errorf("Expected key %q to be listed, but it wasn't", k)
+		}
+	}
+	expectKeys("user:", []string{"user:1", "user:2"})
+	expectKeys("user", []string{"user:1", "user:2", "users"})
+	expectKeys("a%", []string{"a%b"})
+	expectKeys("a_", []string{"a_c"})
+	expectKeys("a*", []string{"a*d"})
+	expectKeys("a?", []string{"a?e"})
+	expectKeys("missing", nil)
+
+	// An error stops the iteration
+	fnErr := errors.New("stop")
+	calls := 0
+	err = iterator.ForEach("user", func(k string) error {
+		calls++
+		return fnErr
+	})
+	if err != fnErr {
+		t.Errorf("Expected the error of fn, but was %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("Expected fn to be called once, but it was called %v times", calls)
+	}
+
+	// The store can be used while iterating
+	err = iterator.ForEach("user:", func(k string) error {
+		return store.Set("copy-"+k, Foo{Bar: k})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys = append(keys, "copy-user:1", "copy-user:2")
+	found, err := store.Get("copy-user:1", new(Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+}
+
+func contains(keys []string, k string) bool {
+	for _, key := range keys {
+		if key == k {
+			return true
+		}
+	}
+	return false
+}
+
+// TestTTLStore tests if the gokv.TTLStore implementation of the store works properly.
+// It waits for the key-value pairs to expire, so it takes a few seconds.
+func TestTTLStore(store gokv.Store, t *testing.T) {
+	ttlStore, ok := store.(gokv.TTLStore)
+	if !ok {
+		t.Fatal("The store doesn't implement gokv.TTLStore")
+	}
+
+	// Some implementations have a precision of one second
+	ttl := 2 * time.Second
+	err := ttlStore.SetWithTTL("ttl", Foo{Bar: "baz"}, ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A Set without TTL removes the expiry
+	err = ttlStore.SetWithTTL("ttl-overwritten", Foo{Bar: "baz"}, ttl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("ttl-overwritten", Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := Foo{}
+	found, err := store.Get("ttl", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	time.Sleep(ttl + 1500*time.Millisecond)
+
+	found, err = store.Get("ttl", new(Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found after the TTL, but no value was expected")
+	}
+	found, err = store.Get("ttl-overwritten", new(Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been, because the expiry was removed")
+	}
+	err = store.Delete("ttl-overwritten")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Errors
+	err = ttlStore.SetWithTTL("", Foo{Bar: "baz"}, ttl)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = ttlStore.SetWithTTL("foo", nil, ttl)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = ttlStore.SetWithTTL("foo", Foo{Bar: "baz"}, 0)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestModTimer tests if the gokv.ModTimer implementation of the store works properly.
+// The store's backend can be remote, so the modification time is only expected to be roughly the current time.
+func TestModTimer(store gokv.Store, t *testing.T) {
+	err := store.Delete("modtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	modTime, found, err := gokv.ModTime(store, "modtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found || !modTime.IsZero() {
+		t.Errorf("Expected no modification time for a missing key, but found was %v and the time %v", found, modTime)
+	}
+
+	start := time.Now()
+	err = store.Set("modtime", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	modTime, found, err = gokv.ModTime(store, "modtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No modification time was found, but should have been")
+	}
+	// Allow for clock differences between the client and a remote backend and for the precision of the backend
+	if modTime.Before(start.Add(-time.Minute)) || modTime.After(time.Now().Add(time.Minute)) {
+		t.Errorf("Expected a modification time around %v, but was %v", start, modTime)
+	}
+
+	err = store.Delete("modtime")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestSyncer tests if the gokv.Syncer implementation of the store works properly.
+// Whether the writes survive a crash can't be tested here,
+// so it only checks that syncing after writes and deletes succeeds and doesn't change the data.
+func TestSyncer(store gokv.Store, t *testing.T) {
+	err := store.Set("sync1", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("sync2", Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Delete("sync2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = gokv.Sync(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := Foo{}
+	found, err := store.Get("sync1", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found || actual.Bar != "baz" {
+		t.Errorf("Expected %v to be found, but found was %v and the value %v", Foo{Bar: "baz"}, found, actual)
+	}
+	found, err = store.Get("sync2", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+
+	// Syncing without pending writes works as well
+	err = gokv.Sync(store)
+	if err != nil {
+		t.Error(err)
+	}
+
+	err = store.Delete("sync1")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPinger tests if the gokv.Pinger implementation of the store works properly.
+// The store's backend must be reachable.
+func TestPinger(store gokv.Store, t *testing.T) {
+	err := gokv.Ping(context.Background(), store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The store still works afterwards
+	err = store.Set("ping", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err := store.Get("ping", new(Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+	err = store.Delete("ping")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestCASStore tests if the gokv.CASStore implementation of the store works properly.
+func TestCASStore(store gokv.Store, t *testing.T) {
+	casStore, ok := store.(gokv.CASStore)
+	if !ok {
+		t.Fatal("The store doesn't implement gokv.CASStore")
+	}
+
+	// Missing key
+	err := store.Delete("cas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	swapped, err := casStore.CompareAndSwap("cas", Foo{Bar: "baz"}, Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapped {
+		t.Error("A non-existing key-value pair was reported as swapped")
+	}
+	found, err := store.Get("cas", new(Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+
+	err = store.Set("cas", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Different value
+	swapped, err = casStore.CompareAndSwap("cas", Foo{Bar: "qux"}, Foo{Bar: "quux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if swapped {
+		t.Error("The value was swapped, but the old value didn't match")
+	}
+	actual := Foo{}
+	_, err = store.Get("cas", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	// Matching value
+	swapped, err = casStore.CompareAndSwap("cas", Foo{Bar: "baz"}, Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !swapped {
+		t.Error("The value wasn't swapped, but the old value matched")
+	}
+	_, err = store.Get("cas", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual.Bar != "qux" {
+		t.Errorf("Expected %q, but was %q", "qux", actual.Bar)
+	}
+
+	// Concurrent swaps of the same value: only one may succeed
+	goroutineCount := 10
+	successes := make(chan bool, goroutineCount)
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		go func(i int) {
+			defer waitGroup.Done()
+			swapped, err := casStore.CompareAndSwap("cas", Foo{Bar: "qux"}, Foo{Bar: strconv.Itoa(i)})
+			if err != nil {
+				t.Error(err)
+			}
+			successes <- swapped
+		}(i)
+	}
+	waitGroup.Wait()
+	close(successes)
+	successCount := 0
+	for swapped := range successes {
+		if swapped {
+			successCount++
+		}
+	}
+	if successCount != 1 {
+		t.Errorf("Expected exactly one concurrent swap to succeed, but %v did", successCount)
+	}
+
+	err = store.Delete("cas")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Errors
+	_, err = casStore.CompareAndSwap("", Foo{Bar: "baz"}, Foo{Bar: "qux"})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = casStore.CompareAndSwap("cas", nil, Foo{Bar: "qux"})
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = casStore.CompareAndSwap("cas", Foo{Bar: "baz"}, nil)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestUpdateFunc tests if gokv.UpdateFunc works properly with the gokv.CASStore implementation of the store,
+// especially that concurrent updates don't get lost.
+func TestUpdateFunc(store gokv.Store, t *testing.T) {
+	appendFn := func(s string) func(current any) (any, error) {
+		return func(current any) (any, error) {
+			return append(current.([]string), s), nil
+		}
+	}
+
+	// Missing key
+	err := store.Delete("updatefunc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := gokv.UpdateFunc(store, "updatefunc", []string{}, appendFn("initial"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := deep.Equal(result, []string{"initial"}); diff != nil {
+		t.Error(diff)
+	}
+
+	// Concurrent first updates of a missing key must not get lost either
+	err = store.Delete("updatefunc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goroutineCount := 20
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		go func(i int) {
+			defer waitGroup.Done()
+			_, err := gokv.UpdateFunc(store, "updatefunc", []string{}, appendFn(strconv.Itoa(i)))
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+	var values []string
+	_, err = store.Get("updatefunc", &values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(values) != goroutineCount {
+		t.Errorf("Expected %v values, but was %v: %v", goroutineCount, len(values), values)
+	}
+
+	// Concurrent updates must not get lost
+	err = store.Delete("updatefunc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = gokv.UpdateFunc(store, "updatefunc", []string{}, appendFn("initial"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitGroup = sync.WaitGroup{}
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		go func(i int) {
+			defer waitGroup.Done()
+			_, err := gokv.UpdateFunc(store, "updatefunc", []string{}, appendFn(strconv.Itoa(i)))
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	waitGroup.Wait()
+
+	var actual []string
+	found, err := store.Get("updatefunc", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Fatal("No value was found, but should have been")
+	}
+	expected := []string{"initial"}
+	for i := 0; i < goroutineCount; i++ {
+		expected = append(expected, strconv.Itoa(i))
+	}
+	if len(actual) != len(expected) {
+		t.Fatalf("Expected %v values, but was %v: %v", len(expected), len(actual), actual)
+	}
+	sort.Strings(expected[1:])
+	sort.Strings(actual[1:])
+	if diff := deep.Equal(actual, expected); diff != nil {
+		t.Error(diff)
+	}
+
+	// An error of the update function is returned and nothing is stored
+	updateErr := errors.New("update error")
+	_, err = gokv.UpdateFunc(store, "updatefunc", []string{}, func(current any) (any, error) {
+		return nil, updateErr
+	})
+	if err != updateErr {
+		t.Errorf("Expected %v, but was %v", updateErr, err)
+	}
+}
+
+// TestCounterStore tests if the gokv.CounterStore implementation of the store works properly.
+func TestCounterStore(store gokv.Store, t *testing.T) {
+	counterStore, ok := store.(gokv.CounterStore)
+	if !ok {
+		t.Fatal("The store doesn't implement gokv.CounterStore")
+	}
+
+	// Missing key
+	err := store.Delete("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := counterStore.Increment("counter", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 0 {
+		t.Errorf("Expected a non-existing counter to start at 0, but was %v", count)
+	}
+
+	// Concurrent increments must not get lost
+	goroutineCount := 1000
+	waitGroup := sync.WaitGroup{}
+	waitGroup.Add(goroutineCount)
+	for i := 0; i < goroutineCount; i++ {
+		go func() {
+			defer waitGroup.Done()
+			_, err := counterStore.Increment("counter", 1)
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	waitGroup.Wait()
+	count, err = counterStore.Increment("counter", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != int64(goroutineCount) {
+		t.Errorf("Expected %v, but was %v", goroutineCount, count)
+	}
+
+	// Decrement
+	count, err = counterStore.Increment("counter", -int64(goroutineCount)-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != -1 {
+		t.Errorf("Expected %v, but was %v", -1, count)
+	}
+
+	err = store.Delete("counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Non-integer value
+	err = store.Set("not-a-counter", Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = counterStore.Increment("not-a-counter", 1)
+	if !errors.Is(err, gokv.ErrNotACounter) {
+		t.Errorf("Expected gokv.ErrNotACounter, but was %v", err)
+	}
+	err = store.Delete("not-a-counter")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Errors
+	_, err = counterStore.Increment("", 1)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
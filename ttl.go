@@ -0,0 +1,33 @@
+package gokv
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTTLUnsupported is returned by SetWithTTL when the store doesn't implement the TTLStore interface.
+var ErrTTLUnsupported = errors.New("The store doesn't support expiring key-value pairs")
+
+// TTLStore is an optional interface for stores that can let key-value pairs expire.
+// Check for it with a type assertion or use the SetWithTTL function.
+//
+// It's implemented by the badgerdb, dynamodb, freecache, lrucache, mongodb and redis implementations.
+type TTLStore interface {
+	// SetWithTTL stores the given value for the given key, like Set,
+	// but the key-value pair expires after the given TTL.
+	// After that, Get returns (false, nil) for the key.
+	// A following Set without TTL removes the expiry.
+	// The key must not be "", the value must not be nil and the TTL must be positive.
+	SetWithTTL(k string, v any, ttl time.Duration) error
+}
+
+// SetWithTTL stores the given value for the given key via the store if it implements the TTLStore interface,
+// so that the key-value pair expires after the given TTL.
+// Otherwise ErrTTLUnsupported is returned.
+func SetWithTTL(store Store, k string, v any, ttl time.Duration) error {
+	ttlStore, ok := store.(TTLStore)
+	if !ok {
+		return ErrTTLUnsupported
+	}
+	return ttlStore.SetWithTTL(k, v, ttl)
+}
@@ -0,0 +1,111 @@
+package util
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+
+	"github.com/philippgille/gokv"
+)
+
+// CheckKeys returns an error if one of the keys is ""
+func CheckKeys(keys []string) error {
+	for _, k := range keys {
+		if err := CheckKey(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckKeysAndValues returns an error if one of the keys is "" or one of the values is nil
+func CheckKeysAndValues(values map[string]any) error {
+	for k, v := range values {
+		if err := CheckKeyAndValue(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BatchPointers returns the elements of values, which must be a slice with the same length as keys,
+// as pointers to unmarshal the values into.
+// It returns an error if one of the keys is "" or one of the elements is not a non-nil pointer.
+func BatchPointers(keys []string, values any) ([]any, error) {
+	if err := CheckKeys(keys); err != nil {
+		return nil, err
+	}
+	slice := reflect.ValueOf(values)
+	if slice.Kind() != reflect.Slice {
+		return nil, errors.New("The passed values must be a slice")
+	}
+	if slice.Len() != len(keys) {
+		return nil, errors.New("The passed values must have the same length as the keys")
+	}
+
+	result := make([]any, len(keys))
+	for i := range keys {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Pointer || elem.IsNil() {
+			return nil, errors.New("The passed value at index " + strconv.Itoa(i) + " is not a non-nil pointer")
+		}
+		result[i] = elem.Interface()
+	}
+	return result, nil
+}
+
+// WrapBatchStore returns the store as gokv.BatchStore.
+// If the store implements gokv.BatchStore natively, it's returned as is.
+// Otherwise the returned store calls the store's regular methods in a loop.
+func WrapBatchStore(store gokv.Store) gokv.BatchStore {
+	if batchStore, ok := store.(gokv.BatchStore); ok {
+		return batchStore
+	}
+	return batchStoreWrapper{store}
+}
+
+type batchStoreWrapper struct {
+	gokv.Store
+}
+
+func (s batchStoreWrapper) SetMany(values map[string]any) error {
+	if err := CheckKeysAndValues(values); err != nil {
+		return err
+	}
+	for k, v := range values {
+		if err := s.Set(k, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s batchStoreWrapper) GetMany(keys []string, values any) (found []bool, err error) {
+	pointers, err := BatchPointers(keys, values)
+	if err != nil {
+		return nil, err
+	}
+	found = make([]bool, len(keys))
+	for i, k := range keys {
+		found[i], err = s.Get(k, pointers[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return found, nil
+}
+
+func (s batchStoreWrapper) DeleteMany(keys []string) error {
+	if err := CheckKeys(keys); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := s.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
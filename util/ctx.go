@@ -0,0 +1,44 @@
+package util
+
+import (
+	"context"
+
+	"github.com/philippgille/gokv"
+)
+
+// WrapStoreCtx returns the store as gokv.StoreCtx.
+// If the store implements gokv.StoreCtx natively, it's returned as is.
+// Otherwise the returned store checks the context before calling the store's method,
+// so a canceled context or exceeded deadline prevents new operations,
+// but can't abort an operation that's already running.
+func WrapStoreCtx(store gokv.Store) gokv.StoreCtx {
+	if storeCtx, ok := store.(gokv.StoreCtx); ok {
+		return storeCtx
+	}
+	return storeCtxWrapper{store}
+}
+
+type storeCtxWrapper struct {
+	gokv.Store
+}
+
+func (s storeCtxWrapper) SetCtx(ctx context.Context, k string, v any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.Set(k, v)
+}
+
+func (s storeCtxWrapper) GetCtx(ctx context.Context, k string, v any) (found bool, err error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	return s.Get(k, v)
+}
+
+func (s storeCtxWrapper) DeleteCtx(ctx context.Context, k string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.Delete(k)
+}
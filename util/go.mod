@@ -1,3 +1,7 @@
 module github.com/philippgille/gokv/util
 
 go 1.20
+
+require github.com/philippgille/gokv v0.7.0
+
+replace github.com/philippgille/gokv => ../
@@ -0,0 +1,96 @@
+package util
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// MaintenanceStats are statistics about the runs of a Maintenance.
+type MaintenanceStats struct {
+	// Number of finished runs.
+	Runs int
+	// Start time of the last run.
+	LastRun time.Time
+	// Duration of the last run.
+	LastDuration time.Duration
+	// Error of the last run, nil if it succeeded.
+	LastErr error
+}
+
+// Maintenance periodically runs a maintenance function in a goroutine,
+// for example the value log garbage collection of an embedded DB.
+// Create it with NewMaintenance.
+type Maintenance struct {
+	lock  sync.Mutex
+	stop  chan struct{}
+	done  chan struct{}
+	stats MaintenanceStats
+}
+
+// NewMaintenance creates a new Maintenance that's not running yet.
+func NewMaintenance() *Maintenance {
+	return &Maintenance{}
+}
+
+// Start runs fn in a goroutine after each interval until Stop is called.
+// The runs don't overlap, so when a run takes longer than the interval, the next run is delayed.
+// It returns an error if the interval isn't positive or if the maintenance is already running.
+func (m *Maintenance) Start(interval time.Duration, fn func() error) error {
+	if interval <= 0 {
+		return errors.New("The maintenance interval must be positive")
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if m.stop != nil {
+		return errors.New("The maintenance is already running")
+	}
+	m.stop = make(chan struct{})
+	m.done = make(chan struct{})
+
+	go m.run(interval, fn, m.stop, m.done)
+	return nil
+}
+
+func (m *Maintenance) run(interval time.Duration, fn func() error, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			start := time.Now()
+			err := fn()
+			m.lock.Lock()
+			m.stats.Runs++
+			m.stats.LastRun = start
+			m.stats.LastDuration = time.Since(start)
+			m.stats.LastErr = err
+			m.lock.Unlock()
+		}
+	}
+}
+
+// Stop stops the maintenance and waits until a currently running run has finished.
+// Stopping a maintenance that's not running doesn't have any effect.
+func (m *Maintenance) Stop() {
+	m.lock.Lock()
+	stop, done := m.stop, m.done
+	m.stop, m.done = nil, nil
+	m.lock.Unlock()
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+// Stats returns the statistics about the runs so far.
+func (m *Maintenance) Stats() MaintenanceStats {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.stats
+}
@@ -2,6 +2,7 @@ package util
 
 import (
 	"errors"
+	"time"
 )
 
 // CheckKeyAndValue returns an error if k == "" or if v == nil
@@ -27,3 +28,11 @@ func CheckVal(v any) error {
 	}
 	return nil
 }
+
+// CheckTTL returns an error if ttl <= 0
+func CheckTTL(ttl time.Duration) error {
+	if ttl <= 0 {
+		return errors.New("The passed TTL isn't positive, which is invalid")
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+/*
+Package wal contains a `gokv.Store` wrapper that acknowledges writes as soon as they're persisted in a local write-ahead log (WAL)
+and asynchronously replays them into a durable (for example remote) store.
+*/
+package wal
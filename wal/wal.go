@@ -0,0 +1,360 @@
+package wal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/util"
+)
+
+const (
+	opSet    = "set"
+	opDelete = "delete"
+
+	entryExtension = ".json"
+	tempExtension  = ".tmp"
+)
+
+// retryInterval is the interval in which the replay is retried
+// after the durable store returned an error.
+var retryInterval = time.Second
+
+// entry is a WAL entry. There's at most one entry per key,
+// because only the last mutation of a key has to be replayed.
+type entry struct {
+	Key string `json:"key"`
+	// Seq identifies the mutation, so that an entry is only removed
+	// when no newer mutation happened while it was replayed.
+	Seq   uint64          `json:"seq"`
+	Op    string          `json:"op"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+// Store is a gokv.Store implementation that writes all mutations to a local write-ahead log (WAL)
+// and replays them asynchronously into a durable store.
+// Set and Delete return as soon as the mutation is persisted in the WAL.
+// Entries are removed from the WAL once the durable store confirmed them.
+//
+// Reads of keys with pending mutations are answered from the WAL,
+// so a client always reads its own writes.
+// The order of mutations is only preserved per key, because for each key only the last mutation is replayed.
+// Replaying is idempotent, so an entry that was replayed before a crash,
+// but not removed from the WAL yet, is just written again.
+type Store struct {
+	durable gokv.Store
+	dir     string
+	// lock guards pending, seq and the WAL files.
+	lock    *sync.Mutex
+	pending map[string]entry
+	seq     *uint64
+	// replayLock prevents concurrent replays by the background goroutine and Flush().
+	replayLock *sync.Mutex
+	notify     chan struct{}
+	done       chan struct{}
+	wg         *sync.WaitGroup
+	closeOnce  *sync.Once
+}
+
+// Set persists the given value for the given key in the WAL.
+// Values are marshalled to JSON.
+// The key must not be "" and the value must not be nil.
+func (s Store) Set(k string, v any) error {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.write(k, opSet, data)
+}
+
+// Get retrieves the stored value for the given key.
+// If there's a pending mutation for the key in the WAL, the value is taken from there,
+// otherwise it's retrieved from the durable store.
+// If no value is found it returns (false, nil).
+// The key must not be "" and the pointer must not be nil.
+func (s Store) Get(k string, v any) (found bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	s.lock.Lock()
+	e, ok := s.pending[k]
+	s.lock.Unlock()
+	if !ok {
+		return s.durable.Get(k, v)
+	}
+	if e.Op == opDelete {
+		return false, nil
+	}
+	return true, json.Unmarshal(e.Value, v)
+}
+
+// Delete persists the deletion of the given key in the WAL.
+// Deleting a non-existing key-value pair does NOT lead to an error.
+// The key must not be "".
+func (s Store) Delete(k string) error {
+	if err := util.CheckKey(k); err != nil {
+		return err
+	}
+
+	return s.write(k, opDelete, nil)
+}
+
+// Flush synchronously replays all pending WAL entries into the durable store.
+// It returns the first error that the durable store returned.
+// Entries that couldn't be replayed remain in the WAL.
+func (s Store) Flush() error {
+	s.replayLock.Lock()
+	defer s.replayLock.Unlock()
+
+	s.lock.Lock()
+	entries := make([]entry, 0, len(s.pending))
+	for _, e := range s.pending {
+		entries = append(entries, e)
+	}
+	s.lock.Unlock()
+
+	var firstErr error
+	for _, e := range entries {
+		var err error
+		if e.Op == opDelete {
+			err = s.durable.Delete(e.Key)
+		} else {
+			// The value is already marshalled to JSON, which a json.RawMessage preserves
+			// when the durable store marshals it to JSON again.
+			err = s.durable.Set(e.Key, e.Value)
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		if err = s.confirm(e); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Close stops the background replay, makes a last attempt to replay all pending entries
+// and then closes the durable store.
+// Entries that couldn't be replayed remain in the WAL and are replayed when a store
+// is created for the same WAL path again.
+// Calling Close multiple times is safe, the calls after the first one don't do anything.
+func (s Store) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		close(s.done)
+		s.wg.Wait()
+		flushErr := s.Flush()
+		err = errors.Join(flushErr, s.durable.Close())
+	})
+	return err
+}
+
+// write persists the mutation in the WAL and triggers the background replay.
+func (s Store) write(k, op string, data []byte) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	*s.seq++
+	e := entry{
+		Key:   k,
+		Seq:   *s.seq,
+		Op:    op,
+		Value: data,
+	}
+	if err := writeEntry(s.dir, e); err != nil {
+		return err
+	}
+	s.pending[k] = e
+
+	// Non-blocking, because a pending notification already leads to a replay of this entry.
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// confirm removes the entry from the WAL, unless there was a newer mutation of the key in the meantime.
+func (s Store) confirm(e entry) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if current, ok := s.pending[e.Key]; !ok || current.Seq != e.Seq {
+		return nil
+	}
+	err := os.Remove(entryPath(s.dir, e.Key))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	delete(s.pending, e.Key)
+	return nil
+}
+
+// replay replays pending entries in the background until the store is closed.
+func (s Store) replay() {
+	defer s.wg.Done()
+
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.notify:
+		case <-retry:
+		}
+		retry = nil
+		if err := s.Flush(); err != nil {
+			retry = time.After(retryInterval)
+		}
+	}
+}
+
+// NewStore creates a new WAL store that replays the mutations into the given durable store.
+// walPath is the directory in which the WAL entries are stored. It's created if it doesn't exist yet.
+// Unconfirmed entries from a previous run (for example before a crash) are replayed in the background.
+//
+// The values are marshalled to JSON before they're written to the WAL
+// and the durable store receives them as json.RawMessage,
+// so the durable store must use encoding.JSON as codec.
+//
+// You must call the Close() method on the store when you're done working with it.
+func NewStore(durable gokv.Store, walPath string) (Store, error) {
+	result := Store{}
+
+	// Precondition check
+	if durable == nil {
+		return result, errors.New("The durable store must not be nil")
+	}
+	if walPath == "" {
+		return result, errors.New("The walPath must not be empty")
+	}
+
+	if err := os.MkdirAll(walPath, 0700); err != nil {
+		return result, err
+	}
+	pending, seq, err := readEntries(walPath)
+	if err != nil {
+		return result, err
+	}
+
+	result.durable = durable
+	result.dir = walPath
+	result.lock = &sync.Mutex{}
+	result.pending = pending
+	result.seq = &seq
+	result.replayLock = &sync.Mutex{}
+	result.notify = make(chan struct{}, 1)
+	result.done = make(chan struct{})
+	result.wg = &sync.WaitGroup{}
+	result.closeOnce = &sync.Once{}
+
+	result.wg.Add(1)
+	go result.replay()
+	if len(pending) > 0 {
+		result.notify <- struct{}{}
+	}
+
+	return result, nil
+}
+
+// entryPath returns the path of the WAL file for the given key.
+// The key is hashed, because it can contain characters that aren't allowed in file names
+// and can be longer than the maximum file name length.
+func entryPath(dir, k string) string {
+	hash := sha256.Sum256([]byte(k))
+	return filepath.Join(dir, hex.EncodeToString(hash[:])+entryExtension)
+}
+
+// writeEntry atomically writes the entry to its WAL file.
+// The data is synced to disk before the file is renamed, so after a crash
+// there's either the old or the new entry, but never a partially written one.
+func writeEntry(dir string, e entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, "*"+tempExtension)
+	if err != nil {
+		return err
+	}
+	tempPath := f.Name()
+	if _, err = f.Write(data); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tempPath, entryPath(dir, e.Key))
+	}
+	if err != nil {
+		_ = os.Remove(tempPath)
+		return err
+	}
+
+	// Make sure the rename itself is persisted
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+// readEntries reads all entries from the WAL directory and removes leftover temporary files.
+// It returns the entries by key and the highest sequence number.
+func readEntries(dir string) (map[string]entry, uint64, error) {
+	result := make(map[string]entry)
+	var maxSeq uint64
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, dirEntry := range dirEntries {
+		if dirEntry.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, dirEntry.Name())
+		if strings.HasSuffix(dirEntry.Name(), tempExtension) {
+			// A write that was interrupted before it was acknowledged
+			if err = os.Remove(path); err != nil {
+				return nil, 0, err
+			}
+			continue
+		}
+		if !strings.HasSuffix(dirEntry.Name(), entryExtension) {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, 0, err
+		}
+		var e entry
+		if err = json.Unmarshal(data, &e); err != nil {
+			return nil, 0, err
+		}
+		result[e.Key] = e
+		if e.Seq > maxSeq {
+			maxSeq = e.Seq
+		}
+	}
+	return result, maxSeq, nil
+}
@@ -0,0 +1,276 @@
+package wal_test
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/philippgille/gokv"
+	"github.com/philippgille/gokv/gomap"
+	"github.com/philippgille/gokv/test"
+	"github.com/philippgille/gokv/wal"
+)
+
+// TestStore tests if reading from, writing to and deleting from the store works properly.
+// A struct is used as value. See TestTypes() for a test that is simpler but tests all types.
+func TestStore(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), t.TempDir())
+	test.TestStore(store, t)
+}
+
+// TestTypes tests if setting and getting values works with all Go types.
+func TestTypes(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), t.TempDir())
+	test.TestTypes(store, t)
+}
+
+// TestStoreConcurrent launches a bunch of goroutines that concurrently work with one store.
+func TestStoreConcurrent(t *testing.T) {
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), t.TempDir())
+
+	goroutineCount := 100
+
+	test.TestConcurrentInteractions(t, goroutineCount, store)
+}
+
+// TestReplay tests that mutations are replayed into the durable store.
+func TestReplay(t *testing.T) {
+	durable := gomap.NewStore(gomap.DefaultOptions)
+	err := durable.Set("bar", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	walPath := t.TempDir()
+	store := createStore(t, durable, walPath)
+
+	err = store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Delete("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectDurable(t, durable, "foo", true)
+	expectDurable(t, durable, "bar", false)
+	expectEmptyWAL(t, walPath)
+}
+
+// TestReadYourWrites tests that pending mutations are visible before they were replayed.
+func TestReadYourWrites(t *testing.T) {
+	durable := newFlakyStore()
+	durable.fail.Store(true)
+	err := durable.Store.Set("bar", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := createStore(t, durable, t.TempDir())
+
+	err = store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := test.Foo{}
+	found, err := store.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+	if actual.Bar != "baz" {
+		t.Errorf("Expected %q, but was %q", "baz", actual.Bar)
+	}
+
+	err = store.Delete("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	found, err = store.Get("bar", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found {
+		t.Error("A value was found, but no value was expected")
+	}
+
+	expectDurable(t, durable, "foo", false)
+	expectDurable(t, durable, "bar", true)
+}
+
+// TestCrashRecovery tests that mutations that weren't replayed before the store went away
+// are replayed when a store is created for the same WAL path again.
+func TestCrashRecovery(t *testing.T) {
+	walPath := t.TempDir()
+
+	// The durable store is unavailable, so nothing can be replayed
+	unavailable := newFlakyStore()
+	unavailable.fail.Store(true)
+	store, err := wal.NewStore(unavailable, walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Set("foo", test.Foo{Bar: "qux"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Delete("bar")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Leftover of a write that didn't complete
+	err = os.WriteFile(walPath+"/123.tmp", []byte("{"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Close()
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	durable := gomap.NewStore(gomap.DefaultOptions)
+	err = durable.Set("bar", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	store = createStore(t, durable, walPath)
+	err = store.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := test.Foo{}
+	found, err := durable.Get("foo", &actual)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+	if actual.Bar != "qux" {
+		t.Errorf("Expected %q, but was %q", "qux", actual.Bar)
+	}
+	expectDurable(t, durable, "bar", false)
+	expectEmptyWAL(t, walPath)
+}
+
+// TestClose tests that Close replays the pending mutations and can be called multiple times.
+func TestClose(t *testing.T) {
+	durable := gomap.NewStore(gomap.DefaultOptions)
+	store := createStore(t, durable, t.TempDir())
+
+	err := store.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expectDurable(t, durable, "foo", true)
+
+	// For example with `defer store.Close()` and an explicit call
+	err = store.Close()
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// TestErrors tests some error cases.
+func TestErrors(t *testing.T) {
+	_, err := wal.NewStore(nil, t.TempDir())
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = wal.NewStore(gomap.NewStore(gomap.DefaultOptions), "")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+
+	// Test empty key
+	store := createStore(t, gomap.NewStore(gomap.DefaultOptions), t.TempDir())
+	err = store.Set("", "bar")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	_, err = store.Get("", new(string))
+	if err == nil {
+		t.Error("Expected an error")
+	}
+	err = store.Delete("")
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// flakyStore is a gokv.Store whose mutations fail while fail is true.
+type flakyStore struct {
+	gokv.Store
+	fail *atomic.Bool
+}
+
+func newFlakyStore() flakyStore {
+	return flakyStore{
+		Store: gomap.NewStore(gomap.DefaultOptions),
+		fail:  &atomic.Bool{},
+	}
+}
+
+func (s flakyStore) Set(k string, v any) error {
+	if s.fail.Load() {
+		return errors.New("unavailable")
+	}
+	return s.Store.Set(k, v)
+}
+
+func (s flakyStore) Delete(k string) error {
+	if s.fail.Load() {
+		return errors.New("unavailable")
+	}
+	return s.Store.Delete(k)
+}
+
+func expectDurable(t *testing.T, durable gokv.Store, k string, expected bool) {
+	t.Helper()
+
+	found, err := durable.Get(k, new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != expected {
+		t.Errorf("Expected the key %q to be found in the durable store: %v, but was: %v", k, expected, found)
+	}
+}
+
+func expectEmptyWAL(t *testing.T, walPath string) {
+	t.Helper()
+
+	dirEntries, err := os.ReadDir(walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dirEntries) != 0 {
+		t.Errorf("Expected the WAL to be empty, but it contains %v entries", len(dirEntries))
+	}
+}
+
+func createStore(t *testing.T, durable gokv.Store, walPath string) wal.Store {
+	store, err := wal.NewStore(durable, walPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+	return store
+}
@@ -0,0 +1,79 @@
+package zookeeper
+
+import (
+	"bytes"
+
+	"github.com/samuel/go-zookeeper/zk"
+
+	"github.com/philippgille/gokv/util"
+)
+
+// CompareAndSwap stores the new value for the given key if the stored value is equal to the old value.
+// The new value is only set if the node's version is still the one of the compared value,
+// so the comparison and replacement are atomic.
+// If the node was changed in the meantime, it returns (false, nil), even if the value was changed back.
+// The values are compared in their marshalled form.
+// If the key doesn't exist or holds a different value, it returns (false, nil).
+// The key must not be "" and the values must not be nil.
+func (c Client) CompareAndSwap(k string, old, new any) (swapped bool, err error) {
+	if err := util.CheckKeyAndValue(k, old); err != nil {
+		return false, err
+	}
+	if err := util.CheckVal(new); err != nil {
+		return false, err
+	}
+
+	oldData, err := c.codec.Marshal(old)
+	if err != nil {
+		return false, err
+	}
+	newData, err := c.codec.Marshal(new)
+	if err != nil {
+		return false, err
+	}
+
+	k = c.pathPrefix + k
+	data, stat, err := c.c.Get(k)
+	if err != nil {
+		if err == zk.ErrNoNode {
+			return false, nil
+		}
+		return false, err
+	}
+	if !bytes.Equal(data, oldData) {
+		return false, nil
+	}
+	_, err = c.c.Set(k, newData, stat.Version)
+	if err != nil {
+		if err == zk.ErrBadVersion || err == zk.ErrNoNode {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SetIfAbsent stores the given value for the given key if the key doesn't exist yet.
+// Creating a node fails if it already exists, so the existence check and the write are atomic.
+// If the key already exists, it returns (false, nil).
+// The key must not be "" and the value must not be nil.
+func (c Client) SetIfAbsent(k string, v any) (set bool, err error) {
+	if err := util.CheckKeyAndValue(k, v); err != nil {
+		return false, err
+	}
+
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return false, err
+	}
+
+	k = c.pathPrefix + k
+	_, err = c.c.Create(k, data, 0, c.acl)
+	if err != nil {
+		if err == zk.ErrNodeExists {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
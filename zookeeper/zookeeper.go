@@ -7,6 +7,7 @@ import (
 
 	"github.com/samuel/go-zookeeper/zk"
 
+	"github.com/philippgille/gokv"
 	"github.com/philippgille/gokv/encoding"
 	"github.com/philippgille/gokv/util"
 )
@@ -15,6 +16,7 @@ import (
 type Client struct {
 	c          *zk.Conn
 	pathPrefix string
+	acl        []zk.ACL
 	codec      encoding.Codec
 }
 
@@ -33,8 +35,7 @@ func (c Client) Set(k string, v any) error {
 	}
 
 	k = c.pathPrefix + k
-	acl := zk.WorldACL(zk.PermAll)
-	_, err = c.c.Create(k, data, 0, acl)
+	_, err = c.c.Create(k, data, 0, c.acl)
 	if err != nil {
 		if err.Error() == "zk: node already exists" {
 			_, err = c.c.Set(k, data, -1)
@@ -82,6 +83,11 @@ func (c Client) Delete(k string) error {
 	return err
 }
 
+// Kind returns gokv.KindZooKeeper.
+func (c Client) Kind() gokv.Kind {
+	return gokv.KindZooKeeper
+}
+
 // Close closes the client.
 // It must be called to close the underlying ZooKeeper client.
 func (c Client) Close() error {
@@ -100,17 +106,33 @@ type Options struct {
 	// Begin and end with "/" to use as "directory".
 	// Optional ("/gokv/" by default).
 	PathPrefix string
+	// Authentication scheme, for example "digest".
+	// The AuthCredential is added for this scheme to the session.
+	// Optional ("" by default, meaning no authentication).
+	AuthScheme string
+	// Credential for the AuthScheme, for example "user:password" for the "digest" scheme.
+	// Required if AuthScheme is set.
+	AuthCredential string
+	// ACL for the nodes that the client creates, both for the key-value pairs and for the PathPrefix.
+	// With the default, any client that can connect to the ZooKeeper ensemble can read, change and delete the nodes,
+	// so in a shared ensemble you should use a restrictive ACL,
+	// for example zk.DigestACL(zk.PermAll, "user", "password") together with the "digest" AuthScheme.
+	// The ACL of a node is set when it's created, so it doesn't change for existing nodes.
+	// Optional (zk.WorldACL(zk.PermAll) by default).
+	ACL []zk.ACL
 	// Encoding format.
 	// Optional (encoding.JSON by default).
 	Codec encoding.Codec
 }
 
 // DefaultOptions is an Options object with default values.
-// Servers: "localhost:2181", PathPrefix: "/gokv/", Codec: encoding.JSON
+// Servers: "localhost:2181", PathPrefix: "/gokv/", AuthScheme: "", AuthCredential: "", ACL: zk.WorldACL(zk.PermAll), Codec: encoding.JSON
 var DefaultOptions = Options{
 	Servers:    []string{"localhost:2181"},
 	PathPrefix: "/gokv/",
+	ACL:        zk.WorldACL(zk.PermAll),
 	Codec:      encoding.JSON,
+	// No need to set AuthScheme or AuthCredential because their zero values are fine.
 }
 
 // NewClient creates a new Apache ZooKeeper client.
@@ -121,7 +143,14 @@ func NewClient(options Options) (Client, error) {
 
 	// Precondition check
 	if options.PathPrefix != "" && !strings.HasPrefix(options.PathPrefix, "/") {
-		return result, errors.New("The PathPrefix must start with a \\")
+		return result, errors.New("The PathPrefix must start with a /")
+	}
+	// An empty path element would lead to an invalid node path
+	if strings.Contains(options.PathPrefix, "//") {
+		return result, errors.New("Invalid PathPrefix containing \"//\"")
+	}
+	if (options.AuthScheme == "") != (options.AuthCredential == "") {
+		return result, errors.New("The AuthScheme and AuthCredential must either both be set or both be empty")
 	}
 
 	// Set default values
@@ -131,6 +160,9 @@ func NewClient(options Options) (Client, error) {
 	if options.PathPrefix == "" {
 		options.PathPrefix = DefaultOptions.PathPrefix
 	}
+	if options.ACL == nil {
+		options.ACL = DefaultOptions.ACL
+	}
 	if options.Codec == nil {
 		options.Codec = DefaultOptions.Codec
 	}
@@ -140,56 +172,67 @@ func NewClient(options Options) (Client, error) {
 		return result, err
 	}
 
+	if options.AuthScheme != "" {
+		err = c.AddAuth(options.AuthScheme, []byte(options.AuthCredential))
+		if err != nil {
+			c.Close()
+			return result, err
+		}
+	}
+
 	// Check connection
 	_, _, err = c.Children("/")
 	if err != nil {
+		c.Close()
 		return result, err
 	}
 
-	// Create node if it doesn't exist (or even multiple nodes).
-	// Doesn't need to be done if the PathPrefix is "/".
-	if options.PathPrefix != "/" {
-		// Split examples:
-		// "/foo" splits to ["", "foo"]
-		// "/foo/" splits to ["", "foo", ""]
-		// "/foo/bar" splits to ["", "foo", "bar"]
-		baseNodes := strings.Split(options.PathPrefix, "/")
-		// Don't care about the first element
-		baseNodes = baseNodes[1:]
-		// No need to create any nodes if length == 1 (at this point, after removing the first one),
-		// because that's e.g. "/foo", which is no extra node, just a key prefix.
-		if len(baseNodes) > 1 {
-			// Also remove the last element, because there are two cases and we don't care about any of them:
-			// 1) If it's "" it's not a new node
-			// 2) If it's not "" it's just a prefix for a key
-			baseNodes = baseNodes[:len(baseNodes)-1]
-			nodeToCreate := "/"
-			acl := zk.WorldACL(zk.PermAll)
-			for _, pathElem := range baseNodes {
-				// No path elem should be empty, because that would mean a PathPrefix containing "//" was used
-				if pathElem == "" {
-					return result, errors.New("Invalid PathPrefix containing \"//\"")
-				}
-				nodeToCreate += pathElem
-				_, _, err = c.Get(nodeToCreate)
-				if err != nil {
-					if err.Error() == "zk: node does not exist" {
-						_, err = c.Create(nodeToCreate, nil, 0, acl)
-						if err != nil {
-							return result, err
-						}
-					} else {
-						return result, err
-					}
-				}
-				nodeToCreate += "/"
-			}
-		}
+	// Create the nodes of the PathPrefix if they don't exist
+	err = createNodes(c, baseNodes(options.PathPrefix), options.ACL)
+	if err != nil {
+		c.Close()
+		return result, err
 	}
 
 	result.c = c
 	result.pathPrefix = options.PathPrefix
+	result.acl = options.ACL
 	result.codec = options.Codec
 
 	return result, nil
 }
+
+// baseNodes returns the paths of the nodes that must exist for the PathPrefix, from the top down.
+// The last path element of the prefix is only a node if the prefix ends with "/",
+// otherwise it's a prefix of the keys. For example:
+// "/" and "/foo" don't require any nodes,
+// "/foo/" requires "/foo",
+// "/foo/bar/baz" requires "/foo" and "/foo/bar".
+func baseNodes(pathPrefix string) []string {
+	var result []string
+	for i := 1; i < len(pathPrefix); i++ {
+		if pathPrefix[i] == '/' {
+			result = append(result, pathPrefix[:i])
+		}
+	}
+	return result
+}
+
+// createNodes creates the nodes in the given order if they don't exist yet.
+// A node that's created concurrently by another client isn't an error.
+func createNodes(c *zk.Conn, nodes []string, acl []zk.ACL) error {
+	for _, node := range nodes {
+		exists, _, err := c.Exists(node)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		_, err = c.Create(node, nil, 0, acl)
+		if err != nil && err != zk.ErrNodeExists {
+			return err
+		}
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package zookeeper_test
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -48,6 +49,23 @@ func TestTypes(t *testing.T) {
 	})
 }
 
+// TestEmptyValues tests if empty values are found after they're stored.
+func TestEmptyValues(t *testing.T) {
+	// Test with JSON
+	t.Run("JSON", func(t *testing.T) {
+		client := createClient(t, encoding.JSON)
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+
+	// Test with a codec that stores empty values as zero bytes
+	t.Run("bytes", func(t *testing.T) {
+		client := createClient(t, test.BytesCodec{})
+		defer client.Close()
+		test.TestEmptyValues(client, t)
+	})
+}
+
 // TestClientConcurrent launches a bunch of goroutines that concurrently work with the Apache ZooKeeper client.
 func TestClientConcurrent(t *testing.T) {
 	client := createClient(t, encoding.JSON)
@@ -58,6 +76,27 @@ func TestClientConcurrent(t *testing.T) {
 	test.TestConcurrentInteractions(t, goroutineCount, client)
 }
 
+// TestCompareAndSwap tests that a value is only replaced when it holds the expected value.
+func TestCompareAndSwap(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestCASStore(client, t)
+}
+
+// TestSetIfAbsent tests that a value is only stored when the key doesn't exist yet.
+func TestSetIfAbsent(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestConditionalSetter(client, t)
+}
+
+// TestUpdateFunc tests if concurrent updates via gokv.UpdateFunc don't get lost.
+func TestUpdateFunc(t *testing.T) {
+	client := createClient(t, encoding.JSON)
+	defer client.Close()
+	test.TestUpdateFunc(client, t)
+}
+
 // TestErrors tests some error cases.
 func TestErrors(t *testing.T) {
 	// Test empty key
@@ -81,9 +120,89 @@ func TestErrors(t *testing.T) {
 		PathPrefix: "foo",
 	}
 	_, err = zookeeper.NewClient(options)
-	if err == nil || strings.HasPrefix(err.Error(), "The PathPrefix must start with a \\") == false {
+	if err == nil || strings.HasPrefix(err.Error(), "The PathPrefix must start with a /") == false {
 		t.Error("Either no or the wrong error was returned")
 	}
+	options.PathPrefix = "/foo//bar/"
+	_, err = zookeeper.NewClient(options)
+	if err == nil {
+		t.Error("Expected an error")
+	}
+}
+
+// TestPathPrefix tests if the nodes of different PathPrefix values are created,
+// also when they're nested or already exist.
+func TestPathPrefix(t *testing.T) {
+	for _, pathPrefix := range []string{"/gokv/", "/a/b/c/", "/single"} {
+		t.Run(pathPrefix, func(t *testing.T) {
+			// Creating the client a second time must work with the existing nodes
+			for i := 0; i < 2; i++ {
+				options := zookeeper.Options{
+					PathPrefix: pathPrefix,
+				}
+				client, err := zookeeper.NewClient(options)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer client.Close()
+				test.TestStore(client, t)
+			}
+		})
+	}
+}
+
+// TestACL tests that nodes that are created with a digest ACL can't be accessed by a client without authentication.
+func TestACL(t *testing.T) {
+	// A key prefix instead of a "directory", so the client without authentication
+	// doesn't fail to access the base node already
+	options := zookeeper.Options{
+		PathPrefix:     "/gokv-acl-",
+		AuthScheme:     "digest",
+		AuthCredential: "user:password",
+		ACL:            zk.DigestACL(zk.PermAll, "user", "password"),
+	}
+	client, err := zookeeper.NewClient(options)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	err = client.Set("foo", test.Foo{Bar: "baz"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Delete("foo")
+
+	found, err := client.Get("foo", new(test.Foo))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found {
+		t.Error("No value was found, but should have been")
+	}
+
+	unauthenticatedClient, err := zookeeper.NewClient(zookeeper.Options{
+		PathPrefix: options.PathPrefix,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer unauthenticatedClient.Close()
+	_, err = unauthenticatedClient.Get("foo", new(test.Foo))
+	if !errors.Is(err, zk.ErrNoAuth) {
+		t.Errorf("Expected zk.ErrNoAuth, but was %v", err)
+	}
+	err = unauthenticatedClient.Set("foo", test.Foo{Bar: "qux"})
+	if !errors.Is(err, zk.ErrNoAuth) {
+		t.Errorf("Expected zk.ErrNoAuth, but was %v", err)
+	}
+
+	// Scheme and credential must be set together
+	_, err = zookeeper.NewClient(zookeeper.Options{
+		AuthScheme: "digest",
+	})
+	if err == nil {
+		t.Error("Expected an error")
+	}
 }
 
 // TestNil tests the behaviour when passing nil or pointers to nil values to some methods.